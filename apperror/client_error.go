@@ -39,6 +39,10 @@ func (e *ClientError) Conflict() error {
 	e.httpCode = http.StatusConflict
 	return e
 }
+func (e *ClientError) TooManyRequests() error {
+	e.httpCode = http.StatusTooManyRequests
+	return e
+}
 func NewClientError(err error) *ClientError {
 	httpCode := http.StatusBadRequest
 
@@ -0,0 +1,12 @@
+package apperror
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+func NewTooManyAttemptsError(retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	return NewClientError(fmt.Errorf("too many attempts, try again in %d seconds", seconds)).TooManyRequests()
+}
@@ -1,6 +1,7 @@
 package appjwt
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"time"
@@ -8,47 +9,86 @@ import (
 	"github.com/night1010/everhealth/config"
 	"github.com/night1010/everhealth/entity"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	purposeChallenge        = "two_factor_challenge"
+	challengeExpiryDuration = 5 * time.Minute
+)
+
+var (
+	ErrRevokedToken   = errors.New("token has been revoked")
+	ErrInvalidPurpose = errors.New("invalid token purpose")
 )
 
 type CustomClaims struct {
 	jwt.RegisteredClaims
-	Id     uint          `json:"id"`
-	RoleId entity.RoleId `json:"role_id"`
+	Id           uint          `json:"id"`
+	RoleId       entity.RoleId `json:"role_id"`
+	TokenVersion int           `json:"token_version"`
+	Purpose      string        `json:"purpose,omitempty"`
+}
+
+type TokenVersionFinder interface {
+	FindTokenVersion(ctx context.Context, userId uint) (int, error)
 }
 
 type Jwt interface {
 	GenerateToken(user *entity.User) (string, error)
-	ValidateToken(tokenString string) (*entity.User, error)
+	ValidateToken(ctx context.Context, tokenString string) (*entity.User, error)
+	GenerateChallengeToken(user *entity.User) (string, error)
+	ValidateChallengeToken(ctx context.Context, tokenString string) (*entity.User, error)
 }
 
 type jwtImpl struct {
-	secretKey []byte
+	secretKey          []byte
+	tokenVersionFinder TokenVersionFinder
 }
 
-func NewJwt() Jwt {
+func NewJwt(tokenVersionFinder TokenVersionFinder) Jwt {
 	jwtConfig := config.NewJwtConfig()
 	return &jwtImpl{
-		secretKey: []byte(jwtConfig.Secret),
+		secretKey:          []byte(jwtConfig.Secret),
+		tokenVersionFinder: tokenVersionFinder,
 	}
 }
 
 func (j *jwtImpl) GenerateToken(user *entity.User) (string, error) {
 	jwtConfig := config.NewJwtConfig()
+	return j.generate(user, jwtConfig.AccessExpiryDuration, "")
+}
+
+func (j *jwtImpl) GenerateChallengeToken(user *entity.User) (string, error) {
+	return j.generate(user, challengeExpiryDuration, purposeChallenge)
+}
+
+func (j *jwtImpl) ValidateToken(ctx context.Context, tokenString string) (*entity.User, error) {
+	return j.validate(ctx, tokenString, "")
+}
+
+func (j *jwtImpl) ValidateChallengeToken(ctx context.Context, tokenString string) (*entity.User, error) {
+	return j.validate(ctx, tokenString, purposeChallenge)
+}
+
+func (j *jwtImpl) generate(user *entity.User, duration time.Duration, purpose string) (string, error) {
 	appConfig := config.NewAppConfig()
 
 	userId := strconv.Itoa(int(user.Id))
-	duration := jwtConfig.ExpiryDuration
 
 	claims := CustomClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    appConfig.Name,
 			Subject:   userId,
+			ID:        uuid.NewString(),
 		},
-		Id:     user.Id,
-		RoleId: user.RoleId,
+		Id:           user.Id,
+		RoleId:       user.RoleId,
+		TokenVersion: user.TokenVersion,
+		Purpose:      purpose,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -56,19 +96,32 @@ func (j *jwtImpl) GenerateToken(user *entity.User) (string, error) {
 	return signedString, err
 }
 
-func (j *jwtImpl) ValidateToken(tokenString string) (*entity.User, error) {
+func (j *jwtImpl) validate(ctx context.Context, tokenString string, purpose string) (*entity.User, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return j.secretKey, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
-	if claims, ok := token.Claims.(*CustomClaims); ok {
-		user := &entity.User{
-			Id:     claims.Id,
-			RoleId: claims.RoleId,
+	claims, ok := token.Claims.(*CustomClaims)
+	if !ok {
+		return nil, errors.New("invalid claims type")
+	}
+	if claims.Purpose != purpose {
+		return nil, ErrInvalidPurpose
+	}
+	if j.tokenVersionFinder != nil {
+		version, err := j.tokenVersionFinder.FindTokenVersion(ctx, claims.Id)
+		if err != nil {
+			return nil, err
+		}
+		if version != claims.TokenVersion {
+			return nil, ErrRevokedToken
 		}
-		return user, nil
 	}
-	return nil, errors.New("invalid claims type")
+	return &entity.User{
+		Id:           claims.Id,
+		RoleId:       claims.RoleId,
+		TokenVersion: claims.TokenVersion,
+	}, nil
 }
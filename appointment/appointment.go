@@ -0,0 +1,105 @@
+package appointment
+
+import (
+	"sort"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+)
+
+const (
+	SlotDuration  = 30 * time.Minute
+	PaymentWindow = 30 * time.Minute
+)
+
+type Slot struct {
+	StartAt time.Time
+	EndAt   time.Time
+}
+
+func (s *Slot) Overlaps(other *Slot) bool {
+	return s.StartAt.Before(other.EndAt) && other.StartAt.Before(s.EndAt)
+}
+
+type Day struct {
+	Date           time.Time
+	Availabilities []*entity.DoctorAvailability
+	Exceptions     []*entity.DoctorAvailabilityException
+	Booked         []*Slot
+	Now            time.Time
+}
+
+func Slots(day *Day) []*Slot {
+	var windows []*Slot
+	var blocked []*Slot
+	dayOff := false
+	for _, exception := range day.Exceptions {
+		if exception.IsAvailable || exception.StartTime != nil {
+			continue
+		}
+		dayOff = true
+	}
+	if !dayOff {
+		for _, availability := range day.Availabilities {
+			if availability.Weekday != day.Date.Weekday() {
+				continue
+			}
+			windows = append(windows, &Slot{StartAt: on(day.Date, availability.StartTime), EndAt: on(day.Date, availability.EndTime)})
+		}
+	}
+	for _, exception := range day.Exceptions {
+		if exception.StartTime == nil || exception.EndTime == nil {
+			continue
+		}
+		window := &Slot{StartAt: on(day.Date, *exception.StartTime), EndAt: on(day.Date, *exception.EndTime)}
+		if exception.IsAvailable {
+			windows = append(windows, window)
+		} else {
+			blocked = append(blocked, window)
+		}
+	}
+	blocked = append(blocked, day.Booked...)
+
+	seen := make(map[time.Time]bool)
+	var slots []*Slot
+	for _, window := range windows {
+		for start := window.StartAt; !start.Add(SlotDuration).After(window.EndAt); start = start.Add(SlotDuration) {
+			slot := &Slot{StartAt: start, EndAt: start.Add(SlotDuration)}
+			if seen[slot.StartAt] || !slot.StartAt.After(day.Now) || overlapsAny(slot, blocked) {
+				continue
+			}
+			seen[slot.StartAt] = true
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].StartAt.Before(slots[j].StartAt)
+	})
+	return slots
+}
+
+func Find(slots []*Slot, startAt time.Time) *Slot {
+	for _, slot := range slots {
+		if slot.StartAt.Equal(startAt) {
+			return slot
+		}
+	}
+	return nil
+}
+
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func on(date time.Time, clock time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, date.Location())
+}
+
+func overlapsAny(slot *Slot, others []*Slot) bool {
+	for _, other := range others {
+		if slot.Overlaps(other) {
+			return true
+		}
+	}
+	return false
+}
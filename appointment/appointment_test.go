@@ -0,0 +1,62 @@
+package appointment_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/night1010/everhealth/appointment"
+	"github.com/night1010/everhealth/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlots(t *testing.T) {
+	clock := func(hour, minute int) time.Time {
+		return time.Date(1, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	date := time.Date(2030, 1, 7, 0, 0, 0, 0, time.Local)
+	availabilities := []*entity.DoctorAvailability{
+		{Weekday: date.Weekday(), StartTime: clock(9, 0), EndTime: clock(10, 30)},
+		{Weekday: date.Weekday() + 1, StartTime: clock(13, 0), EndTime: clock(14, 0)},
+	}
+	now := date.Add(-time.Hour)
+
+	t.Run("should split weekly availability into slots", func(t *testing.T) {
+		slots := appointment.Slots(&appointment.Day{Date: date, Availabilities: availabilities, Now: now})
+
+		assert.Len(t, slots, 3)
+		assert.Equal(t, date.Add(9*time.Hour), slots[0].StartAt)
+		assert.Equal(t, date.Add(10*time.Hour+30*time.Minute), slots[2].EndAt)
+	})
+
+	t.Run("should skip booked and past slots", func(t *testing.T) {
+		booked := []*appointment.Slot{{StartAt: date.Add(9*time.Hour + 30*time.Minute), EndAt: date.Add(10 * time.Hour)}}
+
+		slots := appointment.Slots(&appointment.Day{Date: date, Availabilities: availabilities, Booked: booked, Now: date.Add(9 * time.Hour)})
+
+		assert.Len(t, slots, 1)
+		assert.Equal(t, date.Add(10*time.Hour), slots[0].StartAt)
+	})
+
+	t.Run("should apply exceptions", func(t *testing.T) {
+		start, end := clock(15, 0), clock(16, 0)
+		exceptions := []*entity.DoctorAvailabilityException{
+			{IsAvailable: false},
+			{IsAvailable: true, StartTime: &start, EndTime: &end},
+		}
+
+		slots := appointment.Slots(&appointment.Day{Date: date, Availabilities: availabilities, Exceptions: exceptions, Now: now})
+
+		assert.Len(t, slots, 2)
+		assert.Equal(t, date.Add(15*time.Hour), slots[0].StartAt)
+	})
+
+	t.Run("should block part of the day", func(t *testing.T) {
+		start, end := clock(9, 0), clock(10, 0)
+		exceptions := []*entity.DoctorAvailabilityException{{IsAvailable: false, StartTime: &start, EndTime: &end}}
+
+		slots := appointment.Slots(&appointment.Day{Date: date, Availabilities: availabilities, Exceptions: exceptions, Now: now})
+
+		assert.Len(t, slots, 1)
+		assert.NotNil(t, appointment.Find(slots, date.Add(10*time.Hour)))
+	})
+}
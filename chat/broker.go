@@ -0,0 +1,46 @@
+package chat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/night1010/everhealth/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	MemoryMethod   = "memory"
+	PostgresMethod = "postgres"
+)
+
+var (
+	ErrBrokerClosed = errors.New("broker is closed")
+)
+
+type BrokerMessage struct {
+	Room          uint   `json:"room"`
+	UserId        uint   `json:"user_id,omitempty"`
+	ExcludeClient string `json:"exclude_client,omitempty"`
+	Event         Event  `json:"event"`
+}
+
+type Broker interface {
+	Publish(ctx context.Context, message *BrokerMessage) error
+	Subscribe(handler func(message *BrokerMessage)) error
+	Close() error
+}
+
+func NewBroker(impl string, db *gorm.DB) (Broker, error) {
+	if impl == MemoryMethod {
+		return NewMemoryBroker(), nil
+	}
+	if impl == PostgresMethod {
+		dialector, ok := db.Dialector.(*postgres.Dialector)
+		if !ok {
+			return nil, errors.New("postgres broker requires postgres connection")
+		}
+		return newPostgresBroker(db, dialector.DSN, config.NewChatConfig().Channel), nil
+	}
+	return nil, errors.New("unknown chat broker " + impl)
+}
@@ -2,20 +2,27 @@ package chat
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/logger"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
 var (
 	pongWait     = 10 * time.Second
 	pingInterval = (pongWait * 9) / 10
+	writeWait    = 10 * time.Second
+	egressBuffer = 64
 )
 
 type ClientList map[*Client]bool
 
 type Client struct {
+	id         string
 	connection *websocket.Conn
 	manager    *Manager
 	egress     chan Event
@@ -25,9 +32,10 @@ type Client struct {
 
 func NewClient(conn *websocket.Conn, manager *Manager, chatRoom uint, userId uint) *Client {
 	return &Client{
+		id:         uuid.NewString(),
 		connection: conn,
 		manager:    manager,
-		egress:     make(chan Event),
+		egress:     make(chan Event, egressBuffer),
 		chatRoom:   chatRoom,
 		userId:     userId,
 	}
@@ -71,12 +79,17 @@ func (c *Client) ReadMessages() {
 func (c *Client) WriteMessages() {
 	ticker := time.NewTicker(pingInterval)
 	defer func() {
+		ticker.Stop()
 		c.manager.RemoveClient(c)
 	}()
 
 	for {
 		select {
 		case message, ok := <-c.egress:
+			if err := c.connection.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				logger.Log.Errorf("failed to set write deadline: %v", err)
+				return
+			}
 			if !ok {
 				if err := c.connection.WriteMessage(websocket.CloseMessage, nil); err != nil {
 					logger.Log.Errorf("connection closed: %s", err)
@@ -92,11 +105,50 @@ func (c *Client) WriteMessages() {
 
 			if err := c.connection.WriteMessage(websocket.TextMessage, data); err != nil {
 				logger.Log.Errorf("failed to write message: %s", err)
+				return
 			}
 		case <-ticker.C:
+			if err := c.connection.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				logger.Log.Errorf("failed to set write deadline: %v", err)
+				return
+			}
 			if err := c.connection.WriteMessage(websocket.PingMessage, nil); err != nil {
 				logger.Log.Errorf("failed to ping: %s", err)
+				return
 			}
+			c.manager.refresh(c)
 		}
 	}
 }
+
+func (c *Client) send(event Event) {
+	select {
+	case c.egress <- event:
+	default:
+		logger.Log.Errorf("chat client %s is too slow, closing connection", c.id)
+		go c.manager.RemoveClient(c)
+	}
+}
+
+func (c *Client) sendAck(clientMessageId string, chat *entity.Chat) {
+	data, err := json.Marshal(MessageAckEvent{ClientMessageId: clientMessageId, MessageId: chat.Id, Sent: chat.ChatTime})
+	if err != nil {
+		logger.Log.Errorf("failed to marshal message ack: %v", err)
+		return
+	}
+	c.send(Event{Type: EventMessageAck, Payload: data})
+}
+
+func (c *Client) sendError(clientMessageId string, err error) {
+	message := "failed to send message"
+	var clientErr *apperror.ClientError
+	if errors.As(err, &clientErr) {
+		message = clientErr.Error()
+	}
+	data, err := json.Marshal(MessageErrorEvent{ClientMessageId: clientMessageId, Error: message})
+	if err != nil {
+		logger.Log.Errorf("failed to marshal message error: %v", err)
+		return
+	}
+	c.send(Event{Type: EventMessageError, Payload: data})
+}
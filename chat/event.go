@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/entity"
 )
 
@@ -25,15 +26,56 @@ const (
 	EventSendImage    EventType = "image_message"
 	EventSendPdf      EventType = "pdf_message"
 	EventTypingSignal EventType = "typing_signal"
+	EventQueueUpdate  EventType = "queue_update"
+	EventMessageAck   EventType = "message_ack"
+	EventMessageError EventType = "message_error"
+	EventDelivered    EventType = "message_delivered"
+	EventRead         EventType = "message_read"
+
+	EventPresenceJoin     EventType = "presence_join"
+	EventPresenceLeave    EventType = "presence_leave"
+	EventPresenceSnapshot EventType = "presence_snapshot"
 )
 
 var (
 	ErrEventNotSupported = errors.New("this event type is not supported")
 )
 
+var messageEvents = map[entity.MessageType]EventType{
+	entity.MessageTypeText:  EventSendMessage,
+	entity.MessageTypeImage: EventSendImage,
+	entity.MessageTypePdf:   EventSendPdf,
+}
+
 type SendMessageEvent struct {
-	Message string `json:"message"`
-	From    uint   `json:"from"`
+	ClientMessageId string `json:"client_message_id,omitempty"`
+	Message         string `json:"message"`
+	From            uint   `json:"from"`
+}
+
+type MessageAckEvent struct {
+	ClientMessageId string    `json:"client_message_id,omitempty"`
+	MessageId       uint      `json:"message_id"`
+	Sent            time.Time `json:"sent"`
+}
+
+type MessageErrorEvent struct {
+	ClientMessageId string `json:"client_message_id,omitempty"`
+	Error           string `json:"error"`
+}
+
+type ReceiptEvent struct {
+	UserId    uint `json:"user_id"`
+	MessageId uint `json:"message_id"`
+}
+
+type PresenceEvent struct {
+	UserId     uint       `json:"user_id"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+type PresenceSnapshotEvent struct {
+	UserIds []uint `json:"user_ids"`
 }
 
 type TypingSignalEvent struct {
@@ -41,9 +83,19 @@ type TypingSignalEvent struct {
 	IsTyping bool `json:"is_typing"`
 }
 
+type QueueUpdateEvent struct {
+	DoctorId             uint                           `json:"doctor_id"`
+	Status               entity.TelemedicineQueueStatus `json:"status"`
+	Position             int                            `json:"position"`
+	EstimatedWaitMinutes int                            `json:"estimated_wait_minutes"`
+	TelemedicineId       *uint                          `json:"telemedicine_id,omitempty"`
+}
+
 type NewMessageEvent struct {
+	Id uint `json:"id"`
 	SendMessageEvent
-	Sent time.Time `json:"sent"`
+	MessageType entity.MessageType `json:"message_type"`
+	Sent        time.Time          `json:"sent"`
 }
 
 func SendMessageHandler(event Event, c *Client) error {
@@ -52,38 +104,28 @@ func SendMessageHandler(event Event, c *Client) error {
 		return fmt.Errorf("bad payload in request: %v", err)
 	}
 
-	var broadMessage NewMessageEvent
-
-	broadMessage.Sent = time.Now()
-	broadMessage.Message = chatEvent.Message
-	broadMessage.From = chatEvent.From
-
-	data, err := json.Marshal(broadMessage)
-	if err != nil {
-		return fmt.Errorf("failed to marshal broadcast message: %v", err)
-	}
-
-	var outgoingEvent Event
-	outgoingEvent.Payload = data
-	outgoingEvent.Type = event.Type
-
-	for client := range c.manager.clients {
-		if client.chatRoom == c.chatRoom {
-			client.egress <- outgoingEvent
-		}
-	}
-
 	chatMessage := &entity.Chat{
 		TelemedicineId: c.chatRoom,
 		UserId:         c.userId,
-		ChatTime:       broadMessage.Sent,
+		ChatTime:       time.Now(),
 		Message:        chatEvent.Message,
 		MessageType:    entity.MessageTypeText,
 	}
 
-	_, _ = c.manager.chatUsecase.AddChatMessage(context.Background(), chatMessage)
+	if _, err := c.manager.chatUsecase.AddChatMessage(context.Background(), chatMessage); err != nil {
+		c.sendError(chatEvent.ClientMessageId, err)
+		var clientErr *apperror.ClientError
+		if errors.As(err, &clientErr) {
+			return nil
+		}
+		return err
+	}
+
+	publishErr := c.manager.BroadcastMessage(chatMessage)
 
-	return nil
+	c.sendAck(chatEvent.ClientMessageId, chatMessage)
+
+	return publishErr
 }
 
 func SendTypingSignalHandler(event Event, c *Client) error {
@@ -92,13 +134,35 @@ func SendTypingSignalHandler(event Event, c *Client) error {
 		return fmt.Errorf("bad payload in request: %v", err)
 	}
 
-	for client := range c.manager.clients {
-		if client.chatRoom == c.chatRoom {
-			if client != c {
-				client.egress <- event
-			}
-		}
+	typingEvent.UserId = c.userId
+	data, err := json.Marshal(typingEvent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal typing signal: %v", err)
+	}
+
+	return c.manager.broker.Publish(context.Background(), &BrokerMessage{Room: c.chatRoom, ExcludeClient: c.id, Event: Event{Type: event.Type, Payload: data}})
+}
+
+func ReceiptHandler(event Event, c *Client) error {
+	var receiptEvent ReceiptEvent
+	if err := json.Unmarshal(event.Payload, &receiptEvent); err != nil {
+		return fmt.Errorf("bad payload in request: %v", err)
+	}
+
+	receipt := &entity.ChatReceipt{TelemedicineId: c.chatRoom, UserId: c.userId, DeliveredUpTo: receiptEvent.MessageId}
+	if event.Type == EventRead {
+		receipt.ReadUpTo = receiptEvent.MessageId
+	}
+
+	if _, err := c.manager.chatUsecase.UpdateReceipt(context.Background(), receipt); err != nil {
+		return err
+	}
+
+	receiptEvent.UserId = c.userId
+	data, err := json.Marshal(receiptEvent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal receipt: %v", err)
 	}
 
-	return nil
+	return c.manager.broker.Publish(context.Background(), &BrokerMessage{Room: c.chatRoom, ExcludeClient: c.id, Event: Event{Type: event.Type, Payload: data}})
 }
@@ -1,50 +1,130 @@
 package chat
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/logger"
 	"github.com/night1010/everhealth/usecase"
 )
 
+var inboxBuffer = 1024
+
 type Manager struct {
 	clients ClientList
 	sync.RWMutex
 	handlers    map[EventType]EventHandler
 	chatUsecase usecase.ChatUsecase
+	broker      Broker
+	gracePeriod time.Duration
+	inbox       chan *BrokerMessage
 }
 
-func NewManager(chatUsecase usecase.ChatUsecase) *Manager {
+func NewManager(chatUsecase usecase.ChatUsecase, broker Broker, gracePeriod time.Duration) (*Manager, error) {
 	m := &Manager{
 		clients:     make(ClientList),
 		handlers:    make(map[EventType]EventHandler),
 		chatUsecase: chatUsecase,
+		broker:      broker,
+		gracePeriod: gracePeriod,
+		inbox:       make(chan *BrokerMessage, inboxBuffer),
 	}
 	m.setupEventHandler()
-	return m
+	if err := broker.Subscribe(m.receive); err != nil {
+		return nil, err
+	}
+	go m.run()
+	return m, nil
 }
 
 func (m *Manager) AddClient(client *Client) {
 	m.Lock()
-	defer m.Unlock()
-
 	m.clients[client] = true
+	m.Unlock()
+
+	m.join(client)
 }
 
 func (m *Manager) RemoveClient(client *Client) {
 	m.Lock()
-	defer m.Unlock()
-
-	if _, ok := m.clients[client]; ok {
+	_, ok := m.clients[client]
+	if ok {
 		_ = client.connection.Close()
 		delete(m.clients, client)
 	}
+	m.Unlock()
+
+	if ok {
+		m.leave(client)
+	}
+}
+
+func (m *Manager) BroadcastMessage(chat *entity.Chat) error {
+	var broadMessage NewMessageEvent
+
+	broadMessage.Id = chat.Id
+	broadMessage.Sent = chat.ChatTime
+	broadMessage.Message = chat.Message
+	broadMessage.From = chat.UserId
+	broadMessage.MessageType = chat.MessageType
+
+	data, err := json.Marshal(broadMessage)
+	if err != nil {
+		return fmt.Errorf("failed to marshal broadcast message: %v", err)
+	}
+
+	eventType, ok := messageEvents[chat.MessageType]
+	if !ok {
+		return ErrEventNotSupported
+	}
+
+	return m.broker.Publish(context.Background(), &BrokerMessage{Room: chat.TelemedicineId, Event: Event{Type: eventType, Payload: data}})
+}
+
+func (m *Manager) receive(message *BrokerMessage) {
+	select {
+	case m.inbox <- message:
+	default:
+		logger.Log.Errorf("chat inbox is full, dropping %s event", message.Event.Type)
+	}
+}
+
+func (m *Manager) run() {
+	for message := range m.inbox {
+		m.dispatch(message)
+	}
+}
+
+func (m *Manager) dispatch(message *BrokerMessage) {
+	m.RLock()
+	var targets []*Client
+	for client := range m.clients {
+		if message.UserId != 0 {
+			if client.chatRoom == 0 && client.userId == message.UserId {
+				targets = append(targets, client)
+			}
+			continue
+		}
+		if client.chatRoom == message.Room && client.id != message.ExcludeClient {
+			targets = append(targets, client)
+		}
+	}
+	m.RUnlock()
+
+	for _, client := range targets {
+		client.send(message.Event)
+	}
 }
 
 func (m *Manager) setupEventHandler() {
 	m.handlers[EventSendMessage] = SendMessageHandler
-	m.handlers[EventSendImage] = SendMessageHandler
-	m.handlers[EventSendPdf] = SendMessageHandler
 	m.handlers[EventTypingSignal] = SendTypingSignalHandler
+	m.handlers[EventDelivered] = ReceiptHandler
+	m.handlers[EventRead] = ReceiptHandler
 }
 
 func (m *Manager) routeEvent(e Event, c *Client) error {
@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"context"
+	"sync"
+)
+
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers []func(message *BrokerMessage)
+	closed   bool
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, message *BrokerMessage) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBrokerClosed
+	}
+	handlers := make([]func(message *BrokerMessage), len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		copied := *message
+		handler(&copied)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(handler func(message *BrokerMessage)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBrokerClosed
+	}
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.handlers = nil
+	return nil
+}
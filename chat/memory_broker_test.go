@@ -0,0 +1,36 @@
+package chat_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/night1010/everhealth/chat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	t.Run("should deliver published message to every subscriber", func(t *testing.T) {
+		broker := chat.NewMemoryBroker()
+		var first, second []*chat.BrokerMessage
+		_ = broker.Subscribe(func(message *chat.BrokerMessage) { first = append(first, message) })
+		_ = broker.Subscribe(func(message *chat.BrokerMessage) { second = append(second, message) })
+
+		err := broker.Publish(context.Background(), &chat.BrokerMessage{Room: 7, Event: chat.Event{Type: chat.EventTypingSignal}})
+
+		assert.NoError(t, err)
+		assert.Len(t, first, 1)
+		assert.Len(t, second, 1)
+		assert.Equal(t, uint(7), first[0].Room)
+		assert.Equal(t, chat.EventTypingSignal, second[0].Event.Type)
+	})
+
+	t.Run("should reject publish after close", func(t *testing.T) {
+		broker := chat.NewMemoryBroker()
+		_ = broker.Close()
+
+		err := broker.Publish(context.Background(), &chat.BrokerMessage{Room: 1})
+
+		assert.ErrorIs(t, err, chat.ErrBrokerClosed)
+		assert.ErrorIs(t, broker.Subscribe(func(*chat.BrokerMessage) {}), chat.ErrBrokerClosed)
+	})
+}
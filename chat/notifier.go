@@ -0,0 +1,36 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/logger"
+)
+
+type QueueNotifier struct {
+	broker Broker
+}
+
+func NewQueueNotifier(broker Broker) *QueueNotifier {
+	return &QueueNotifier{broker: broker}
+}
+
+func (n *QueueNotifier) NotifyQueue(userId uint, queue *entity.TelemedicineQueue) {
+	data, err := json.Marshal(QueueUpdateEvent{
+		DoctorId:             queue.DoctorId,
+		Status:               queue.Status,
+		Position:             queue.Position,
+		EstimatedWaitMinutes: int(queue.EstimatedWait.Minutes()),
+		TelemedicineId:       queue.TelemedicineId,
+	})
+	if err != nil {
+		logger.Log.Errorf("failed to marshal queue update: %v", err)
+		return
+	}
+
+	message := &BrokerMessage{UserId: userId, Event: Event{Type: EventQueueUpdate, Payload: data}}
+	if err := n.broker.Publish(context.Background(), message); err != nil {
+		logger.Log.Errorf("failed to publish queue update: %v", err)
+	}
+}
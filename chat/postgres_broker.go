@@ -0,0 +1,102 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/night1010/everhealth/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	postgresNotifyLimit    = 8000
+	postgresReconnectDelay = 3 * time.Second
+)
+
+type postgresBroker struct {
+	db      *gorm.DB
+	dsn     string
+	channel string
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newPostgresBroker(db *gorm.DB, dsn string, channel string) *postgresBroker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &postgresBroker{db: db, dsn: dsn, channel: channel, ctx: ctx, cancel: cancel}
+}
+
+func (b *postgresBroker) Publish(ctx context.Context, message *BrokerMessage) error {
+	if b.ctx.Err() != nil {
+		return ErrBrokerClosed
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if len(payload) > postgresNotifyLimit {
+		return fmt.Errorf("chat message of %d bytes exceeds notify limit", len(payload))
+	}
+	return b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", b.channel, string(payload)).Error
+}
+
+func (b *postgresBroker) Subscribe(handler func(message *BrokerMessage)) error {
+	if b.ctx.Err() != nil {
+		return ErrBrokerClosed
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			err := b.listen(handler)
+			if b.ctx.Err() != nil {
+				return
+			}
+			logger.Log.Errorf("chat broker listen failed: %v", err)
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-time.After(postgresReconnectDelay):
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *postgresBroker) listen(handler func(message *BrokerMessage)) error {
+	conn, err := pgx.Connect(b.ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err := conn.Exec(b.ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(b.ctx)
+		if err != nil {
+			return err
+		}
+		var message BrokerMessage
+		if err := json.Unmarshal([]byte(notification.Payload), &message); err != nil {
+			logger.Log.Errorf("failed to unmarshal chat broker message: %v", err)
+			continue
+		}
+		handler(&message)
+	}
+}
+
+func (b *postgresBroker) Close() error {
+	b.cancel()
+	b.wg.Wait()
+	return nil
+}
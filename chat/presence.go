@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/logger"
+)
+
+func (m *Manager) join(client *Client) {
+	online, first, err := m.chatUsecase.JoinPresence(context.Background(), client.presence())
+	if err != nil {
+		logger.Log.Errorf("failed to join presence: %v", err)
+		return
+	}
+	if client.chatRoom == 0 {
+		return
+	}
+
+	data, err := json.Marshal(PresenceSnapshotEvent{UserIds: online})
+	if err != nil {
+		logger.Log.Errorf("failed to marshal presence snapshot: %v", err)
+	} else {
+		client.send(Event{Type: EventPresenceSnapshot, Payload: data})
+	}
+
+	if first {
+		m.publishPresence(client, EventPresenceJoin, PresenceEvent{UserId: client.userId})
+	}
+}
+
+func (m *Manager) refresh(client *Client) {
+	if err := m.chatUsecase.RefreshPresence(context.Background(), client.presence()); err != nil {
+		logger.Log.Errorf("failed to refresh presence: %v", err)
+	}
+}
+
+func (m *Manager) leave(client *Client) {
+	lastSeenAt := time.Now()
+	left, err := m.chatUsecase.LeavePresence(context.Background(), client.presence())
+	if err != nil {
+		logger.Log.Errorf("failed to leave presence: %v", err)
+	}
+	if left && client.chatRoom != 0 {
+		m.publishPresence(client, EventPresenceLeave, PresenceEvent{UserId: client.userId, LastSeenAt: &lastSeenAt})
+	}
+
+	if err := m.chatUsecase.UpdateLastSeen(context.Background(), client.userId, lastSeenAt); err != nil {
+		logger.Log.Errorf("failed to update last seen: %v", err)
+	}
+
+	userId := client.userId
+	time.AfterFunc(m.gracePeriod, func() {
+		online, err := m.chatUsecase.IsOnline(context.Background(), userId)
+		if err != nil {
+			logger.Log.Errorf("failed to check presence: %v", err)
+			return
+		}
+		if online {
+			return
+		}
+		if err := m.chatUsecase.SetDoctorOffline(context.Background(), userId); err != nil {
+			logger.Log.Errorf("failed to set doctor offline: %v", err)
+		}
+	})
+}
+
+func (m *Manager) publishPresence(client *Client, eventType EventType, presence PresenceEvent) {
+	data, err := json.Marshal(presence)
+	if err != nil {
+		logger.Log.Errorf("failed to marshal presence: %v", err)
+		return
+	}
+	message := &BrokerMessage{Room: client.chatRoom, ExcludeClient: client.id, Event: Event{Type: eventType, Payload: data}}
+	if err := m.broker.Publish(context.Background(), message); err != nil {
+		logger.Log.Errorf("failed to publish presence: %v", err)
+	}
+}
+
+func (c *Client) presence() *entity.ChatPresence {
+	return &entity.ChatPresence{ClientId: c.id, TelemedicineId: c.chatRoom, UserId: c.userId}
+}
@@ -4,11 +4,15 @@ import (
 	"github.com/night1010/everhealth/appjwt"
 	"github.com/night1010/everhealth/appvalidator"
 	"github.com/night1010/everhealth/chat"
+	"github.com/night1010/everhealth/config"
 	"github.com/night1010/everhealth/handler"
 	"github.com/night1010/everhealth/hasher"
 	"github.com/night1010/everhealth/imagehelper"
 	"github.com/night1010/everhealth/logger"
 	"github.com/night1010/everhealth/mail"
+	"github.com/night1010/everhealth/middleware"
+	"github.com/night1010/everhealth/payment"
+	"github.com/night1010/everhealth/rbac"
 	"github.com/night1010/everhealth/repository"
 	"github.com/night1010/everhealth/router"
 	"github.com/night1010/everhealth/server"
@@ -35,13 +39,18 @@ func main() {
 	if err != nil {
 		logger.Log.Error(err)
 	}
-	jwt := appjwt.NewJwt()
 	appvalidator.RegisterCustomValidator()
 	ur := repository.NewUserRepository(db)
+	jwt := appjwt.NewJwt(ur)
+	roleRepository := repository.NewRoleRepository(db)
+	roleResolver := rbac.NewResolver(roleRepository)
 	pr := repository.NewProfileRepository(db)
 	dpr := repository.NewDoctorProfileRepository(db)
 	productCategoryRepository := repository.NewProductCategoryRepository(db)
 	fr := repository.NewForgotPasswordRepository(db)
+	rtr := repository.NewRefreshTokenRepository(db)
+	tfr := repository.NewUserTwoFactorRepository(db)
+	rcr := repository.NewTwoFactorRecoveryCodeRepository(db)
 	productRepo := repository.NewProductRepository(db)
 	cartRepo := repository.NewCartRepository(db)
 	pharmacyProductRepository := repository.NewPharmacyProductRepository(db)
@@ -50,7 +59,8 @@ func main() {
 	drugFormRepo := repository.NewDrugFormRepository(db)
 	drugClassificationRepo := repository.NewDrugClassificationRepository(db)
 
-	au := usecase.NewAuthUsecase(manager, ur, pr, dpr, fr, cartRepo, mail, hash, jwt, imageHelper)
+	aau := usecase.NewAuthAttemptUsecase(manager, repository.NewAuthAttemptRepository(db))
+	au := usecase.NewAuthUsecase(manager, ur, pr, dpr, fr, rtr, tfr, rcr, cartRepo, mail, hash, jwt, imageHelper, aau, roleResolver)
 	uu := usecase.NewUserUsecase(manager, ur, pr, dpr, hash, imageHelper)
 	productCategoryUsecase := usecase.NewProductCategoryUsecase(productCategoryRepository, imageHelper, manager)
 	productUsecase := usecase.NewProductUsecase(manager, imageHelper, productRepo, productCategoryRepository, drugRepo, drugFormRepo, drugClassificationRepo, pharmacyProductRepository)
@@ -88,46 +98,109 @@ func main() {
 	addressHandler := handler.NewAddressHandler(addressUsecase)
 
 	pharmacyRepository := repository.NewPharmacyRepository(db)
-	pharmacyUsecase := usecase.NewPharmacyUsecase(pharmacyRepository, provinceRepository, cityRepository)
+	pharmacyStaffRepository := repository.NewPharmacyStaffRepository(db)
+	pharmacyUsecase := usecase.NewPharmacyUsecase(pharmacyRepository, provinceRepository, cityRepository, pharmacyStaffRepository, manager)
 	pharmacyHandler := handler.NewPharmacyHandler(pharmacyUsecase)
+	pharmacyStaffUsecase := usecase.NewPharmacyStaffUsecase(manager, pharmacyStaffRepository, pharmacyRepository, ur)
+	pharmacyStaffHandler := handler.NewPharmacyStaffHandler(pharmacyStaffUsecase)
+
+	stockReservationRepository := repository.NewStockReservationRepository(db)
+
+	telemedicineRepo := repository.NewTelemedicineRepository(db)
+	prescriptionItemRepository := repository.NewPrescriptionItemRepository(db)
 
 	cartItemRepo := repository.NewCartItemRepository(db)
-	cartUsecase := usecase.NewCartUsecase(manager, cartRepo, cartItemRepo, productRepo, pharmacyProductRepository)
+	cartUsecase := usecase.NewCartUsecase(manager, cartRepo, cartItemRepo, productRepo, pharmacyProductRepository, telemedicineRepo, prescriptionItemRepository)
 	cartHandler := handler.NewCartHandler(cartUsecase)
 
-	pharmacyProductUsecase := usecase.NewPharmacyProductUsecase(pharmacyProductRepository, pharmacyRepository, productRepo)
+	pharmacyProductUsecase := usecase.NewPharmacyProductUsecase(pharmacyProductRepository, pharmacyRepository, productRepo, stockReservationRepository, pharmacyStaffRepository)
 	pharmacyProductHandler := handler.NewPharmacyProductHandler(pharmacyProductUsecase)
 
 	adminContactRepository := repository.NewAdminContactRepository(db)
-	adminPharmacyUsecase := usecase.NewAdminPharmacyUsecase(ur, hash, pharmacyRepository, adminContactRepository, manager)
+	adminPharmacyUsecase := usecase.NewAdminPharmacyUsecase(ur, hash, pharmacyRepository, adminContactRepository, pharmacyStaffRepository, manager)
 	adminPharmacyHandler := handler.NewAdminPharmacyHandler(adminPharmacyUsecase)
 
 	stockRecordRepository := repository.NewStockRecordRepository(db)
-	stockRecordUsecase := usecase.NewStockRecordUsecase(stockRecordRepository, pharmacyProductRepository, manager)
+	stockRecordUsecase := usecase.NewStockRecordUsecase(stockRecordRepository, pharmacyProductRepository, stockReservationRepository, pharmacyStaffRepository, manager)
 	stockRecordHandler := handler.NewStockRecordHandler(stockRecordUsecase)
 
 	stockMutationRepository := repository.NewStockMutationRepository(db)
-	stockMutationUsecase := usecase.NewStockMutationUsecase(stockMutationRepository, pharmacyProductRepository, stockRecordRepository, manager)
+	stockMutationUsecase := usecase.NewStockMutationUsecase(stockMutationRepository, pharmacyProductRepository, stockRecordRepository, pharmacyStaffRepository, manager)
 	stockMutationHandler := handler.NewStockMutationHandler(stockMutationUsecase)
 
 	orderItemRepository := repository.NewOrderItemRepository(db)
 	productOrderRepository := repository.NewProductOrderRepository(db)
-	orderUsecase := usecase.NewOrderUsecase(manager, imageHelper, cartRepo, orderItemRepository, productOrderRepository, cartItemRepo, addressRepository, pharmacyRepository, pharmacyProductRepository, stockMutationRepository, stockRecordRepository)
+	orderShipmentRepository := repository.NewOrderShipmentRepository(db)
+	refundRepository := repository.NewRefundRepository(db)
+	orderStatusHistoryRepository := repository.NewOrderStatusHistoryRepository(db)
+	invoiceRepository := repository.NewInvoiceRepository(db)
+	orderPrescriptionRepository := repository.NewOrderPrescriptionRepository(db)
+
+	voucherRepository := repository.NewVoucherRepository(db)
+	voucherUsageRepository := repository.NewVoucherUsageRepository(db)
+	voucherUsecase := usecase.NewVoucherUsecase(manager, voucherRepository, voucherUsageRepository, productCategoryRepository)
+	voucherHandler := handler.NewVoucherHandler(voucherUsecase)
+
+	paymentRepo := repository.NewPaymentRepository(db)
+	orderUsecase := usecase.NewOrderUsecase(manager, imageHelper, cartRepo, orderItemRepository, productOrderRepository, orderShipmentRepository, cartItemRepo, addressRepository, pharmacyRepository, pharmacyProductRepository, stockMutationRepository, stockRecordRepository, stockReservationRepository, refundRepository, orderStatusHistoryRepository, invoiceRepository, orderPrescriptionRepository, telemedicineRepo, paymentRepo, voucherUsecase)
 	orderHandler := handler.NewOrderHandler(orderUsecase)
 
 	shippingMethodRepo := repository.NewShippingMethodRepository(db, client)
-	shippingMethodUsecase := usecase.NewShippingMethodUsecase(addressRepository, shippingMethodRepo, pharmacyRepository, orderUsecase)
+	shippingMethodUsecase := usecase.NewShippingMethodUsecase(addressRepository, shippingMethodRepo, pharmacyRepository, orderUsecase, voucherUsecase)
 	shippingMethodHandler := handler.NewShippingMethodHandler(shippingMethodUsecase)
 
-	telemedicineRepo := repository.NewTelemedicineRepository(db)
-	telemedicineUsecase := usecase.NewTelemedicineUsecase(telemedicineRepo, dpr, manager, imageHelper)
-	telemedicineHandler := handler.NewTelemedicineHadnler(telemedicineUsecase)
-
 	chatRepo := repository.NewChatRepository(db)
-	chatUsecase := usecase.NewChatUsecase(chatRepo, telemedicineRepo)
-	chatManager := chat.NewManager(chatUsecase)
+	telemedicineQueueRepository := repository.NewTelemedicineQueueRepository(db)
+	chatReceiptRepo := repository.NewChatReceiptRepository(db)
+	chatPresenceRepo := repository.NewChatPresenceRepository(db)
+	chatUsecase := usecase.NewChatUsecase(manager, imageHelper, chatRepo, chatReceiptRepo, telemedicineRepo, pr, dpr, chatPresenceRepo, telemedicineQueueRepository)
+	chatConfig := config.NewChatConfig()
+	chatBroker, err := chat.NewBroker(chatConfig.Broker, db)
+	if err != nil {
+		logger.Log.Fatal(err)
+	}
+	defer chatBroker.Close()
+	chatManager, err := chat.NewManager(chatUsecase, chatBroker, chatConfig.PresenceGracePeriod)
+	if err != nil {
+		logger.Log.Fatal(err)
+	}
 	chatHandler := handler.NewChatHandler(chatManager, chatUsecase, jwt)
 
+	doctorAvailabilityRepository := repository.NewDoctorAvailabilityRepository(db)
+	doctorAvailabilityExceptionRepository := repository.NewDoctorAvailabilityExceptionRepository(db)
+	doctorScheduleUsecase := usecase.NewDoctorScheduleUsecase(manager, dpr, doctorAvailabilityRepository, doctorAvailabilityExceptionRepository, telemedicineRepo)
+	doctorScheduleHandler := handler.NewDoctorScheduleHandler(doctorScheduleUsecase)
+
+	telemedicineUsecase := usecase.NewTelemedicineUsecase(telemedicineRepo, dpr, refundRepository, paymentRepo, productRepo, prescriptionItemRepository, telemedicineQueueRepository, chatRepo, doctorScheduleUsecase, manager, imageHelper, chat.NewQueueNotifier(chatBroker))
+	telemedicineHandler := handler.NewTelemedicineHadnler(telemedicineUsecase)
+
+	doctorReviewRepository := repository.NewDoctorReviewRepository(db)
+	doctorReviewUsecase := usecase.NewDoctorReviewUsecase(manager, doctorReviewRepository, dpr, telemedicineRepo)
+	doctorReviewHandler := handler.NewDoctorReviewHandler(doctorReviewUsecase)
+
+	productReviewRepository := repository.NewProductReviewRepository(db)
+	productReviewPhotoRepository := repository.NewProductReviewPhotoRepository(db)
+	productReviewUsecase := usecase.NewProductReviewUsecase(manager, imageHelper, productReviewRepository, productReviewPhotoRepository, productRepo, productOrderRepository, orderItemRepository)
+	productReviewHandler := handler.NewProductReviewHandler(productReviewUsecase)
+
+	paymentProviders := []payment.PaymentProvider{}
+	for _, method := range []string{payment.ManualMethod, config.NewPaymentConfig().Provider} {
+		paymentProvider, err := payment.NewPaymentProvider(method)
+		if err != nil {
+			logger.Log.Error(err)
+			continue
+		}
+		paymentProviders = append(paymentProviders, paymentProvider)
+	}
+	paymentUsecase := usecase.NewPaymentUsecase(manager, paymentProviders, paymentRepo, refundRepository, productOrderRepository, telemedicineRepo, orderUsecase, telemedicineUsecase)
+	paymentHandler := handler.NewPaymentHandler(paymentUsecase)
+
+	refundUsecase := usecase.NewRefundUsecase(manager, paymentProviders, refundRepository, paymentRepo, productOrderRepository, orderShipmentRepository, orderItemRepository, pharmacyStaffRepository)
+	refundHandler := handler.NewRefundHandler(refundUsecase)
+
+	roleUsecase := usecase.NewRoleUsecase(manager, roleRepository, repository.NewPermissionRepository(db), ur, roleResolver)
+	roleHandler := handler.NewRoleHandler(roleUsecase)
+
 	handlers := router.Handlers{
 		Auth:               ah,
 		ProductCategory:    productCategoryHandler,
@@ -149,9 +222,17 @@ func main() {
 		ShippingMethod:     shippingMethodHandler,
 		Chat:               chatHandler,
 		Telemedicine:       telemedicineHandler,
+		Payment:            paymentHandler,
+		Refund:             refundHandler,
+		Voucher:            voucherHandler,
+		DoctorSchedule:     doctorScheduleHandler,
+		DoctorReview:       doctorReviewHandler,
+		ProductReview:      productReviewHandler,
+		Role:               roleHandler,
+		PharmacyStaff:      pharmacyStaffHandler,
 	}
 
-	r := router.New(handlers)
+	r := router.New(handlers, middleware.NewAuthenticator(jwt, roleResolver))
 
 	s := server.New(r)
 
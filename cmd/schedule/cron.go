@@ -6,8 +6,12 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/night1010/everhealth/chat"
+	"github.com/night1010/everhealth/config"
 	"github.com/night1010/everhealth/logger"
 	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/usecase"
 	"github.com/robfig/cron"
 )
 
@@ -22,12 +26,84 @@ func main() {
 	c := cron.New()
 	defer c.Stop()
 
-	repo := repository.NewProductOrderRepository(db)
+	manager := transactor.NewManager(db)
+	voucherUsecase := usecase.NewVoucherUsecase(
+		manager,
+		repository.NewVoucherRepository(db),
+		repository.NewVoucherUsageRepository(db),
+		repository.NewProductCategoryRepository(db),
+	)
+	orderUsecase := usecase.NewOrderUsecase(
+		manager,
+		nil,
+		repository.NewCartRepository(db),
+		repository.NewOrderItemRepository(db),
+		repository.NewProductOrderRepository(db),
+		repository.NewOrderShipmentRepository(db),
+		repository.NewCartItemRepository(db),
+		repository.NewAddressRepository(db),
+		repository.NewPharmacyRepository(db),
+		repository.NewPharmacyProductRepository(db),
+		repository.NewStockMutationRepository(db),
+		repository.NewStockRecordRepository(db),
+		repository.NewStockReservationRepository(db),
+		repository.NewRefundRepository(db),
+		repository.NewOrderStatusHistoryRepository(db),
+		repository.NewInvoiceRepository(db),
+		repository.NewOrderPrescriptionRepository(db),
+		repository.NewTelemedicineRepository(db),
+		repository.NewPaymentRepository(db),
+		voucherUsecase,
+	)
+
+	chatBroker, err := chat.NewBroker(config.NewChatConfig().Broker, db)
+	if err != nil {
+		logger.Log.Fatal(err)
+	}
+	defer chatBroker.Close()
+
+	telemedicineRepository := repository.NewTelemedicineRepository(db)
+	telemedicineQueueRepository := repository.NewTelemedicineQueueRepository(db)
+	doctorProfileRepository := repository.NewDoctorProfileRepository(db)
+	telemedicineUsecase := usecase.NewTelemedicineUsecase(
+		telemedicineRepository,
+		doctorProfileRepository,
+		repository.NewRefundRepository(db),
+		repository.NewPaymentRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewPrescriptionItemRepository(db),
+		telemedicineQueueRepository,
+		repository.NewChatRepository(db),
+		usecase.NewDoctorScheduleUsecase(
+			manager,
+			doctorProfileRepository,
+			repository.NewDoctorAvailabilityRepository(db),
+			repository.NewDoctorAvailabilityExceptionRepository(db),
+			telemedicineRepository,
+		),
+		manager,
+		nil,
+		chat.NewQueueNotifier(chatBroker),
+	)
+
+	chatUsecase := usecase.NewChatUsecase(
+		manager,
+		nil,
+		repository.NewChatRepository(db),
+		repository.NewChatReceiptRepository(db),
+		telemedicineRepository,
+		repository.NewProfileRepository(db),
+		doctorProfileRepository,
+		repository.NewChatPresenceRepository(db),
+		telemedicineQueueRepository,
+	)
+
+	authAttemptUsecase := usecase.NewAuthAttemptUsecase(manager, repository.NewAuthAttemptRepository(db))
 
 	background := context.Background()
 
 	err = c.AddFunc("@hourly", func() {
-		err := repo.CancelOrder(background)
+		err := orderUsecase.CancelExpiredOrders(background)
 		if err != nil {
 			logger.Log.Error(err)
 		}
@@ -37,7 +113,40 @@ func main() {
 	}
 
 	err = c.AddFunc("@daily", func() {
-		err := repo.ConfirmOrder(background)
+		err := orderUsecase.ConfirmSentOrders(background)
+		if err != nil {
+			logger.Log.Error(err)
+		}
+	})
+	if err != nil {
+		logger.Log.Error(err)
+	}
+
+	err = c.AddFunc("@hourly", func() {
+		err := authAttemptUsecase.PurgeStale(background)
+		if err != nil {
+			logger.Log.Error(err)
+		}
+	})
+	if err != nil {
+		logger.Log.Error(err)
+	}
+
+	err = c.AddFunc("@every 1m", func() {
+		err := telemedicineUsecase.CancelExpiredTelemedicines(background)
+		if err != nil {
+			logger.Log.Error(err)
+		}
+		err = telemedicineUsecase.StartScheduledTelemedicines(background)
+		if err != nil {
+			logger.Log.Error(err)
+		}
+	})
+	if err != nil {
+		logger.Log.Error(err)
+	}
+	err = c.AddFunc("@every 1m", func() {
+		err := chatUsecase.SweepPresence(background)
 		if err != nil {
 			logger.Log.Error(err)
 		}
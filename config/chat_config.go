@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	defaultChatBroker          = "postgres"
+	defaultChatChannel         = "everhealth_chat"
+	defaultPresenceGracePeriod = 60
+)
+
+var chatConfig *ChatConfig
+
+type ChatConfig struct {
+	Broker              string
+	Channel             string
+	PresenceGracePeriod time.Duration
+}
+
+func NewChatConfig() *ChatConfig {
+	if chatConfig == nil {
+		chatConfig = initializeChatConfig()
+	}
+	return chatConfig
+}
+
+func initializeChatConfig() *ChatConfig {
+	_ = godotenv.Load()
+
+	broker := os.Getenv("CHAT_BROKER")
+	if broker == "" {
+		broker = defaultChatBroker
+	}
+
+	channel := os.Getenv("CHAT_CHANNEL")
+	if channel == "" {
+		channel = defaultChatChannel
+	}
+
+	presenceGracePeriodString := os.Getenv("CHAT_PRESENCE_GRACE_PERIOD")
+	presenceGracePeriod, err := strconv.Atoi(presenceGracePeriodString)
+	if err != nil {
+		presenceGracePeriod = defaultPresenceGracePeriod
+	}
+
+	return &ChatConfig{
+		Broker:              broker,
+		Channel:             channel,
+		PresenceGracePeriod: time.Duration(presenceGracePeriod) * time.Second,
+	}
+}
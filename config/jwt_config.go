@@ -8,13 +8,17 @@ import (
 	"github.com/joho/godotenv"
 )
 
-const defaultJwtExpiryDuration = 1
+const (
+	defaultJwtAccessExpiryDuration  = 15
+	defaultJwtRefreshExpiryDuration = 720
+)
 
 var jwtConfig *JwtConfig
 
 type JwtConfig struct {
-	ExpiryDuration time.Duration
-	Secret         string
+	AccessExpiryDuration  time.Duration
+	RefreshExpiryDuration time.Duration
+	Secret                string
 }
 
 func NewJwtConfig() *JwtConfig {
@@ -27,17 +31,24 @@ func NewJwtConfig() *JwtConfig {
 func initializeJwtConfig() *JwtConfig {
 	_ = godotenv.Load()
 
-	jwtExpiryDurationString := os.Getenv("JWT_EXPIRY_DURATION")
-	jwtExpiryDuration, err := strconv.Atoi(jwtExpiryDurationString)
+	jwtAccessExpiryDurationString := os.Getenv("JWT_ACCESS_EXPIRY_DURATION")
+	jwtAccessExpiryDuration, err := strconv.Atoi(jwtAccessExpiryDurationString)
+	if err != nil {
+		jwtAccessExpiryDuration = defaultJwtAccessExpiryDuration
+	}
+
+	jwtRefreshExpiryDurationString := os.Getenv("JWT_REFRESH_EXPIRY_DURATION")
+	jwtRefreshExpiryDuration, err := strconv.Atoi(jwtRefreshExpiryDurationString)
 	if err != nil {
-		jwtExpiryDuration = defaultJwtExpiryDuration
+		jwtRefreshExpiryDuration = defaultJwtRefreshExpiryDuration
 	}
 
 	jwtSecret := os.Getenv("JWT_SECRET")
 
 	return &JwtConfig{
-		ExpiryDuration: time.Duration(jwtExpiryDuration),
-		Secret:         jwtSecret,
+		AccessExpiryDuration:  time.Duration(jwtAccessExpiryDuration) * time.Minute,
+		RefreshExpiryDuration: time.Duration(jwtRefreshExpiryDuration) * time.Hour,
+		Secret:                jwtSecret,
 	}
 
 }
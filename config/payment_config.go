@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+const defaultPaymentProvider = "manual"
+
+var paymentConfig *PaymentConfig
+
+type PaymentConfig struct {
+	Provider   string
+	FakeSecret string
+}
+
+func NewPaymentConfig() *PaymentConfig {
+	if paymentConfig == nil {
+		paymentConfig = initializePaymentConfig()
+	}
+	return paymentConfig
+}
+
+func initializePaymentConfig() *PaymentConfig {
+	_ = godotenv.Load()
+
+	provider := os.Getenv("PAYMENT_PROVIDER")
+	if provider == "" {
+		provider = defaultPaymentProvider
+	}
+
+	return &PaymentConfig{
+		Provider:   provider,
+		FakeSecret: os.Getenv("PAYMENT_FAKE_SECRET"),
+	}
+}
@@ -2,15 +2,19 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 var restConfig *RestConfig
 
+const defaultTrustedProxies = "127.0.0.1,::1"
+
 type RestConfig struct {
-	Host string
-	Port string
+	Host           string
+	Port           string
+	TrustedProxies []string
 }
 
 func NewRestConfig() *RestConfig {
@@ -25,9 +29,14 @@ func initializeRestConfig() *RestConfig {
 
 	host := os.Getenv("REST_HOST")
 	port := os.Getenv("REST_PORT")
+	trustedProxies := os.Getenv("REST_TRUSTED_PROXIES")
+	if trustedProxies == "" {
+		trustedProxies = defaultTrustedProxies
+	}
 	
 	return &RestConfig{
-		Host: host,
-		Port: port,
+		Host:           host,
+		Port:           port,
+		TrustedProxies: strings.Split(trustedProxies, ","),
 	}
 }
@@ -47,9 +47,17 @@ type UpdateAddressRequest struct {
 }
 
 type ShippingMethodResponse struct {
-	Name     string `json:"name"`
-	Duration string `json:"duration"`
-	Cost     string `json:"cost"`
+	Name      string `json:"name"`
+	Duration  string `json:"duration"`
+	Cost      string `json:"cost"`
+	Discount  string `json:"discount,omitempty"`
+	FinalCost string `json:"final_cost,omitempty"`
+}
+
+type PharmacyShippingMethodResponse struct {
+	PharmacyId      uint                     `json:"pharmacy_id"`
+	PharmacyName    string                   `json:"pharmacy_name"`
+	ShippingMethods []ShippingMethodResponse `json:"shipping_methods"`
 }
 
 type CreateAddressResponse struct {
@@ -60,6 +68,10 @@ type AddressUri struct {
 	Id uint `uri:"id" binding:"required,numeric"`
 }
 
+type ShippingMethodQueryParam struct {
+	VoucherCode *string `form:"voucher_code" binding:"omitempty,max=50"`
+}
+
 func (r *CreateAddressRequest) ToProfile() *entity.Profile {
 	return &entity.Profile{
 		Name: r.Name,
@@ -29,8 +29,54 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token  string `json:"token"`
-	RoleId uint   `json:"role_id"`
+	Token              string `json:"token"`
+	RefreshToken       string `json:"refresh_token"`
+	RoleId             uint   `json:"role_id"`
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	EnrollmentRequired bool   `json:"enrollment_required,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `binding:"required" json:"challenge_token"`
+	Code           string `binding:"required_without=RecoveryCode" json:"code"`
+	RecoveryCode   string `binding:"required_without=Code" json:"recovery_code"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `binding:"required" json:"challenge_token"`
+}
+
+type TwoFactorChallengeActivateRequest struct {
+	ChallengeToken string `binding:"required" json:"challenge_token"`
+	Code           string `binding:"required" json:"code"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `binding:"required" json:"code"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorActivationResponse struct {
+	*LoginResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `binding:"required" json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `binding:"required" json:"refresh_token"`
+	AllDevices   bool   `json:"all_devices"`
 }
 
 type ForgotPasswordRequest struct {
@@ -41,6 +87,29 @@ type ApplyPasswordRequest struct {
 	Password string `binding:"required" json:"password"`
 }
 
+func NewLoginResponse(u *entity.User) *LoginResponse {
+	if u.Challenge != nil {
+		return &LoginResponse{
+			RoleId:             uint(u.RoleId),
+			TwoFactorRequired:  true,
+			ChallengeToken:     u.Challenge.Token,
+			EnrollmentRequired: u.Challenge.EnrollmentRequired,
+		}
+	}
+	return &LoginResponse{
+		Token:        u.Token,
+		RefreshToken: u.RefreshToken,
+		RoleId:       uint(u.RoleId),
+	}
+}
+
+func NewTwoFactorEnrollmentResponse(t *entity.UserTwoFactor) *TwoFactorEnrollmentResponse {
+	return &TwoFactorEnrollmentResponse{
+		Secret:          t.Secret,
+		ProvisioningUri: t.ProvisioningUri,
+	}
+}
+
 func (r *RegisterRequest) ToUser() *entity.User {
 	return &entity.User{
 		Email:      r.Email,
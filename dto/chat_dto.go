@@ -2,4 +2,24 @@ package dto
 
 type ChatToken struct {
 	Token string `form:"token"`
+	Queue bool   `form:"queue"`
+}
+
+const defaultChatHistoryLimit = 20
+
+type ChatHistoryQueryParam struct {
+	Before *uint `form:"before" binding:"omitempty,numeric,min=1"`
+	Limit  *int  `form:"limit" binding:"omitempty,numeric,min=1,max=100"`
+}
+
+func (qp *ChatHistoryQueryParam) GetLimit() int {
+	if qp.Limit == nil {
+		return defaultChatHistoryLimit
+	}
+	return *qp.Limit
+}
+
+type ChatHistoryRes struct {
+	Messages   []*ChatRes `json:"messages"`
+	NextCursor *uint      `json:"next_cursor"`
 }
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type DoctorReviewReq struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Review string `json:"review" binding:"required,max=1000"`
+}
+
+func (r *DoctorReviewReq) ToModel(telemedicineId uint) *entity.DoctorReview {
+	return &entity.DoctorReview{TelemedicineId: telemedicineId, Rating: r.Rating, Review: r.Review}
+}
+
+type ModerateDoctorReviewReq struct {
+	IsHidden *bool `json:"is_hidden" binding:"required"`
+}
+
+type DoctorReviewQueryParam struct {
+	Rating   *int    `form:"rating" binding:"omitempty,min=1,max=5"`
+	IsHidden *bool   `form:"is_hidden"`
+	SortBy   *string `form:"sort_by" binding:"omitempty,oneof=rating created_at"`
+	Order    *string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit    *int    `form:"limit" binding:"omitempty,numeric,min=1"`
+	Page     *int    `form:"page" binding:"omitempty,numeric,min=1"`
+}
+
+func (qp *DoctorReviewQueryParam) ToQuery() *valueobject.Query {
+	query := valueobject.NewQuery()
+	if qp.Page != nil {
+		query.WithPage(*qp.Page)
+	}
+	if qp.Limit != nil {
+		query.WithLimit(*qp.Limit)
+	}
+	if qp.Order != nil {
+		query.WithOrder(valueobject.Order(*qp.Order))
+	} else {
+		query.WithOrder(valueobject.OrderDesc)
+	}
+	if qp.SortBy != nil {
+		query.WithSortBy("\"doctor_reviews\"." + *qp.SortBy)
+	} else {
+		query.WithSortBy("\"doctor_reviews\".created_at")
+	}
+	if qp.Rating != nil {
+		query.Condition("rating", valueobject.Equal, *qp.Rating)
+	}
+	if qp.IsHidden != nil {
+		query.Condition("is_hidden", valueobject.Equal, *qp.IsHidden)
+	}
+	return query
+}
+
+type DoctorReviewRes struct {
+	Id             uint        `json:"id"`
+	TelemedicineId uint        `json:"telemedicine_id"`
+	DoctorId       uint        `json:"doctor_id"`
+	Rating         int         `json:"rating"`
+	Review         string      `json:"review"`
+	IsHidden       bool        `json:"is_hidden"`
+	Profile        *ProfileRes `json:"profile,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+func NewDoctorReviewRes(r *entity.DoctorReview) *DoctorReviewRes {
+	var profile *ProfileRes
+	if r.Profile != nil {
+		profile = NewProfileTeleRes(r.Profile)
+	}
+	return &DoctorReviewRes{
+		Id:             r.Id,
+		TelemedicineId: r.TelemedicineId,
+		DoctorId:       r.DoctorId,
+		Rating:         r.Rating,
+		Review:         r.Review,
+		IsHidden:       r.IsHidden,
+		Profile:        profile,
+		CreatedAt:      r.CreatedAt,
+	}
+}
@@ -0,0 +1,157 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/appointment"
+	"github.com/night1010/everhealth/entity"
+)
+
+type DoctorAvailabilityReq struct {
+	Weekday   *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+type UpdateDoctorAvailabilityReq struct {
+	Availabilities []*DoctorAvailabilityReq `json:"availabilities" binding:"required,dive"`
+}
+
+func (r *UpdateDoctorAvailabilityReq) ToModel() ([]*entity.DoctorAvailability, error) {
+	availabilities := []*entity.DoctorAvailability{}
+	for _, availability := range r.Availabilities {
+		startTime, err := parseClock(availability.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		endTime, err := parseClock(availability.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		availabilities = append(availabilities, &entity.DoctorAvailability{
+			Weekday:   time.Weekday(*availability.Weekday),
+			StartTime: *startTime,
+			EndTime:   *endTime,
+		})
+	}
+	return availabilities, nil
+}
+
+type DoctorAvailabilityExceptionReq struct {
+	Date        string  `json:"date" binding:"required"`
+	IsAvailable *bool   `json:"is_available" binding:"required"`
+	StartTime   *string `json:"start_time"`
+	EndTime     *string `json:"end_time"`
+	Reason      string  `json:"reason" binding:"max=255"`
+}
+
+func (r *DoctorAvailabilityExceptionReq) ToModel() (*entity.DoctorAvailabilityException, error) {
+	date, err := time.ParseInLocation("2006-01-02", r.Date, time.Local)
+	if err != nil {
+		return nil, apperror.NewClientError(err)
+	}
+	exception := &entity.DoctorAvailabilityException{Date: date, IsAvailable: *r.IsAvailable, Reason: r.Reason}
+	if r.StartTime != nil {
+		exception.StartTime, err = parseClock(*r.StartTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.EndTime != nil {
+		exception.EndTime, err = parseClock(*r.EndTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exception, nil
+}
+
+type DoctorSlotQueryParam struct {
+	Date string `form:"date" binding:"required"`
+}
+
+func (qp *DoctorSlotQueryParam) ToDate() (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", qp.Date, time.Local)
+	if err != nil {
+		return time.Time{}, apperror.NewClientError(err)
+	}
+	return date, nil
+}
+
+type DoctorAvailabilityRes struct {
+	Id        uint   `json:"id"`
+	Weekday   int    `json:"weekday"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type DoctorAvailabilityExceptionRes struct {
+	Id          uint    `json:"id"`
+	Date        string  `json:"date"`
+	IsAvailable bool    `json:"is_available"`
+	StartTime   *string `json:"start_time"`
+	EndTime     *string `json:"end_time"`
+	Reason      string  `json:"reason"`
+}
+
+type DoctorScheduleRes struct {
+	Availabilities []*DoctorAvailabilityRes          `json:"availabilities"`
+	Exceptions     []*DoctorAvailabilityExceptionRes `json:"exceptions"`
+}
+
+type DoctorSlotRes struct {
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+func NewDoctorAvailabilityRes(a *entity.DoctorAvailability) *DoctorAvailabilityRes {
+	return &DoctorAvailabilityRes{
+		Id:        a.Id,
+		Weekday:   int(a.Weekday),
+		StartTime: a.StartTime.Format("15:04"),
+		EndTime:   a.EndTime.Format("15:04"),
+	}
+}
+
+func NewDoctorAvailabilityExceptionRes(e *entity.DoctorAvailabilityException) *DoctorAvailabilityExceptionRes {
+	res := &DoctorAvailabilityExceptionRes{
+		Id:          e.Id,
+		Date:        e.Date.Format("2006-01-02"),
+		IsAvailable: e.IsAvailable,
+		Reason:      e.Reason,
+	}
+	if e.StartTime != nil && e.EndTime != nil {
+		startTime, endTime := e.StartTime.Format("15:04"), e.EndTime.Format("15:04")
+		res.StartTime, res.EndTime = &startTime, &endTime
+	}
+	return res
+}
+
+func NewDoctorScheduleRes(availabilities []*entity.DoctorAvailability, exceptions []*entity.DoctorAvailabilityException) *DoctorScheduleRes {
+	res := &DoctorScheduleRes{Availabilities: []*DoctorAvailabilityRes{}, Exceptions: []*DoctorAvailabilityExceptionRes{}}
+	for _, availability := range availabilities {
+		res.Availabilities = append(res.Availabilities, NewDoctorAvailabilityRes(availability))
+	}
+	for _, exception := range exceptions {
+		res.Exceptions = append(res.Exceptions, NewDoctorAvailabilityExceptionRes(exception))
+	}
+	return res
+}
+
+func NewDoctorSlotsRes(slots []*appointment.Slot) []*DoctorSlotRes {
+	res := []*DoctorSlotRes{}
+	for _, slot := range slots {
+		res = append(res, &DoctorSlotRes{StartAt: slot.StartAt, EndAt: slot.EndAt})
+	}
+	return res
+}
+
+func parseClock(value string) (*time.Time, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return nil, apperror.NewClientError(err)
+	}
+	clock = time.Date(1, 1, 1, clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	return &clock, nil
+}
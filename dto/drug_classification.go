@@ -5,10 +5,11 @@ import (
 )
 
 type DrugClassificationRes struct {
-	Id   uint   `json:"id"`
-	Name string `json:"name"`
+	Id                   uint   `json:"id"`
+	Name                 string `json:"name"`
+	RequiresPrescription bool   `json:"requires_prescription"`
 }
 
 func NewDrugClassificationres(d *entity.DrugClassification) DrugClassificationRes {
-	return DrugClassificationRes{Id: d.Id, Name: d.Name}
+	return DrugClassificationRes{Id: d.Id, Name: d.Name, RequiresPrescription: d.RequiresPrescription}
 }
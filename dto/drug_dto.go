@@ -7,6 +7,7 @@ type DrugResponse struct {
 	DrugFormId           string   `json:"drug_form"`
 	DrugClassificationId string   `json:"drug_classification"`
 	Content              string `json:"content"`
+	RequiresPrescription bool   `json:"requires_prescription"`
 }
 
 func NewFromDrug(d *entity.Drug) *DrugResponse {
@@ -15,5 +16,6 @@ func NewFromDrug(d *entity.Drug) *DrugResponse {
 		DrugFormId:           d.DrugForm.Name,
 		DrugClassificationId: d.DrugClassification.Name,
 		Content:              d.Content,
+		RequiresPrescription: d.DrugClassification.RequiresPrescription,
 	}
 }
@@ -1,17 +1,27 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/valueobject"
 	"github.com/shopspring/decimal"
 )
 
 type CreateOrderRequest struct {
-	AddressId     uint   `binding:"required" json:"address_id"`
-	ShippingName  string `binding:"required" json:"shipping_name"`
-	ShippingCost  string `binding:"required" json:"shipping_cost"`
-	ShippingEta   string `binding:"required" json:"shipping_eta"`
-	PaymentMethod string `binding:"required" json:"payment_method"`
+	AddressId      uint                          `binding:"required" json:"address_id"`
+	Shipments      []*CreateOrderShipmentRequest `binding:"required,min=1,dive" json:"shipments"`
+	PaymentMethod  string                        `binding:"required" json:"payment_method"`
+	VoucherCode    string                        `binding:"omitempty,max=50" json:"voucher_code"`
+	PrescriptionId *uint                         `binding:"omitempty,min=1" json:"prescription_id"`
+	TelemedicineId *uint                         `binding:"omitempty,min=1" json:"telemedicine_id"`
+}
+
+type CreateOrderShipmentRequest struct {
+	PharmacyId   uint   `binding:"required" json:"pharmacy_id"`
+	ShippingName string `binding:"required" json:"shipping_name"`
+	ShippingCost string `binding:"required" json:"shipping_cost"`
+	ShippingEta  string `binding:"required" json:"shipping_eta"`
 }
 
 type CreateOrderResponse struct {
@@ -19,16 +29,34 @@ type CreateOrderResponse struct {
 }
 
 type OrderHistoryResponse struct {
-	Id            string               `json:"id"`
-	OrderItem     []*OrderItemResponse `json:"order_items"`
-	ItemOrder     int                  `json:"item_order"`
-	OrderDate     string               `json:"order_date"`
-	ShippingName  string               `json:"shipping_name"`
-	ShippingPrice string               `json:"shipping_price"`
-	ShippingEta   string               `json:"shipping_eta"`
-	TotalPrice    string               `json:"total_price"`
-	OrderStatus   string               `json:"order_status"`
-	Name          string               `json:"name,omitempty"`
+	Id            string                   `json:"id"`
+	OrderItem     []*OrderItemResponse     `json:"order_items"`
+	ItemOrder     int                      `json:"item_order"`
+	OrderDate     string                   `json:"order_date"`
+	ShippingName  string                   `json:"shipping_name"`
+	ShippingPrice string                   `json:"shipping_price"`
+	ShippingEta   string                   `json:"shipping_eta"`
+	TotalPrice    string                   `json:"total_price"`
+	OrderStatus   string                   `json:"order_status"`
+	Name          string                   `json:"name,omitempty"`
+	Shipments     []*OrderShipmentResponse `json:"shipments"`
+}
+
+type OrderShipmentResponse struct {
+	Id                 uint                 `json:"id"`
+	PharmacyId         uint                 `json:"pharmacy_id"`
+	PharmacyName       string               `json:"pharmacy_name"`
+	OrderStatus        string               `json:"order_status"`
+	ShippingName       string               `json:"shipping_name"`
+	ShippingPrice      string               `json:"shipping_price"`
+	ShippingEta        string               `json:"shipping_eta"`
+	SubTotal           string               `json:"sub_total"`
+	Discount           string               `json:"discount"`
+	PrescriptionStatus string               `json:"prescription_status,omitempty"`
+	PrescriptionNote   string               `json:"prescription_note,omitempty"`
+	OrderItem          []*OrderItemResponse `json:"order_items,omitempty"`
+	PharmacyContact    string               `json:"pharmacy_contact,omitempty"`
+	PharmacyEmail      string               `json:"pharmacy_email,omitempty"`
 }
 
 type OrderItemResponse struct {
@@ -53,36 +81,85 @@ type OrderUri struct {
 }
 
 type OrderDetailResponse struct {
-	Id              string               `json:"id"`
-	OrderItem       []*OrderItemResponse `json:"order_items"`
-	OrderedAt       string               `json:"ordered_at"`
-	ExpiredAt       string               `json:"expired_at"`
-	OrderStatus     string               `json:"order_status"`
-	ProductPrice    string               `json:"product_price"`
-	ShippingName    string               `json:"shipping_name"`
-	ShippingPrice   string               `json:"shipping_price"`
-	ShippingEta     string               `json:"shipping_eta"`
-	TotalPrice      string               `json:"total_price"`
-	PaymentProof    string               `json:"payment_proof"`
-	Name            string               `json:"name"`
-	StreetName      string               `json:"street"`
-	PostalCode      string               `json:"postal_code"`
-	Phone           string               `json:"phone"`
-	Detail          string               `json:"detail"`
-	Province        string               `json:"province"`
-	City            string               `json:"city"`
-	PharmacyContact string               `json:"pharmacy_contact,omitempty"`
-	PharmacyEmail   string               `json:"pharmacy_email,omitempty"`
+	Id               string                        `json:"id"`
+	OrderItem        []*OrderItemResponse          `json:"order_items"`
+	OrderedAt        string                        `json:"ordered_at"`
+	ExpiredAt        string                        `json:"expired_at"`
+	OrderStatus      string                        `json:"order_status"`
+	ProductPrice     string                        `json:"product_price"`
+	ShippingName     string                        `json:"shipping_name"`
+	ShippingPrice    string                        `json:"shipping_price"`
+	ShippingEta      string                        `json:"shipping_eta"`
+	VoucherCode      string                        `json:"voucher_code,omitempty"`
+	Discount         string                        `json:"discount"`
+	ShippingDiscount string                        `json:"shipping_discount"`
+	TotalPrice       string                        `json:"total_price"`
+	Prescription     string                        `json:"prescription,omitempty"`
+	PaymentProof     string                        `json:"payment_proof"`
+	Name             string                        `json:"name"`
+	StreetName       string                        `json:"street"`
+	PostalCode       string                        `json:"postal_code"`
+	Phone            string                        `json:"phone"`
+	Detail           string                        `json:"detail"`
+	Province         string                        `json:"province"`
+	City             string                        `json:"city"`
+	PharmacyContact  string                        `json:"pharmacy_contact,omitempty"`
+	PharmacyEmail    string                        `json:"pharmacy_email,omitempty"`
+	Shipments        []*OrderShipmentResponse      `json:"shipments"`
+	Timeline         []*OrderStatusHistoryResponse `json:"timeline"`
+}
+
+type OrderStatusHistoryResponse struct {
+	ShipmentId *uint  `json:"shipment_id,omitempty"`
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status"`
+	ActorId    *uint  `json:"actor_id,omitempty"`
+	ActorRole  string `json:"actor_role"`
+	Reason     string `json:"reason,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func NewOrderStatusHistoryResponse(h *entity.OrderStatusHistory) *OrderStatusHistoryResponse {
+	res := &OrderStatusHistoryResponse{
+		ShipmentId: h.ShipmentId,
+		ActorId:    h.ActorId,
+		ActorRole:  actorRoleName(h.ActorRoleId),
+		Reason:     h.Reason,
+		CreatedAt:  h.CreatedAt.Format(time.RFC3339),
+	}
+	if h.FromStatus != nil {
+		res.FromStatus = h.FromStatus.Name
+	}
+	if h.ToStatus != nil {
+		res.ToStatus = h.ToStatus.Name
+	}
+	return res
+}
+
+func actorRoleName(roleId entity.RoleId) string {
+	switch roleId {
+	case entity.RoleUser:
+		return "user"
+	case entity.RoleDoctor:
+		return "doctor"
+	case entity.RoleAdmin:
+		return "admin"
+	case entity.RoleSuperAdmin:
+		return "super admin"
+	}
+	return "system"
 }
 
 type CheckoutItemResponse struct {
-	Id         uint   `json:"id"`
-	Name       string `json:"name"`
-	UnitInPack string `json:"unit_in_pack"`
-	Price      string `json:"price"`
-	Image      string `json:"image"`
-	Quantity   int    `json:"qty"`
-	SubTotal   string `json:"sub_total"`
+	Id           uint   `json:"id"`
+	PharmacyId   uint   `json:"pharmacy_id"`
+	PharmacyName string `json:"pharmacy_name"`
+	Name         string `json:"name"`
+	UnitInPack   string `json:"unit_in_pack"`
+	Price        string `json:"price"`
+	Image        string `json:"image"`
+	Quantity     int    `json:"qty"`
+	SubTotal     string `json:"sub_total"`
 }
 
 type CheckoutResponse struct {
@@ -92,11 +169,40 @@ type CheckoutResponse struct {
 }
 
 type UserUpdateOrderStatusRequest struct {
-	Status uint `json:"status" binding:"required,oneof=5 6"`
+	Status uint   `json:"status" binding:"required,oneof=5 6"`
+	Reason string `json:"reason" binding:"max=255"`
 }
 
 type AdminUpdateOrderStatusRequest struct {
-	Status uint `json:"status" binding:"required,oneof=3 4 6"`
+	Status     uint   `json:"status" binding:"required,oneof=3 4 6"`
+	ShipmentId *uint  `json:"shipment_id" binding:"omitempty,min=1"`
+	Reason     string `json:"reason" binding:"max=255"`
+}
+
+type ReviewPrescriptionRequest struct {
+	ShipmentId *uint  `json:"shipment_id" binding:"omitempty,min=1"`
+	Status     string `json:"status" binding:"required,oneof=approved rejected"`
+	Note       string `json:"note" binding:"required_if=Status rejected,max=255"`
+}
+
+func (r *ReviewPrescriptionRequest) ToOrder(orderId uint) *entity.ProductOrder {
+	shipment := entity.OrderShipment{
+		PrescriptionStatus: entity.PrescriptionStatus(r.Status),
+		PrescriptionNote:   r.Note,
+	}
+	if r.ShipmentId != nil {
+		shipment.Id = *r.ShipmentId
+	}
+	return &entity.ProductOrder{Id: orderId, Shipments: []entity.OrderShipment{shipment}}
+}
+
+type OrderPrescriptionResponse struct {
+	Id   uint   `json:"id"`
+	File string `json:"file"`
+}
+
+func NewOrderPrescriptionResponse(prescription *entity.OrderPrescription) *OrderPrescriptionResponse {
+	return &OrderPrescriptionResponse{Id: prescription.Id, File: prescription.File}
 }
 
 type OrderAddressUri struct {
@@ -107,30 +213,65 @@ func (r *UserUpdateOrderStatusRequest) ToOrder(orderId uint) *entity.ProductOrde
 	return &entity.ProductOrder{
 		Id:            orderId,
 		OrderStatusId: r.Status,
+		StatusReason:  r.Reason,
 	}
 }
 
 func (r *AdminUpdateOrderStatusRequest) ToOrder(orderId uint) *entity.ProductOrder {
-	return &entity.ProductOrder{
+	order := &entity.ProductOrder{
 		Id:            orderId,
 		OrderStatusId: r.Status,
+		StatusReason:  r.Reason,
+	}
+	if r.ShipmentId != nil {
+		order.Shipments = []entity.OrderShipment{{Id: *r.ShipmentId}}
 	}
+	return order
 }
 
 func (r *CreateOrderRequest) ToOrder() (*entity.ProductOrder, error) {
-	price, err := decimal.NewFromString(r.ShippingCost)
-	if err != nil {
-		return nil, err
+	var shipments []entity.OrderShipment
+	for _, shipment := range r.Shipments {
+		price, err := decimal.NewFromString(shipment.ShippingCost)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, entity.OrderShipment{
+			PharmacyId:    shipment.PharmacyId,
+			ShippingName:  shipment.ShippingName,
+			ShippingPrice: price,
+			ShippingEta:   shipment.ShippingEta,
+		})
 	}
 	return &entity.ProductOrder{
-		AddressId:     r.AddressId,
-		ShippingName:  r.ShippingName,
-		ShippingPrice: price,
-		ShippingEta:   r.ShippingEta,
-		PaymentMethod: r.PaymentMethod,
+		AddressId:                  r.AddressId,
+		Shipments:                  shipments,
+		PaymentMethod:              r.PaymentMethod,
+		VoucherCode:                r.VoucherCode,
+		PrescriptionId:             r.PrescriptionId,
+		PrescriptionTelemedicineId: r.TelemedicineId,
 	}, nil
 }
 
+func NewOrderShipmentResponse(shipment *entity.OrderShipment) *OrderShipmentResponse {
+	res := &OrderShipmentResponse{
+		Id:                 shipment.Id,
+		PharmacyId:         shipment.PharmacyId,
+		OrderStatus:        shipment.OrderStatus.Name,
+		ShippingName:       shipment.ShippingName,
+		ShippingPrice:      shipment.ShippingPrice.String(),
+		ShippingEta:        shipment.ShippingEta,
+		SubTotal:           shipment.SubTotal.String(),
+		Discount:           shipment.Discount.Add(shipment.ShippingDiscount).String(),
+		PrescriptionStatus: string(shipment.PrescriptionStatus),
+		PrescriptionNote:   shipment.PrescriptionNote,
+	}
+	if shipment.Pharmacy != nil {
+		res.PharmacyName = shipment.Pharmacy.Name
+	}
+	return res
+}
+
 func (op *OrderHistoryParam) ToQuery() (*valueobject.Query, error) {
 	query := valueobject.NewQuery()
 
@@ -159,3 +300,8 @@ func (op *OrderHistoryParam) ToQuery() (*valueobject.Query, error) {
 
 	return query, nil
 }
+
+type InvoiceResponse struct {
+	Number string `json:"number"`
+	Url    string `json:"url"`
+}
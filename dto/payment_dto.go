@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/shopspring/decimal"
+)
+
+type CreatePaymentRequest struct {
+	Provider       string `json:"provider" binding:"required"`
+	PaymentType    string `json:"payment_type" binding:"required,oneof=product_order telemedicine"`
+	OrderId        *uint  `json:"order_id" binding:"required_if=PaymentType product_order,omitempty,min=1"`
+	TelemedicineId *uint  `json:"telemedicine_id" binding:"required_if=PaymentType telemedicine,omitempty,min=1"`
+}
+
+func (r *CreatePaymentRequest) ToModel() *entity.Payment {
+	payment := &entity.Payment{
+		Provider:    r.Provider,
+		PaymentType: entity.PaymentType(r.PaymentType),
+	}
+	if payment.PaymentType == entity.PaymentTypeOrder {
+		payment.OrderId = r.OrderId
+	} else {
+		payment.TelemedicineId = r.TelemedicineId
+	}
+	return payment
+}
+
+type WebhookUri struct {
+	Provider string `uri:"provider" binding:"required"`
+}
+
+type PaymentResponse struct {
+	Id             uint            `json:"id"`
+	Provider       string          `json:"provider"`
+	Reference      string          `json:"reference"`
+	PaymentType    string          `json:"payment_type"`
+	OrderId        *uint           `json:"order_id,omitempty"`
+	TelemedicineId *uint           `json:"telemedicine_id,omitempty"`
+	Amount         decimal.Decimal `json:"amount"`
+	Status         string          `json:"status"`
+	PaymentUrl     string          `json:"payment_url,omitempty"`
+	PaidAt         *time.Time      `json:"paid_at,omitempty"`
+}
+
+func NewPaymentResponse(p *entity.Payment) *PaymentResponse {
+	return &PaymentResponse{
+		Id:             p.Id,
+		Provider:       p.Provider,
+		Reference:      p.Reference,
+		PaymentType:    string(p.PaymentType),
+		OrderId:        p.OrderId,
+		TelemedicineId: p.TelemedicineId,
+		Amount:         p.Amount,
+		Status:         string(p.Status),
+		PaymentUrl:     p.PaymentUrl,
+		PaidAt:         p.PaidAt,
+	}
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+)
+
+type PharmacyStaffUri struct {
+	PharmacyId uint `uri:"pharmacy_id" binding:"required,numeric"`
+	UserId     uint `uri:"user_id" binding:"required,numeric"`
+}
+
+type InviteStaffReq struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner pharmacist cashier"`
+}
+
+func (r *InviteStaffReq) ToModel(pharmacyId uint) *entity.PharmacyStaff {
+	return &entity.PharmacyStaff{
+		PharmacyId: pharmacyId,
+		User:       &entity.User{Email: r.Email},
+		Role:       entity.PharmacyStaffRole(r.Role),
+	}
+}
+
+type PharmacyStaffRes struct {
+	UserId    uint                     `json:"user_id"`
+	Email     string                   `json:"email"`
+	Name      string                   `json:"name"`
+	Role      entity.PharmacyStaffRole `json:"role"`
+	InvitedBy *uint                    `json:"invited_by"`
+	JoinedAt  time.Time                `json:"joined_at"`
+}
+
+func NewPharmacyStaffRes(s *entity.PharmacyStaff) *PharmacyStaffRes {
+	res := &PharmacyStaffRes{UserId: s.UserId, Role: s.Role, InvitedBy: s.InvitedBy, JoinedAt: s.CreatedAt}
+	if s.User != nil {
+		res.Email = s.User.Email
+		if s.User.AdminContact != nil {
+			res.Name = s.User.AdminContact.Name
+		}
+	}
+	return res
+}
@@ -11,14 +11,15 @@ import (
 )
 
 type ListProductQueryParam struct {
-	IsUser   *bool   `form:"is_user"`
-	Name     *string `form:"name"`
-	Category *int    `form:"category" binding:"omitempty,numeric,min=1"`
-	SortBy   *string `form:"sort_by" binding:"omitempty,oneof=name price"`
-	Order    *string `form:"order" binding:"omitempty,oneof=asc desc"`
-	Limit    *int    `form:"limit" binding:"omitempty,numeric,min=1"`
-	Page     *int    `form:"page" binding:"omitempty,numeric,min=1"`
-	IsHidden *bool   `form:"is_hidden" binding:"omitempty"`
+	IsUser    *bool    `form:"is_user"`
+	Name      *string  `form:"name"`
+	Category  *int     `form:"category" binding:"omitempty,numeric,min=1"`
+	MinRating *float64 `form:"min_rating" binding:"omitempty,min=0,max=5"`
+	SortBy    *string  `form:"sort_by" binding:"omitempty,oneof=name price rating"`
+	Order     *string  `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit     *int     `form:"limit" binding:"omitempty,numeric,min=1"`
+	Page      *int     `form:"page" binding:"omitempty,numeric,min=1"`
+	IsHidden  *bool    `form:"is_hidden" binding:"omitempty"`
 }
 
 func (qp *ListProductQueryParam) ToQuery() (*valueobject.Query, error) {
@@ -49,6 +50,9 @@ func (qp *ListProductQueryParam) ToQuery() (*valueobject.Query, error) {
 	if qp.IsHidden != nil {
 		query.Condition("is_hidden", valueobject.Equal, *qp.IsHidden)
 	}
+	if qp.MinRating != nil {
+		query.Condition("min_rating", valueobject.GreaterThanEqual, *qp.MinRating)
+	}
 
 	return query, nil
 }
@@ -140,6 +144,8 @@ type SimpleProductResponse struct {
 	FloorPrice  string `json:"floor_price"`
 	SellingUnit string `json:"selling_unit"`
 	Image       string `json:"image"`
+	Rating      string `json:"rating"`
+	ReviewCount int    `json:"review_count"`
 }
 
 type ProductPriceRangeResponse struct {
@@ -158,6 +164,8 @@ type ProductPriceRangeResponse struct {
 	TopPrice          decimal.Decimal `json:"top_price"`
 	FloorPrice        decimal.Decimal `json:"floor_price"`
 	SellingUnit       string          `json:"selling_unit"`
+	Rating            string          `json:"rating"`
+	ReviewCount       int             `json:"review_count"`
 	*DrugResponse
 }
 
@@ -201,6 +209,8 @@ func NewFromPharmacyProduct(product *entity.Product, top, floor decimal.Decimal)
 		Image:             product.Image,
 		DrugResponse:      drug,
 		IsHidden:          product.IsHidden,
+		Rating:            product.RatingAverage.StringFixed(2),
+		ReviewCount:       product.ReviewCount,
 	}
 }
 
@@ -47,11 +47,12 @@ func (p *PharmacyProductReq) ToModel() (*entity.PharmacyProduct, error) {
 }
 
 type ProductPharmacyRes struct {
-	Id       uint             `json:"id"`
-	Product  *ProductResponse `json:"product,omitempty"`
-	Stock    int              `json:"stock"`
-	Price    decimal.Decimal  `json:"price"`
-	IsActive bool             `json:"is_active"`
+	Id             uint             `json:"id"`
+	Product        *ProductResponse `json:"product,omitempty"`
+	Stock          int              `json:"stock"`
+	AvailableStock int              `json:"available_stock"`
+	Price          decimal.Decimal  `json:"price"`
+	IsActive       bool             `json:"is_active"`
 }
 
 func NewProductPhamarcyRes(p *entity.PharmacyProduct) *ProductPharmacyRes {
@@ -62,10 +63,11 @@ func NewProductPhamarcyRes(p *entity.PharmacyProduct) *ProductPharmacyRes {
 	}
 
 	return &ProductPharmacyRes{Id: p.Id,
-		Product:  product,
-		Stock:    p.Stock,
-		Price:    p.Price,
-		IsActive: p.IsActive}
+		Product:        product,
+		Stock:          p.Stock,
+		AvailableStock: p.AvailableStock,
+		Price:          p.Price,
+		IsActive:       p.IsActive}
 }
 
 type ListPharmacyProductQueryParam struct {
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type ProductReviewReq struct {
+	OrderId uint   `form:"order_id" binding:"required,numeric,min=1"`
+	Rating  int    `form:"rating" binding:"required,min=1,max=5"`
+	Review  string `form:"review" binding:"required,max=1000"`
+}
+
+func (r *ProductReviewReq) ToModel(productId uint) *entity.ProductReview {
+	return &entity.ProductReview{ProductOrderId: r.OrderId, ProductId: productId, Rating: r.Rating, Review: r.Review}
+}
+
+type ModerateProductReviewReq struct {
+	IsHidden *bool `json:"is_hidden" binding:"required"`
+}
+
+type ProductReviewQueryParam struct {
+	Rating   *int    `form:"rating" binding:"omitempty,min=1,max=5"`
+	IsHidden *bool   `form:"is_hidden"`
+	SortBy   *string `form:"sort_by" binding:"omitempty,oneof=rating created_at"`
+	Order    *string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit    *int    `form:"limit" binding:"omitempty,numeric,min=1"`
+	Page     *int    `form:"page" binding:"omitempty,numeric,min=1"`
+}
+
+func (qp *ProductReviewQueryParam) ToQuery() *valueobject.Query {
+	query := valueobject.NewQuery()
+	if qp.Page != nil {
+		query.WithPage(*qp.Page)
+	}
+	if qp.Limit != nil {
+		query.WithLimit(*qp.Limit)
+	}
+	if qp.Order != nil {
+		query.WithOrder(valueobject.Order(*qp.Order))
+	} else {
+		query.WithOrder(valueobject.OrderDesc)
+	}
+	if qp.SortBy != nil {
+		query.WithSortBy("\"product_reviews\"." + *qp.SortBy)
+	} else {
+		query.WithSortBy("\"product_reviews\".created_at")
+	}
+	if qp.Rating != nil {
+		query.Condition("rating", valueobject.Equal, *qp.Rating)
+	}
+	if qp.IsHidden != nil {
+		query.Condition("is_hidden", valueobject.Equal, *qp.IsHidden)
+	}
+	return query
+}
+
+type ProductReviewRes struct {
+	Id        uint        `json:"id"`
+	OrderId   uint        `json:"order_id"`
+	ProductId uint        `json:"product_id"`
+	Rating    int         `json:"rating"`
+	Review    string      `json:"review"`
+	IsHidden  bool        `json:"is_hidden"`
+	Photos    []string    `json:"photos"`
+	Profile   *ProfileRes `json:"profile,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func NewProductReviewRes(r *entity.ProductReview) *ProductReviewRes {
+	var profile *ProfileRes
+	if r.Profile != nil {
+		profile = NewProfileTeleRes(r.Profile)
+	}
+	photos := []string{}
+	for _, photo := range r.Photos {
+		photos = append(photos, photo.Image)
+	}
+	return &ProductReviewRes{
+		Id:        r.Id,
+		OrderId:   r.ProductOrderId,
+		ProductId: r.ProductId,
+		Rating:    r.Rating,
+		Review:    r.Review,
+		IsHidden:  r.IsHidden,
+		Photos:    photos,
+		Profile:   profile,
+		CreatedAt: r.CreatedAt,
+	}
+}
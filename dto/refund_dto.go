@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+)
+
+type RefundItemRequest struct {
+	OrderItemId uint `json:"order_item_id" binding:"required,min=1"`
+	Quantity    int  `json:"quantity" binding:"required,min=1"`
+}
+
+type CreateRefundRequest struct {
+	Reason string               `json:"reason" binding:"required,max=255"`
+	Items  []*RefundItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+func (r *CreateRefundRequest) ToModel(orderId uint) *entity.Refund {
+	refund := &entity.Refund{OrderId: &orderId, Reason: r.Reason}
+	for _, item := range r.Items {
+		refund.RefundItems = append(refund.RefundItems, &entity.RefundItem{
+			OrderItemId: item.OrderItemId,
+			Quantity:    item.Quantity,
+		})
+	}
+	return refund
+}
+
+type ListRefundQueryParam struct {
+	Status      *string `form:"status" binding:"omitempty,oneof=requested approved paid"`
+	PaymentType *string `form:"payment_type" binding:"omitempty,oneof=product_order telemedicine"`
+	Order       *string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit       *int    `form:"limit" binding:"omitempty,numeric,min=1"`
+	Page        *int    `form:"page" binding:"omitempty,numeric,min=1"`
+}
+
+func (qp *ListRefundQueryParam) ToQuery() (*valueobject.Query, error) {
+	query := valueobject.NewQuery().WithSortBy("\"refunds\".created_at").WithOrder(valueobject.OrderDesc)
+
+	if qp.Page != nil {
+		query.WithPage(*qp.Page)
+	}
+	if qp.Limit != nil {
+		query.WithLimit(*qp.Limit)
+	}
+
+	if qp.Order != nil {
+		query.WithOrder(valueobject.Order(*qp.Order))
+	}
+
+	if qp.Status != nil {
+		query.Condition("status", valueobject.Equal, *qp.Status)
+	}
+
+	if qp.PaymentType != nil {
+		query.Condition("payment_type", valueobject.Equal, *qp.PaymentType)
+	}
+
+	return query, nil
+}
+
+type RefundItemResponse struct {
+	OrderItemId uint            `json:"order_item_id"`
+	ProductName string          `json:"product_name,omitempty"`
+	Quantity    int             `json:"quantity"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+type RefundResponse struct {
+	Id             uint                  `json:"id"`
+	PaymentType    string                `json:"payment_type"`
+	OrderId        *uint                 `json:"order_id,omitempty"`
+	ShipmentId     *uint                 `json:"shipment_id,omitempty"`
+	PharmacyName   string                `json:"pharmacy_name,omitempty"`
+	TelemedicineId *uint                 `json:"telemedicine_id,omitempty"`
+	Amount         decimal.Decimal       `json:"amount"`
+	Reason         string                `json:"reason"`
+	Status         string                `json:"status"`
+	ApprovedAt     *time.Time            `json:"approved_at,omitempty"`
+	PaidAt         *time.Time            `json:"paid_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	Items          []*RefundItemResponse `json:"items,omitempty"`
+}
+
+func NewRefundResponse(r *entity.Refund) *RefundResponse {
+	res := &RefundResponse{
+		Id:             r.Id,
+		PaymentType:    string(r.PaymentType),
+		OrderId:        r.OrderId,
+		ShipmentId:     r.ShipmentId,
+		TelemedicineId: r.TelemedicineId,
+		Amount:         r.Amount,
+		Reason:         r.Reason,
+		Status:         string(r.Status),
+		ApprovedAt:     r.ApprovedAt,
+		PaidAt:         r.PaidAt,
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.Shipment != nil && r.Shipment.Pharmacy != nil {
+		res.PharmacyName = r.Shipment.Pharmacy.Name
+	}
+	for _, item := range r.RefundItems {
+		itemRes := &RefundItemResponse{
+			OrderItemId: item.OrderItemId,
+			Quantity:    item.Quantity,
+			Amount:      item.Amount,
+		}
+		if item.OrderItem != nil && item.OrderItem.PharmacyProduct.Product != nil {
+			itemRes.ProductName = item.OrderItem.PharmacyProduct.Product.Name
+		}
+		res.Items = append(res.Items, itemRes)
+	}
+	return res
+}
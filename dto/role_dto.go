@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"github.com/night1010/everhealth/entity"
+)
+
+type RoleReq struct {
+	Name        string   `json:"name" binding:"required,max=50"`
+	BaseRoleId  uint     `json:"base_role_id" binding:"required,oneof=3 4"`
+	Permissions []string `json:"permissions" binding:"required,dive,required"`
+}
+
+func (r *RoleReq) ToModel() *entity.Role {
+	role := &entity.Role{Name: r.Name, BaseRoleId: entity.RoleId(r.BaseRoleId)}
+	for _, name := range r.Permissions {
+		role.Permissions = append(role.Permissions, &entity.Permission{Name: name})
+	}
+	return role
+}
+
+type UpdateRoleReq struct {
+	Name        string   `json:"name" binding:"required,max=50"`
+	Permissions []string `json:"permissions" binding:"required,dive,required"`
+}
+
+func (r *UpdateRoleReq) ToModel(roleId uint) *entity.Role {
+	role := &entity.Role{Id: entity.RoleId(roleId), Name: r.Name}
+	for _, name := range r.Permissions {
+		role.Permissions = append(role.Permissions, &entity.Permission{Name: name})
+	}
+	return role
+}
+
+type AssignRoleReq struct {
+	RoleId uint `json:"role_id" binding:"required"`
+}
+
+type PermissionRes struct {
+	Id          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func NewPermissionRes(p *entity.Permission) *PermissionRes {
+	return &PermissionRes{Id: p.Id, Name: p.Name, Description: p.Description}
+}
+
+type RoleDetailRes struct {
+	Id          uint     `json:"id"`
+	Name        string   `json:"name"`
+	BaseRoleId  uint     `json:"base_role_id"`
+	IsSystem    bool     `json:"is_system"`
+	Permissions []string `json:"permissions"`
+}
+
+func NewRoleDetailRes(r *entity.Role) *RoleDetailRes {
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+	return &RoleDetailRes{
+		Id:          uint(r.Id),
+		Name:        r.Name,
+		BaseRoleId:  uint(r.GetBaseRoleId()),
+		IsSystem:    r.IsSystem,
+		Permissions: permissions,
+	}
+}
+
+type RoleUri struct {
+	Id uint `uri:"id" binding:"required,numeric"`
+}
@@ -54,7 +54,7 @@ func NewProductStockMutationRes(p *entity.PharmacyProduct) *ProductStockMutation
 	}
 	return &ProductStockMutationRes{Id: p.Id, Name: p.Product.Name, Image: p.Product.Image, Pharmacy: pharmacy}
 }
-func NewStockMutationRes(u *entity.StockMutation, adminId uint) *StockMutationRes {
+func NewStockMutationRes(u *entity.StockMutation) *StockMutationRes {
 	var toProduct *ProductStockMutationRes
 	var fromProduct *ProductStockMutationRes
 	if u.ToPharmacyProduct != nil {
@@ -63,10 +63,7 @@ func NewStockMutationRes(u *entity.StockMutation, adminId uint) *StockMutationRe
 	if u.FromPharmacyProduct != nil {
 		fromProduct = NewProductStockMutationRes(u.FromPharmacyProduct)
 	}
-	isRequest := false
-	if adminId == u.FromPharmacyProduct.Pharmacy.AdminId {
-		isRequest = true
-	}
+	isRequest := u.IsRequest
 	return &StockMutationRes{Id: u.Id, ToPharmacyProduct: toProduct, FormPharmacyProduct: fromProduct, Quantity: u.Quantity, Status: u.Status, MutatedAt: u.MutatedAt, IsRequest: &isRequest}
 }
 
@@ -15,6 +15,11 @@ type TelemedicineCreateReq struct {
 	DoctorId uint `json:"doctor_id" binding:"required,numeric,min=1"`
 }
 
+type TelemedicineBookingReq struct {
+	DoctorId    uint      `json:"doctor_id" binding:"required,numeric,min=1"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+}
+
 type SickLeaveReq struct {
 	StartDate string `json:"start_date" binding:"required"`
 	EndDate   string `json:"end_date" binding:"required"`
@@ -45,7 +50,57 @@ func (r *SickLeaveReq) ToModel() (*SickLeave, error) {
 }
 
 type Prescription struct {
-	Prescription string `json:"prescription" binding:"required"`
+	Items []*PrescriptionItemReq `json:"items" binding:"required,min=1,dive"`
+}
+
+type PrescriptionItemReq struct {
+	ProductId uint   `json:"product_id" binding:"required,min=1"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+	Dosage    string `json:"dosage" binding:"required,max=255"`
+	Frequency string `json:"frequency" binding:"required,max=255"`
+	Duration  string `json:"duration" binding:"required,max=255"`
+	Notes     string `json:"notes" binding:"max=255"`
+}
+
+func (r *Prescription) ToModel(telemedicineId uint) []*entity.PrescriptionItem {
+	var items []*entity.PrescriptionItem
+	for _, item := range r.Items {
+		items = append(items, &entity.PrescriptionItem{
+			TelemedicineId: telemedicineId,
+			ProductId:      item.ProductId,
+			Quantity:       item.Quantity,
+			Dosage:         item.Dosage,
+			Frequency:      item.Frequency,
+			Duration:       item.Duration,
+			Notes:          item.Notes,
+		})
+	}
+	return items
+}
+
+type PrescriptionItemRes struct {
+	ProductId   uint   `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Quantity    int    `json:"quantity"`
+	Dosage      string `json:"dosage"`
+	Frequency   string `json:"frequency"`
+	Duration    string `json:"duration"`
+	Notes       string `json:"notes"`
+}
+
+func NewPrescriptionItemRes(item *entity.PrescriptionItem) *PrescriptionItemRes {
+	res := &PrescriptionItemRes{
+		ProductId: item.ProductId,
+		Quantity:  item.Quantity,
+		Dosage:    item.Dosage,
+		Frequency: item.Frequency,
+		Duration:  item.Duration,
+		Notes:     item.Notes,
+	}
+	if item.Product != nil {
+		res.ProductName = item.Product.Name
+	}
+	return res
 }
 
 type TelemedicineParams struct {
@@ -53,7 +108,7 @@ type TelemedicineParams struct {
 	Order  *string `form:"order" binding:"omitempty,oneof=asc desc"`
 	Limit  *int    `form:"limit" binding:"omitempty,numeric,min=1"`
 	Page   *int    `form:"page" binding:"omitempty,numeric,min=1"`
-	Status *string `form:"status" binding:"omitempty,oneof=ongoing ended"`
+	Status *string `form:"status" binding:"omitempty,oneof=booked ongoing ended"`
 }
 
 func (qp *TelemedicineParams) ToQuery() *valueobject.Query {
@@ -78,34 +133,41 @@ func (qp *TelemedicineParams) ToQuery() *valueobject.Query {
 }
 
 type TelemedicineRes struct {
-	Id              uint                      `json:"id"`
-	OrderedAt       time.Time                 `json:"ordered_at"`
-	ExpiredAt       time.Time                 `json:"expired_at"`
-	Status          entity.TelemedicineStatus `json:"status"`
-	TotalPayment    decimal.Decimal           `json:"total_payment"`
-	Proof           string                    `json:"proof"`
-	SickLeavePdf    string                    `json:"sick_leave_pdf"`
-	PrescriptionPdf string                    `json:"prescription_pdf"`
-	Profile         *ProfileRes               `json:"profile"`
-	Doctor          *DoctorRes                `json:"doctor"`
-	Chats           []*ChatRes                `json:"chats"`
+	Id                uint                      `json:"id"`
+	OrderedAt         time.Time                 `json:"ordered_at"`
+	ExpiredAt         time.Time                 `json:"expired_at"`
+	Status            entity.TelemedicineStatus `json:"status"`
+	ScheduledAt       *time.Time                `json:"scheduled_at,omitempty"`
+	ScheduledEndAt    *time.Time                `json:"scheduled_end_at,omitempty"`
+	TotalPayment      decimal.Decimal           `json:"total_payment"`
+	Proof             string                    `json:"proof"`
+	SickLeavePdf      string                    `json:"sick_leave_pdf"`
+	PrescriptionPdf   string                    `json:"prescription_pdf"`
+	PrescriptionItems []*PrescriptionItemRes    `json:"prescription_items,omitempty"`
+	Profile           *ProfileRes               `json:"profile"`
+	Doctor            *DoctorRes                `json:"doctor"`
+	Chats             []*ChatRes                `json:"chats"`
+	UnreadCount       int                       `json:"unread_count"`
 }
 
 type ProfileRes struct {
-	Id        uint   `json:"id"`
-	Name      string `json:"name"`
-	Image     string `json:"image"`
-	BirthDate string `json:"birth_date"`
+	Id         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Image      string     `json:"image"`
+	BirthDate  string     `json:"birth_date"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
 }
 
 type DoctorRes struct {
-	Id     uint   `json:"id"`
-	Name   string `json:"name"`
-	Image  string `json:"image"`
-	Status string `json:"status"`
+	Id         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Image      string     `json:"image"`
+	Status     string     `json:"status"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
 }
 
 type ChatRes struct {
+	Id          uint               `json:"id"`
 	UserId      uint               `json:"user_id"`
 	ChatTime    time.Time          `json:"chat_time"`
 	Message     string             `json:"message"`
@@ -125,19 +187,24 @@ func NewTelemedicinRes(u *entity.Telemedicine) *TelemedicineRes {
 	if u.Doctor != nil {
 		doctor = NewDoctorTeleRes(u.Doctor)
 	}
-	return &TelemedicineRes{Id: u.Id, OrderedAt: u.OrderedAt, ExpiredAt: u.ExpiredAt, Status: u.Status, TotalPayment: u.TotalPayment, Proof: u.Proof, PrescriptionPdf: u.PrescriptionPdf, SickLeavePdf: u.SickLeavePdf, Doctor: doctor, Profile: profile, Chats: NewChatsRes(u.Chats)}
+	var prescriptionItems []*PrescriptionItemRes
+	for _, item := range u.PrescriptionItems {
+		prescriptionItems = append(prescriptionItems, NewPrescriptionItemRes(item))
+	}
+	return &TelemedicineRes{Id: u.Id, OrderedAt: u.OrderedAt, ExpiredAt: u.ExpiredAt, Status: u.Status, ScheduledAt: u.ScheduledAt, ScheduledEndAt: u.ScheduledEndAt, TotalPayment: u.TotalPayment, Proof: u.Proof, PrescriptionPdf: u.PrescriptionPdf, PrescriptionItems: prescriptionItems, SickLeavePdf: u.SickLeavePdf, Doctor: doctor, Profile: profile, Chats: NewChatsRes(u.Chats), UnreadCount: u.UnreadCount}
 }
 
 func NewProfileTeleRes(u *entity.Profile) *ProfileRes {
-	return &ProfileRes{Id: u.UserId, Name: u.Name, Image: u.Image, BirthDate: u.Birthdate.Format("2006-01-02")}
+	return &ProfileRes{Id: u.UserId, Name: u.Name, Image: u.Image, BirthDate: u.Birthdate.Format("2006-01-02"), LastSeenAt: u.LastSeenAt}
 }
 
 func NewDoctorTeleRes(u *entity.DoctorProfile) *DoctorRes {
-	return &DoctorRes{Id: u.ProfileId, Name: u.Profile.Name, Image: u.Profile.Image, Status: entity.DoctorStatusMap[u.Status]}
+	return &DoctorRes{Id: u.ProfileId, Name: u.Profile.Name, Image: u.Profile.Image, Status: entity.DoctorStatusMap[u.Status], LastSeenAt: u.Profile.LastSeenAt}
 }
 
 func NewChatRes(c *entity.Chat) *ChatRes {
 	return &ChatRes{
+		Id:          c.Id,
 		UserId:      c.UserId,
 		ChatTime:    c.ChatTime,
 		Message:     c.Message,
@@ -148,3 +215,29 @@ func NewChatRes(c *entity.Chat) *ChatRes {
 func NewChatsRes(cs []*entity.Chat) []*ChatRes {
 	return newResponsesFromEntities(cs, NewChatRes)
 }
+
+type TelemedicineQueueReq struct {
+	DoctorId uint `json:"doctor_id" binding:"required,numeric,min=1"`
+}
+
+type TelemedicineQueueRes struct {
+	Id                   uint                           `json:"id"`
+	DoctorId             uint                           `json:"doctor_id"`
+	Status               entity.TelemedicineQueueStatus `json:"status"`
+	Position             int                            `json:"position"`
+	EstimatedWaitMinutes int                            `json:"estimated_wait_minutes"`
+	TelemedicineId       *uint                          `json:"telemedicine_id,omitempty"`
+	JoinedAt             time.Time                      `json:"joined_at"`
+}
+
+func NewTelemedicineQueueRes(q *entity.TelemedicineQueue) *TelemedicineQueueRes {
+	return &TelemedicineQueueRes{
+		Id:                   q.Id,
+		DoctorId:             q.DoctorId,
+		Status:               q.Status,
+		Position:             q.Position,
+		EstimatedWaitMinutes: int(q.EstimatedWait.Minutes()),
+		TelemedicineId:       q.TelemedicineId,
+		JoinedAt:             q.JoinedAt,
+	}
+}
@@ -40,12 +40,13 @@ type UpdateProfileRequest struct {
 }
 
 type GetDoctorsParam struct {
-	Name           *string `form:"name"`
-	Specialization *int    `form:"specialization" binding:"omitempty,numeric,min=1"`
-	SortBy         *string `form:"sort_by" binding:"omitempty,oneof=name fee"`
-	Order          *string `form:"order" binding:"omitempty,oneof=asc desc"`
-	Limit          *int    `form:"limit" binding:"omitempty,numeric,min=1"`
-	Page           *int    `form:"page" binding:"omitempty,numeric,min=1"`
+	Name           *string  `form:"name"`
+	Specialization *int     `form:"specialization" binding:"omitempty,numeric,min=1"`
+	MinRating      *float64 `form:"min_rating" binding:"omitempty,min=1,max=5"`
+	SortBy         *string  `form:"sort_by" binding:"omitempty,oneof=name fee rating"`
+	Order          *string  `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit          *int     `form:"limit" binding:"omitempty,numeric,min=1"`
+	Page           *int     `form:"page" binding:"omitempty,numeric,min=1"`
 }
 
 type GetDoctorsResponse struct {
@@ -58,6 +59,8 @@ type GetDoctorsResponse struct {
 	YearOfExperience uint   `json:"yoe"`
 	Certificate      string `json:"certificate"`
 	Status           string `json:"status"`
+	Rating           string `json:"rating"`
+	ReviewCount      int    `json:"review_count"`
 }
 
 type DoctorUri struct {
@@ -72,6 +75,8 @@ type DoctorDetailResponse struct {
 	YearOfExperience uint   `json:"yoe"`
 	Certificate      string `json:"certificate"`
 	Status           string `json:"status"`
+	Rating           string `json:"rating"`
+	ReviewCount      int    `json:"review_count"`
 }
 
 type UpdateStatusRequest struct {
@@ -113,6 +118,10 @@ func (dp *GetDoctorsParam) ToQuery() (*valueobject.Query, error) {
 		query.Condition("specialization", valueobject.Equal, *dp.Specialization)
 	}
 
+	if dp.MinRating != nil {
+		query.Condition("min_rating", valueobject.GreaterThanEqual, *dp.MinRating)
+	}
+
 	return query, nil
 }
 
@@ -159,6 +168,8 @@ func ToDoctorDetailDTO(profile *entity.Profile, doctorProfile *entity.DoctorProf
 	doctorDetailResponse.YearOfExperience = doctorProfile.YearOfExperience
 	doctorDetailResponse.Certificate = doctorProfile.Certificate
 	doctorDetailResponse.Status = entity.DoctorStatusMap[doctorProfile.Status]
+	doctorDetailResponse.Rating = doctorProfile.RatingAverage.StringFixed(2)
+	doctorDetailResponse.ReviewCount = doctorProfile.ReviewCount
 	return doctorDetailResponse
 }
 
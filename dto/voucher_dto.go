@@ -0,0 +1,154 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+)
+
+type VoucherUri struct {
+	Id uint `uri:"id" binding:"required,numeric"`
+}
+
+type VoucherRequest struct {
+	Code              string    `json:"code" binding:"required,alphanum,max=50"`
+	Name              string    `json:"name" binding:"required,max=255"`
+	Description       string    `json:"description"`
+	Type              string    `json:"type" binding:"required,oneof=percentage fixed free_shipping"`
+	Value             string    `json:"value" binding:"required_unless=Type free_shipping,omitempty,numeric"`
+	MaxDiscount       *string   `json:"max_discount" binding:"omitempty,numeric"`
+	MinSpend          string    `json:"min_spend" binding:"omitempty,numeric"`
+	ProductCategoryId *uint     `json:"product_category_id" binding:"omitempty,min=1"`
+	PerUserLimit      int       `json:"per_user_limit" binding:"min=0"`
+	Quota             int       `json:"quota" binding:"min=0"`
+	StartAt           time.Time `json:"start_at" binding:"required"`
+	EndAt             time.Time `json:"end_at" binding:"required"`
+	IsActive          *bool     `json:"is_active" binding:"required"`
+}
+
+func (r *VoucherRequest) ToModel() (*entity.Voucher, error) {
+	voucher := &entity.Voucher{
+		Code:              r.Code,
+		Name:              r.Name,
+		Description:       r.Description,
+		Type:              entity.VoucherType(r.Type),
+		ProductCategoryId: r.ProductCategoryId,
+		PerUserLimit:      r.PerUserLimit,
+		Quota:             r.Quota,
+		StartAt:           r.StartAt,
+		EndAt:             r.EndAt,
+		IsActive:          *r.IsActive,
+	}
+	if r.Value != "" {
+		value, err := decimal.NewFromString(r.Value)
+		if err != nil {
+			return nil, err
+		}
+		voucher.Value = value
+	}
+	if r.MaxDiscount != nil {
+		maxDiscount, err := decimal.NewFromString(*r.MaxDiscount)
+		if err != nil {
+			return nil, err
+		}
+		voucher.MaxDiscount = decimal.NewNullDecimal(maxDiscount)
+	}
+	if r.MinSpend != "" {
+		minSpend, err := decimal.NewFromString(r.MinSpend)
+		if err != nil {
+			return nil, err
+		}
+		voucher.MinSpend = minSpend
+	}
+	return voucher, nil
+}
+
+type ListVoucherQueryParam struct {
+	Name     *string `form:"name"`
+	Type     *string `form:"type" binding:"omitempty,oneof=percentage fixed free_shipping"`
+	IsActive *bool   `form:"is_active"`
+	SortBy   *string `form:"sort_by" binding:"omitempty,oneof=code start_at end_at created_at"`
+	Order    *string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit    *int    `form:"limit" binding:"omitempty,numeric,min=1"`
+	Page     *int    `form:"page" binding:"omitempty,numeric,min=1"`
+}
+
+func (qp *ListVoucherQueryParam) ToQuery() (*valueobject.Query, error) {
+	query := valueobject.NewQuery()
+
+	if qp.Page != nil {
+		query.WithPage(*qp.Page)
+	}
+	if qp.Limit != nil {
+		query.WithLimit(*qp.Limit)
+	}
+
+	if qp.Order != nil {
+		query.WithOrder(valueobject.Order(*qp.Order))
+	}
+
+	if qp.SortBy != nil {
+		query.WithSortBy(*qp.SortBy)
+	} else {
+		query.WithSortBy("created_at").WithOrder(valueobject.OrderDesc)
+	}
+
+	if qp.Name != nil {
+		query.Condition("name", valueobject.ILike, *qp.Name)
+	}
+	if qp.Type != nil {
+		query.Condition("type", valueobject.Equal, *qp.Type)
+	}
+	if qp.IsActive != nil {
+		query.Condition("is_active", valueobject.Equal, *qp.IsActive)
+	}
+
+	return query, nil
+}
+
+type VoucherResponse struct {
+	Id                uint             `json:"id"`
+	Code              string           `json:"code"`
+	Name              string           `json:"name"`
+	Description       string           `json:"description"`
+	Type              string           `json:"type"`
+	Value             decimal.Decimal  `json:"value"`
+	MaxDiscount       *decimal.Decimal `json:"max_discount,omitempty"`
+	MinSpend          decimal.Decimal  `json:"min_spend"`
+	ProductCategoryId *uint            `json:"product_category_id,omitempty"`
+	ProductCategory   string           `json:"product_category,omitempty"`
+	PerUserLimit      int              `json:"per_user_limit"`
+	Quota             int              `json:"quota"`
+	UsedCount         int              `json:"used_count"`
+	StartAt           time.Time        `json:"start_at"`
+	EndAt             time.Time        `json:"end_at"`
+	IsActive          bool             `json:"is_active"`
+}
+
+func NewVoucherResponse(voucher *entity.Voucher) *VoucherResponse {
+	res := &VoucherResponse{
+		Id:                voucher.Id,
+		Code:              voucher.Code,
+		Name:              voucher.Name,
+		Description:       voucher.Description,
+		Type:              string(voucher.Type),
+		Value:             voucher.Value,
+		MinSpend:          voucher.MinSpend,
+		ProductCategoryId: voucher.ProductCategoryId,
+		PerUserLimit:      voucher.PerUserLimit,
+		Quota:             voucher.Quota,
+		UsedCount:         voucher.UsedCount,
+		StartAt:           voucher.StartAt,
+		EndAt:             voucher.EndAt,
+		IsActive:          voucher.IsActive,
+	}
+	if voucher.MaxDiscount.Valid {
+		res.MaxDiscount = &voucher.MaxDiscount.Decimal
+	}
+	if voucher.ProductCategory != nil {
+		res.ProductCategory = voucher.ProductCategory.Name
+	}
+	return res
+}
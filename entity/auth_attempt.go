@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+)
+
+type AuthAttemptScope string
+
+const (
+	LoginScope          AuthAttemptScope = "login"
+	ForgotPasswordScope AuthAttemptScope = "forgot_password"
+	RegisterScope       AuthAttemptScope = "register"
+)
+
+type AuthAttempt struct {
+	Id           uint             `gorm:"primaryKey;autoIncrement"`
+	Scope        AuthAttemptScope `gorm:"not null;uniqueIndex:idx_auth_attempt_scope_identifier"`
+	Identifier   string           `gorm:"not null;uniqueIndex:idx_auth_attempt_scope_identifier"`
+	Failures     int              `gorm:"not null"`
+	LockedUntil  *time.Time
+	LastFailedAt time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -14,9 +14,14 @@ const (
 	MessageTypePdf   MessageType = "pdf"
 )
 
+const (
+	ChatAttachmentFolder = "chat-attachment"
+	ChatAttachmentPrefix = "chat-attachment-"
+)
+
 type Chat struct {
-	Id             uint      `gorm:"primaryKey;autoIncrement"`
-	TelemedicineId uint      `gorm:"not null"`
+	Id             uint      `gorm:"primaryKey;autoIncrement;index:idx_chat_telemedicine_id,priority:2"`
+	TelemedicineId uint      `gorm:"not null;index:idx_chat_telemedicine_id,priority:1"`
 	UserId         uint      `gorm:"not null"`
 	ChatTime       time.Time `gorm:"not null"`
 	Telemedicine   Telemedicine
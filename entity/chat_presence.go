@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type ChatPresence struct {
+	Id             uint      `gorm:"primaryKey;autoIncrement"`
+	ClientId       string    `gorm:"not null;unique"`
+	TelemedicineId uint      `gorm:"not null;index"`
+	UserId         uint      `gorm:"not null;index"`
+	ExpiredAt      time.Time `gorm:"not null;index"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type ChatReceipt struct {
+	Id             uint `gorm:"primaryKey;autoIncrement"`
+	TelemedicineId uint `gorm:"not null;uniqueIndex:idx_chat_receipt_telemedicine_user"`
+	UserId         uint `gorm:"not null;uniqueIndex:idx_chat_receipt_telemedicine_user"`
+	DeliveredUpTo  uint `gorm:"not null;default:0"`
+	ReadUpTo       uint `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
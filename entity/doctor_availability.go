@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DoctorAvailability struct {
+	Id        uint         `gorm:"primaryKey;autoIncrement"`
+	DoctorId  uint         `gorm:"not null;index"`
+	Weekday   time.Weekday `gorm:"not null"`
+	StartTime time.Time    `gorm:"not null"`
+	EndTime   time.Time    `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+type DoctorAvailabilityException struct {
+	Id          uint      `gorm:"primaryKey;autoIncrement"`
+	DoctorId    uint      `gorm:"not null;index"`
+	Date        time.Time `gorm:"not null;type:date"`
+	IsAvailable bool      `gorm:"not null"`
+	StartTime   *time.Time
+	EndTime     *time.Time
+	Reason      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt
+}
@@ -31,6 +31,8 @@ type DoctorProfile struct {
 	YearOfExperience uint             `gorm:"not null"`
 	Status           StatusDoctor     `gorm:"not null"`
 	Fee              decimal.Decimal  `gorm:"type:numeric"`
+	RatingAverage    decimal.Decimal  `gorm:"not null;type:numeric;default:0"`
+	ReviewCount      int              `gorm:"not null;default:0"`
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	DeletedAt        gorm.DeletedAt
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DoctorReview struct {
+	Id             uint     `gorm:"primaryKey;autoIncrement"`
+	TelemedicineId uint     `gorm:"not null;uniqueIndex"`
+	DoctorId       uint     `gorm:"not null;index"`
+	ProfileId      uint     `gorm:"not null"`
+	Profile        *Profile `gorm:"foreignKey:ProfileId;references:UserId"`
+	Rating         int      `gorm:"not null"`
+	Review         string   `gorm:"not null"`
+	IsHidden       bool     `gorm:"not null;default:false"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
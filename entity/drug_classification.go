@@ -7,9 +7,10 @@ import (
 )
 
 type DrugClassification struct {
-	Id        uint   `gorm:"primaryKey;autoIncrement"`
-	Name      string `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt
+	Id                   uint   `gorm:"primaryKey;autoIncrement"`
+	Name                 string `gorm:"not null"`
+	RequiresPrescription bool   `gorm:"not null;default:false"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	DeletedAt            gorm.DeletedAt
 }
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Invoice struct {
+	Id             uint      `gorm:"primaryKey;autoIncrement"`
+	OrderId        uint      `gorm:"not null;unique"`
+	Number         string    `gorm:"not null"`
+	Url            string    `gorm:"not null"`
+	Key            string    `gorm:"not null"`
+	OrderUpdatedAt time.Time `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
+
+const (
+	InvoiceFolder = "invoice"
+	InvoicePrefix = "invoice-"
+)
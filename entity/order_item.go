@@ -11,6 +11,7 @@ type OrderItem struct {
 	Id                uint            `gorm:"primaryKey;autoIncrement"`
 	OrderId           uint            `gorm:"not null"`
 	Order             ProductOrder    `gorm:"foreignKey:OrderId;references:Id"`
+	ShipmentId        uint            `gorm:"not null"`
 	PharmacyProductId uint            `gorm:"not null"`
 	PharmacyProduct   PharmacyProduct `gorm:"foreignKey:PharmacyProductId;references:Id"`
 	Quantity          int             `gorm:"not null"`
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PrescriptionStatus string
+
+const (
+	PrescriptionNotRequired PrescriptionStatus = ""
+	PrescriptionPending     PrescriptionStatus = "pending"
+	PrescriptionApproved    PrescriptionStatus = "approved"
+	PrescriptionRejected    PrescriptionStatus = "rejected"
+)
+
+type OrderPrescription struct {
+	Id             uint `gorm:"primaryKey;autoIncrement"`
+	ProfileId      uint `gorm:"not null"`
+	OrderId        *uint
+	TelemedicineId *uint
+	File           string `gorm:"not null"`
+	FileKey        string `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
+
+const (
+	OrderPrescriptionFolder = "order-prescription"
+	OrderPrescriptionPrefix = "order-prescription-"
+)
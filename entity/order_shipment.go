@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type OrderShipment struct {
+	Id                     uint            `gorm:"primaryKey;autoIncrement"`
+	OrderId                uint            `gorm:"not null"`
+	PharmacyId             uint            `gorm:"not null"`
+	Pharmacy               *Pharmacy       `gorm:"foreignKey:PharmacyId;references:Id"`
+	OrderStatusId          uint            `gorm:"not null"`
+	OrderStatus            OrderStatus     `gorm:"foreignKey:OrderStatusId;references:Id"`
+	ShippingName           string          `gorm:"not null"`
+	ShippingEta            string          `gorm:"not null"`
+	ShippingPrice          decimal.Decimal `gorm:"not null;type:numeric"`
+	SubTotal               decimal.Decimal `gorm:"not null;type:numeric"`
+	Discount               decimal.Decimal `gorm:"not null;type:numeric;default:0"`
+	ShippingDiscount       decimal.Decimal `gorm:"not null;type:numeric;default:0"`
+	PrescriptionStatus     PrescriptionStatus
+	PrescriptionNote       string
+	PrescriptionReviewedBy *uint
+	PrescriptionReviewedAt *time.Time
+	OrderItems             []*OrderItem `gorm:"foreignKey:ShipmentId"`
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	DeletedAt              gorm.DeletedAt
+}
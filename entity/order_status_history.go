@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistory struct {
+	Id           uint `gorm:"primaryKey;autoIncrement"`
+	OrderId      uint `gorm:"not null"`
+	ShipmentId   *uint
+	FromStatusId *uint
+	FromStatus   *OrderStatus `gorm:"foreignKey:FromStatusId;references:Id"`
+	ToStatusId   uint         `gorm:"not null"`
+	ToStatus     *OrderStatus `gorm:"foreignKey:ToStatusId;references:Id"`
+	ActorId      *uint
+	ActorRoleId  RoleId `gorm:"not null"`
+	Reason       string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type Payment struct {
+	Id             uint        `gorm:"primaryKey;autoIncrement"`
+	ProfileId      uint        `gorm:"not null"`
+	Provider       string      `gorm:"not null"`
+	Reference      string      `gorm:"not null;unique"`
+	ExternalId     string      `gorm:"not null"`
+	PaymentType    PaymentType `gorm:"not null"`
+	OrderId        *uint
+	TelemedicineId *uint
+	Amount         decimal.Decimal `gorm:"not null;type:numeric"`
+	Status         PaymentStatus   `gorm:"not null"`
+	PaymentUrl     string
+	PaidAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
+
+type PaymentType string
+
+const (
+	PaymentTypeOrder        PaymentType = "product_order"
+	PaymentTypeTelemedicine PaymentType = "telemedicine"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentPending  PaymentStatus = "pending"
+	PaymentPaid     PaymentStatus = "paid"
+	PaymentFailed   PaymentStatus = "failed"
+	PaymentRefunded PaymentStatus = "refunded"
+)
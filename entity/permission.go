@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	PermissionUserRead                = "user:read"
+	PermissionRoleManage              = "role:manage"
+	PermissionAdminPharmacyManage     = "admin_pharmacy:manage"
+	PermissionProductCategoryWrite    = "product_category:write"
+	PermissionProductReadAdmin        = "product:read_admin"
+	PermissionProductWrite            = "product:write"
+	PermissionPharmacyRead            = "pharmacy:read"
+	PermissionPharmacyWrite           = "pharmacy:write"
+	PermissionPharmacyStaffManage     = "pharmacy_staff:manage"
+	PermissionPharmacyProductRead     = "pharmacy_product:read"
+	PermissionPharmacyProductCreate   = "pharmacy_product:create"
+	PermissionPharmacyProductUpdate   = "pharmacy_product:update"
+	PermissionStockRecordRead         = "stock_record:read"
+	PermissionStockRecordWrite        = "stock_record:write"
+	PermissionStockMutationRead       = "stock_mutation:read"
+	PermissionStockMutationWrite      = "stock_mutation:write"
+	PermissionOrderRead               = "order:read"
+	PermissionOrderInvoiceRead        = "order:read_invoice"
+	PermissionOrderReviewPrescription = "order:review_prescription"
+	PermissionOrderUpdateStatus       = "order:update_status"
+	PermissionOrderReport             = "order:report"
+	PermissionOrderReportAll          = "order:report_all"
+	PermissionRefundRead              = "refund:read"
+	PermissionRefundRequest           = "refund:request"
+	PermissionRefundProcess           = "refund:process"
+	PermissionRefundProcessAll        = "refund:process_all"
+	PermissionVoucherManage           = "voucher:manage"
+	PermissionDoctorReviewModerate    = "doctor_review:moderate"
+	PermissionProductReviewModerate   = "product_review:moderate"
+)
+
+type Permission struct {
+	Id          uint   `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"unique;not null"`
+	Description string `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+var Permissions = []*Permission{
+	{Name: PermissionUserRead, Description: "List all users"},
+	{Name: PermissionRoleManage, Description: "Manage roles, permissions and role assignments"},
+	{Name: PermissionAdminPharmacyManage, Description: "Manage pharmacy admin accounts"},
+	{Name: PermissionProductCategoryWrite, Description: "Create, update and delete product categories"},
+	{Name: PermissionProductReadAdmin, Description: "View products in the admin catalog"},
+	{Name: PermissionProductWrite, Description: "Create and update products"},
+	{Name: PermissionPharmacyRead, Description: "View pharmacies"},
+	{Name: PermissionPharmacyWrite, Description: "Create and update pharmacies"},
+	{Name: PermissionPharmacyStaffManage, Description: "Invite and remove pharmacy staff"},
+	{Name: PermissionPharmacyProductRead, Description: "View pharmacy products"},
+	{Name: PermissionPharmacyProductCreate, Description: "Add products to a pharmacy"},
+	{Name: PermissionPharmacyProductUpdate, Description: "Update pharmacy product price and availability"},
+	{Name: PermissionStockRecordRead, Description: "View stock records and reports"},
+	{Name: PermissionStockRecordWrite, Description: "Record stock changes"},
+	{Name: PermissionStockMutationRead, Description: "View stock mutations"},
+	{Name: PermissionStockMutationWrite, Description: "Request and process stock mutations"},
+	{Name: PermissionOrderRead, Description: "View orders"},
+	{Name: PermissionOrderInvoiceRead, Description: "Download order invoices"},
+	{Name: PermissionOrderReviewPrescription, Description: "Review order prescriptions"},
+	{Name: PermissionOrderUpdateStatus, Description: "Process orders and update their status"},
+	{Name: PermissionOrderReport, Description: "View sales reports"},
+	{Name: PermissionOrderReportAll, Description: "View sales reports of every pharmacy"},
+	{Name: PermissionRefundRead, Description: "View refunds"},
+	{Name: PermissionRefundRequest, Description: "Request item refunds"},
+	{Name: PermissionRefundProcess, Description: "Approve refunds and mark them paid"},
+	{Name: PermissionRefundProcessAll, Description: "Approve and pay refunds of every pharmacy"},
+	{Name: PermissionVoucherManage, Description: "Manage vouchers"},
+	{Name: PermissionDoctorReviewModerate, Description: "Moderate doctor reviews"},
+	{Name: PermissionProductReviewModerate, Description: "Moderate product reviews"},
+}
+
+var DefaultRolePermissions = map[RoleId][]string{
+	RoleUser: {
+		PermissionOrderRead,
+		PermissionOrderInvoiceRead,
+		PermissionRefundRead,
+	},
+	RoleAdmin: {
+		PermissionProductReadAdmin,
+		PermissionPharmacyRead,
+		PermissionPharmacyWrite,
+		PermissionPharmacyStaffManage,
+		PermissionPharmacyProductRead,
+		PermissionPharmacyProductCreate,
+		PermissionPharmacyProductUpdate,
+		PermissionStockRecordRead,
+		PermissionStockRecordWrite,
+		PermissionStockMutationRead,
+		PermissionStockMutationWrite,
+		PermissionOrderRead,
+		PermissionOrderReviewPrescription,
+		PermissionOrderUpdateStatus,
+		PermissionOrderReport,
+		PermissionRefundRead,
+		PermissionRefundRequest,
+		PermissionRefundProcess,
+	},
+	RoleSuperAdmin: {
+		PermissionUserRead,
+		PermissionRoleManage,
+		PermissionAdminPharmacyManage,
+		PermissionProductCategoryWrite,
+		PermissionProductReadAdmin,
+		PermissionProductWrite,
+		PermissionPharmacyRead,
+		PermissionOrderRead,
+		PermissionOrderInvoiceRead,
+		PermissionOrderReport,
+		PermissionOrderReportAll,
+		PermissionRefundRead,
+		PermissionRefundProcess,
+		PermissionRefundProcessAll,
+		PermissionVoucherManage,
+		PermissionDoctorReviewModerate,
+		PermissionProductReviewModerate,
+	},
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"time"
+)
+
+type PharmacyStaffRole string
+
+const (
+	PharmacyOwner      PharmacyStaffRole = "owner"
+	PharmacyPharmacist PharmacyStaffRole = "pharmacist"
+	PharmacyCashier    PharmacyStaffRole = "cashier"
+)
+
+var PharmacyStaffPermissions = map[PharmacyStaffRole][]string{
+	PharmacyOwner: {
+		PermissionPharmacyRead,
+		PermissionPharmacyWrite,
+		PermissionPharmacyStaffManage,
+		PermissionPharmacyProductRead,
+		PermissionPharmacyProductCreate,
+		PermissionPharmacyProductUpdate,
+		PermissionStockRecordRead,
+		PermissionStockRecordWrite,
+		PermissionStockMutationRead,
+		PermissionStockMutationWrite,
+		PermissionOrderRead,
+		PermissionOrderReviewPrescription,
+		PermissionOrderUpdateStatus,
+		PermissionOrderReport,
+		PermissionRefundRead,
+		PermissionRefundRequest,
+		PermissionRefundProcess,
+	},
+	PharmacyPharmacist: {
+		PermissionPharmacyRead,
+		PermissionPharmacyProductRead,
+		PermissionPharmacyProductCreate,
+		PermissionStockRecordRead,
+		PermissionStockRecordWrite,
+		PermissionStockMutationRead,
+		PermissionStockMutationWrite,
+		PermissionOrderRead,
+		PermissionOrderReviewPrescription,
+		PermissionOrderUpdateStatus,
+		PermissionRefundRead,
+		PermissionRefundRequest,
+	},
+	PharmacyCashier: {
+		PermissionPharmacyRead,
+		PermissionPharmacyProductRead,
+		PermissionStockRecordRead,
+		PermissionOrderRead,
+		PermissionOrderUpdateStatus,
+		PermissionRefundRead,
+	},
+}
+
+func (r PharmacyStaffRole) Can(permission string) bool {
+	for _, p := range PharmacyStaffPermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func PharmacyStaffRolesWith(permission string) []PharmacyStaffRole {
+	var roles []PharmacyStaffRole
+	for _, role := range []PharmacyStaffRole{PharmacyOwner, PharmacyPharmacist, PharmacyCashier} {
+		if role.Can(permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+type PharmacyStaff struct {
+	Id         uint              `gorm:"primaryKey;autoIncrement"`
+	PharmacyId uint              `gorm:"not null;uniqueIndex:idx_pharmacy_staff_member"`
+	Pharmacy   *Pharmacy         `gorm:"foreignKey:PharmacyId;references:Id"`
+	UserId     uint              `gorm:"not null;uniqueIndex:idx_pharmacy_staff_member;index"`
+	User       *User             `gorm:"foreignKey:UserId;references:Id"`
+	Role       PharmacyStaffRole `gorm:"not null"`
+	InvitedBy  *uint
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (s *PharmacyStaff) Can(permission string) bool {
+	return s != nil && s.Role.Can(permission)
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PrescriptionItem struct {
+	Id             uint     `gorm:"primaryKey;autoIncrement"`
+	TelemedicineId uint     `gorm:"not null"`
+	ProductId      uint     `gorm:"not null"`
+	Product        *Product `gorm:"foreignKey:ProductId;references:Id"`
+	Quantity       int      `gorm:"not null"`
+	Dosage         string   `gorm:"not null"`
+	Frequency      string   `gorm:"not null"`
+	Duration       string   `gorm:"not null"`
+	Notes          string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
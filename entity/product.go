@@ -24,6 +24,8 @@ type Product struct {
 	Image             string          `gorm:"not null"`
 	ImageKey          string          `gorm:"not null"`
 	IsHidden          bool            `gorm:"not null"`
+	RatingAverage     decimal.Decimal `gorm:"not null;type:numeric;default:0"`
+	ReviewCount       int             `gorm:"not null;default:0"`
 	Drug              *Drug
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
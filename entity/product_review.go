@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProductReview struct {
+	Id             uint                  `gorm:"primaryKey;autoIncrement"`
+	ProductOrderId uint                  `gorm:"not null;uniqueIndex:idx_product_review_order_product"`
+	ProductId      uint                  `gorm:"not null;uniqueIndex:idx_product_review_order_product;index"`
+	ProfileId      uint                  `gorm:"not null"`
+	Profile        *Profile              `gorm:"foreignKey:ProfileId;references:UserId"`
+	Rating         int                   `gorm:"not null"`
+	Review         string                `gorm:"not null"`
+	IsHidden       bool                  `gorm:"not null;default:false"`
+	Photos         []*ProductReviewPhoto `gorm:"foreignKey:ProductReviewId"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
+
+type ProductReviewPhoto struct {
+	Id              uint   `gorm:"primaryKey;autoIncrement"`
+	ProductReviewId uint   `gorm:"not null;index"`
+	Image           string `gorm:"not null"`
+	ImageKey        string `gorm:"not null"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt
+}
+
+const (
+	ProductReviewFolder = "product-review"
+	ProductReviewPrefix = "product-review-"
+)
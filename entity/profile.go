@@ -7,15 +7,16 @@ import (
 )
 
 type Profile struct {
-	UserId    uint   `gorm:"primaryKey"`
-	User      User   `gorm:"foreignKey:UserId;references:Id"`
-	Name      string `gorm:"not null"`
-	Image     string
-	ImageKey  string
-	Birthdate time.Time `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt
+	UserId     uint   `gorm:"primaryKey"`
+	User       User   `gorm:"foreignKey:UserId;references:Id"`
+	Name       string `gorm:"not null"`
+	Image      string
+	ImageKey   string
+	Birthdate  time.Time `gorm:"not null"`
+	LastSeenAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt
 }
 
 const (
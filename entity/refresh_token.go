@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+)
+
+type RefreshToken struct {
+	Id           uint      `gorm:"primaryKey;autoIncrement"`
+	UserId       uint      `gorm:"not null;index"`
+	User         *User     `gorm:"foreignKey:UserId;references:Id"`
+	TokenHash    string    `gorm:"unique;not null"`
+	TokenVersion int       `gorm:"not null"`
+	ExpiredAt    time.Time `gorm:"not null"`
+	RevokedAt    *time.Time
+	ReplacedById *uint
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
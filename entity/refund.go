@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type Refund struct {
+	Id             uint        `gorm:"primaryKey;autoIncrement"`
+	ProfileId      uint        `gorm:"not null"`
+	PaymentType    PaymentType `gorm:"not null"`
+	OrderId        *uint
+	ShipmentId     *uint
+	Shipment       *OrderShipment `gorm:"foreignKey:ShipmentId;references:Id"`
+	TelemedicineId *uint
+	PaymentId      *uint
+	Amount         decimal.Decimal `gorm:"not null;type:numeric"`
+	Reason         string          `gorm:"not null"`
+	Status         RefundStatus    `gorm:"not null"`
+	ApprovedBy     *uint
+	ApprovedAt     *time.Time
+	PaidAt         *time.Time
+	RefundItems    []*RefundItem `gorm:"foreignKey:RefundId"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
+
+type RefundItem struct {
+	Id          uint            `gorm:"primaryKey;autoIncrement"`
+	RefundId    uint            `gorm:"not null"`
+	OrderItemId uint            `gorm:"not null"`
+	OrderItem   *OrderItem      `gorm:"foreignKey:OrderItemId;references:Id"`
+	Quantity    int             `gorm:"not null"`
+	Amount      decimal.Decimal `gorm:"not null;type:numeric"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt
+}
+
+type RefundStatus string
+
+const (
+	RefundRequested RefundStatus = "requested"
+	RefundApproved  RefundStatus = "approved"
+	RefundPaid      RefundStatus = "paid"
+)
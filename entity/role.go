@@ -9,16 +9,31 @@ import (
 type RoleId uint
 
 const (
-	RoleUser RoleId = iota + 1
+	RoleSystem RoleId = iota
+	RoleUser
 	RoleDoctor
 	RoleAdmin
 	RoleSuperAdmin
 )
 
 type Role struct {
-	Id        RoleId `gorm:"primaryKey;autoIncrement"`
-	Name      string `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt
+	Id          RoleId        `gorm:"primaryKey;autoIncrement"`
+	Name        string        `gorm:"not null"`
+	BaseRoleId  RoleId        `gorm:"not null;default:0"`
+	IsSystem    bool          `gorm:"not null;default:false"`
+	Permissions []*Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt
+}
+
+func (r *Role) GetBaseRoleId() RoleId {
+	if r.BaseRoleId == RoleSystem {
+		return r.Id
+	}
+	return r.BaseRoleId
+}
+
+func (r RoleId) IsStaff() bool {
+	return r == RoleAdmin || r == RoleSuperAdmin
 }
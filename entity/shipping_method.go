@@ -21,4 +21,11 @@ type CalculatedShippingMethod struct {
 	Name              string
 	EstimatedDuration string
 	Cost              string
+	Discount          string
+	FinalCost         string
+}
+
+type PharmacyShippingMethod struct {
+	Pharmacy *Pharmacy
+	Methods  []*CalculatedShippingMethod
 }
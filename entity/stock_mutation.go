@@ -16,6 +16,7 @@ type StockMutation struct {
 	Status                StockMutationStatus `gorm:"not null"`
 	OrderId               uint
 	MutatedAt             time.Time `gorm:"not null"`
+	IsRequest             bool      `gorm:"-"`
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 	DeletedAt             gorm.DeletedAt
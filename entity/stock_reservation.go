@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type StockReservation struct {
+	Id                uint                   `gorm:"primaryKey;autoIncrement"`
+	PharmacyProductId uint                   `gorm:"not null"`
+	PharmacyProduct   *PharmacyProduct       `gorm:"foreignKey:PharmacyProductId;references:Id"`
+	OrderId           uint                   `gorm:"not null"`
+	ShipmentId        uint                   `gorm:"not null"`
+	OrderItemId       uint                   `gorm:"not null"`
+	Quantity          int                    `gorm:"not null"`
+	Status            StockReservationStatus `gorm:"not null"`
+	ExpiredAt         *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt
+}
+
+type StockReservationStatus string
+
+const (
+	ReservationHeld     StockReservationStatus = "held"
+	ReservationReleased StockReservationStatus = "released"
+	ReservationDeducted StockReservationStatus = "deducted"
+)
@@ -21,9 +21,13 @@ type Telemedicine struct {
 	PrescriptionPdfKey string             `gorm:"not null"`
 	ProfileId          uint               `gorm:"not null"`
 	Profile            *Profile           `gorm:"foreignKey:ProfileId;references:UserId"`
-	DoctorId           uint               `gorm:"not null"`
+	DoctorId           uint               `gorm:"not null;uniqueIndex:idx_telemedicine_doctor_slot,where:scheduled_at IS NOT NULL AND status <> 'canceled' AND deleted_at IS NULL"`
 	Doctor             *DoctorProfile     `gorm:"foreignKey:DoctorId;references:ProfileId"`
+	ScheduledAt        *time.Time         `gorm:"uniqueIndex:idx_telemedicine_doctor_slot"`
+	ScheduledEndAt     *time.Time
 	Chats              []*Chat
+	PrescriptionItems  []*PrescriptionItem `gorm:"foreignKey:TelemedicineId"`
+	UnreadCount        int                 `gorm:"-"`
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 	DeletedAt          gorm.DeletedAt
@@ -42,6 +46,7 @@ type TelemedicineStatus string
 
 const (
 	Waiting TelemedicineStatus = "waiting for payment"
+	Booked  TelemedicineStatus = "booked"
 	Ongoing TelemedicineStatus = "ongoing"
 	End     TelemedicineStatus = "ended"
 	Cancel  TelemedicineStatus = "canceled"
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TelemedicineQueueStatus string
+
+const (
+	QueueWaiting  TelemedicineQueueStatus = "waiting"
+	QueuePromoted TelemedicineQueueStatus = "promoted"
+	QueueLeft     TelemedicineQueueStatus = "left"
+)
+
+type TelemedicineQueue struct {
+	Id             uint                    `gorm:"primaryKey;autoIncrement"`
+	DoctorId       uint                    `gorm:"not null;index"`
+	Doctor         *DoctorProfile          `gorm:"foreignKey:DoctorId;references:ProfileId"`
+	ProfileId      uint                    `gorm:"not null;index"`
+	Status         TelemedicineQueueStatus `gorm:"not null"`
+	TelemedicineId *uint
+	JoinedAt       time.Time `gorm:"not null"`
+	PromotedAt     *time.Time
+	Position       int           `gorm:"-"`
+	EstimatedWait  time.Duration `gorm:"-"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
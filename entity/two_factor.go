@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+)
+
+type UserTwoFactor struct {
+	Id              uint   `gorm:"primaryKey;autoIncrement"`
+	UserId          uint   `gorm:"unique;not null"`
+	User            *User  `gorm:"foreignKey:UserId;references:Id"`
+	Secret          string `gorm:"not null"`
+	IsEnabled       bool   `gorm:"not null"`
+	EnabledAt       *time.Time
+	LastUsedStep    int64  `gorm:"not null;default:0"`
+	ProvisioningUri string `gorm:"-"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type TwoFactorRecoveryCode struct {
+	Id        uint   `gorm:"primaryKey;autoIncrement"`
+	UserId    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type TwoFactorChallenge struct {
+	Token              string
+	EnrollmentRequired bool
+}
+
+func (r RoleId) RequiresTwoFactor() bool {
+	return r == RoleAdmin || r == RoleSuperAdmin
+}
@@ -10,10 +10,13 @@ type User struct {
 	Id           uint   `gorm:"primaryKey;autoIncrement"`
 	Email        string `gorm:"unique;not null"`
 	Password     string
-	IsVerified   bool   `gorm:"not null"`
-	Token        string `gorm:"not null"`
-	RoleId       RoleId `gorm:"not null"`
-	Role         *Role  `gorm:"foreignKey:RoleId;references:Id"`
+	IsVerified   bool                `gorm:"not null"`
+	Token        string              `gorm:"not null"`
+	RefreshToken string              `gorm:"-"`
+	TokenVersion int                 `gorm:"not null;default:0"`
+	Challenge    *TwoFactorChallenge `gorm:"-"`
+	RoleId       RoleId              `gorm:"not null"`
+	Role         *Role               `gorm:"foreignKey:RoleId;references:Id"`
 	AdminContact *AdminContact
 	Profile      *Profile
 	CreatedAt    time.Time
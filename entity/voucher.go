@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type VoucherType string
+
+const (
+	VoucherPercentage   VoucherType = "percentage"
+	VoucherFixed        VoucherType = "fixed"
+	VoucherFreeShipping VoucherType = "free_shipping"
+)
+
+type Voucher struct {
+	Id                uint   `gorm:"primaryKey;autoIncrement"`
+	Code              string `gorm:"not null"`
+	Name              string `gorm:"not null"`
+	Description       string
+	Type              VoucherType         `gorm:"not null"`
+	Value             decimal.Decimal     `gorm:"not null;type:numeric"`
+	MaxDiscount       decimal.NullDecimal `gorm:"type:numeric"`
+	MinSpend          decimal.Decimal     `gorm:"not null;type:numeric"`
+	ProductCategoryId *uint
+	ProductCategory   *ProductCategory `gorm:"foreignKey:ProductCategoryId;references:Id"`
+	PerUserLimit      int              `gorm:"not null;default:0"`
+	Quota             int              `gorm:"not null;default:0"`
+	UsedCount         int              `gorm:"not null;default:0"`
+	StartAt           time.Time        `gorm:"not null"`
+	EndAt             time.Time        `gorm:"not null"`
+	IsActive          bool             `gorm:"not null"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt
+}
+
+type VoucherUsage struct {
+	Id               uint            `gorm:"primaryKey;autoIncrement"`
+	VoucherId        uint            `gorm:"not null"`
+	ProfileId        uint            `gorm:"not null"`
+	OrderId          uint            `gorm:"not null"`
+	Discount         decimal.Decimal `gorm:"not null;type:numeric"`
+	ShippingDiscount decimal.Decimal `gorm:"not null;type:numeric"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt
+}
@@ -85,7 +85,35 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{Data: dto.LoginResponse{Token: tokenUser.Token, RoleId: uint(tokenUser.RoleId)}})
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewLoginResponse(tokenUser)})
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var request dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tokenUser, err := h.usecase.Refresh(c.Request.Context(), request.RefreshToken)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewLoginResponse(tokenUser)})
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var request dto.LogoutRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.Logout(c.Request.Context(), request.RefreshToken, request.AllDevices)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "logout success"})
 }
 
 func (h *AuthHandler) RequestForgotPassword(c *gin.Context) {
@@ -119,3 +147,96 @@ func (h *AuthHandler) ApplyPassword(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, dto.Response{Message: "password changed"})
 }
+
+func (h *AuthHandler) VerifyTwoFactorLogin(c *gin.Context) {
+	var request dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tokenUser, err := h.usecase.VerifyTwoFactorLogin(c.Request.Context(), request.ChallengeToken, request.Code, request.RecoveryCode)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewLoginResponse(tokenUser)})
+}
+
+func (h *AuthHandler) EnrollTwoFactorChallenge(c *gin.Context) {
+	var request dto.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	twoFactor, err := h.usecase.EnrollTwoFactorChallenge(c.Request.Context(), request.ChallengeToken)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTwoFactorEnrollmentResponse(twoFactor)})
+}
+
+func (h *AuthHandler) ActivateTwoFactorChallenge(c *gin.Context) {
+	var request dto.TwoFactorChallengeActivateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tokenUser, recoveryCodes, err := h.usecase.ActivateTwoFactorChallenge(c.Request.Context(), request.ChallengeToken, request.Code)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.TwoFactorActivationResponse{LoginResponse: dto.NewLoginResponse(tokenUser), RecoveryCodes: recoveryCodes}})
+}
+
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	twoFactor, err := h.usecase.EnrollTwoFactor(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTwoFactorEnrollmentResponse(twoFactor)})
+}
+
+func (h *AuthHandler) ActivateTwoFactor(c *gin.Context) {
+	var request dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	recoveryCodes, err := h.usecase.ActivateTwoFactor(c.Request.Context(), request.Code)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var request dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	recoveryCodes, err := h.usecase.RegenerateRecoveryCodes(c.Request.Context(), request.Code)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}})
+}
+
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var request dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.DisableTwoFactor(c.Request.Context(), request.Code)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "two factor authentication disabled"})
+}
@@ -125,3 +125,17 @@ func (h *CartHandler) CheckAllItem(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, dto.Response{Message: "update success"})
 }
+
+func (h *CartHandler) AddPrescriptionItems(c *gin.Context) {
+	var telemedicineUri dto.TelemedicineUri
+	if err := c.ShouldBindUri(&telemedicineUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.AddPrescriptionItems(c.Request.Context(), telemedicineUri.Id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "add success"})
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/night1010/everhealth/appjwt"
 	"github.com/night1010/everhealth/chat"
 	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/logger"
 	"github.com/night1010/everhealth/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -49,9 +51,9 @@ func (h *ChatHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	user, err := h.jwt.ValidateToken(token.Token)
+	user, err := h.jwt.ValidateToken(c.Request.Context(), token.Token)
 	if err != nil {
-		_ = c.Error(err)
+		_ = c.Error(apperror.NewInvalidTokenError())
 		return
 	}
 
@@ -59,7 +61,14 @@ func (h *ChatHandler) Handle(c *gin.Context) {
 	ctx = context.WithValue(ctx, "role_id", user.RoleId)
 	c.Request = c.Request.WithContext(ctx)
 
-	isValid, err := h.chatUsecase.ValidateTelemedicine(c.Request.Context(), uint(uri.Id))
+	var isValid bool
+	chatRoom := uint(uri.Id)
+	if token.Queue {
+		isValid, err = h.chatUsecase.ValidateQueue(c.Request.Context(), uint(uri.Id))
+		chatRoom = 0
+	} else {
+		isValid, err = h.chatUsecase.ValidateTelemedicine(c.Request.Context(), uint(uri.Id))
+	}
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -78,9 +87,52 @@ func (h *ChatHandler) Handle(c *gin.Context) {
 
 	userId := c.Request.Context().Value("user_id").(uint)
 
-	client := chat.NewClient(conn, h.manager, uint(uri.Id), userId)
+	client := chat.NewClient(conn, h.manager, chatRoom, userId)
 	h.manager.AddClient(client)
 
 	go client.ReadMessages()
 	go client.WriteMessages()
 }
+
+func (h *ChatHandler) GetMessages(c *gin.Context) {
+	var uri dto.RequestUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var request dto.ChatHistoryQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	chats, nextCursor, err := h.chatUsecase.FindChatHistory(c.Request.Context(), uint(uri.Id), request.Before, request.GetLimit())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Data: dto.ChatHistoryRes{Messages: dto.NewChatsRes(chats), NextCursor: nextCursor}})
+}
+
+func (h *ChatHandler) PostAttachment(c *gin.Context) {
+	var uri dto.RequestUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	chat := &entity.Chat{TelemedicineId: uint(uri.Id), UserId: c.Request.Context().Value("user_id").(uint)}
+	createdChat, err := h.chatUsecase.AddAttachment(c.Request.Context(), chat)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.manager.BroadcastMessage(createdChat); err != nil {
+		logger.Log.Errorf("failed to broadcast attachment: %v", err)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewChatRes(createdChat), Message: "attachment sent"})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/usecase"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type DoctorReviewHandler struct {
+	reviewUsecase usecase.DoctorReviewUsecase
+}
+
+func NewDoctorReviewHandler(u usecase.DoctorReviewUsecase) *DoctorReviewHandler {
+	return &DoctorReviewHandler{reviewUsecase: u}
+}
+
+func (h *DoctorReviewHandler) PostReview(c *gin.Context) {
+	var requestUri dto.TelemedicineUri
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.DoctorReviewReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	review, err := h.reviewUsecase.CreateReview(c.Request.Context(), request.ToModel(requestUri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewDoctorReviewRes(review), Message: "created success"})
+}
+
+func (h *DoctorReviewHandler) ListDoctorReviews(c *gin.Context) {
+	var doctorUri dto.DoctorUri
+	if err := c.ShouldBindUri(&doctorUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.DoctorReviewQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	request.IsHidden = nil
+	query := request.ToQuery().
+		Condition("doctor_id", valueobject.Equal, doctorUri.Id).
+		Condition("is_hidden", valueobject.Equal, false)
+	h.listReviews(c, query)
+}
+
+func (h *DoctorReviewHandler) ListAllReviews(c *gin.Context) {
+	var request dto.DoctorReviewQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.listReviews(c, request.ToQuery())
+}
+
+func (h *DoctorReviewHandler) PatchReview(c *gin.Context) {
+	var requestUri dto.RequestUri
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.ModerateDoctorReviewReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	review, err := h.reviewUsecase.ModerateReview(c.Request.Context(), &entity.DoctorReview{Id: uint(requestUri.Id), IsHidden: *request.IsHidden})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewDoctorReviewRes(review), Message: "updated success"})
+}
+
+func (h *DoctorReviewHandler) listReviews(c *gin.Context, query *valueobject.Query) {
+	pagedResult, err := h.reviewUsecase.ListReviews(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	reviews := []*dto.DoctorReviewRes{}
+	for _, review := range pagedResult.Data.([]*entity.DoctorReview) {
+		reviews = append(reviews, dto.NewDoctorReviewRes(review))
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Data:        reviews,
+		CurrentPage: &pagedResult.CurrentPage,
+		CurrentItem: &pagedResult.CurrentItems,
+		TotalPage:   &pagedResult.TotalPage,
+		TotalItem:   &pagedResult.TotalItem,
+	})
+}
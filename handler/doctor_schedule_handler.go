@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/usecase"
+)
+
+type DoctorScheduleHandler struct {
+	scheduleUsecase usecase.DoctorScheduleUsecase
+}
+
+func NewDoctorScheduleHandler(u usecase.DoctorScheduleUsecase) *DoctorScheduleHandler {
+	return &DoctorScheduleHandler{scheduleUsecase: u}
+}
+
+func (h *DoctorScheduleHandler) GetSchedule(c *gin.Context) {
+	availabilities, err := h.scheduleUsecase.FindAvailabilities(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	exceptions, err := h.scheduleUsecase.FindExceptions(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewDoctorScheduleRes(availabilities, exceptions)})
+}
+
+func (h *DoctorScheduleHandler) PutAvailabilities(c *gin.Context) {
+	var request dto.UpdateDoctorAvailabilityReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	availabilities, err := request.ToModel()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	availabilities, err = h.scheduleUsecase.UpdateAvailabilities(c.Request.Context(), availabilities)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	res := []*dto.DoctorAvailabilityRes{}
+	for _, availability := range availabilities {
+		res = append(res, dto.NewDoctorAvailabilityRes(availability))
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: res, Message: "updated success"})
+}
+
+func (h *DoctorScheduleHandler) PostException(c *gin.Context) {
+	var request dto.DoctorAvailabilityExceptionReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	exception, err := request.ToModel()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	exception, err = h.scheduleUsecase.CreateException(c.Request.Context(), exception)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewDoctorAvailabilityExceptionRes(exception), Message: "created success"})
+}
+
+func (h *DoctorScheduleHandler) DeleteException(c *gin.Context) {
+	var request dto.RequestUri
+	if err := c.ShouldBindUri(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.scheduleUsecase.DeleteException(c.Request.Context(), uint(request.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "deleted success"})
+}
+
+func (h *DoctorScheduleHandler) GetDoctorSlots(c *gin.Context) {
+	var doctorUri dto.DoctorUri
+	if err := c.ShouldBindUri(&doctorUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.DoctorSlotQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	date, err := request.ToDate()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	slots, err := h.scheduleUsecase.FindAvailableSlots(c.Request.Context(), doctorUri.Id, date)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewDoctorSlotsRes(slots)})
+}
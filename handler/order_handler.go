@@ -83,6 +83,9 @@ func (h *OrderHandler) OrderHistory(c *gin.Context) {
 			}
 			orders.OrderItem = append(orders.OrderItem, orderItem)
 		}
+		for j := range data[i].Shipments {
+			orders.Shipments = append(orders.Shipments, dto.NewOrderShipmentResponse(&data[i].Shipments[j]))
+		}
 		listOrders = append(listOrders, orders)
 	}
 	c.JSON(200, dto.Response{
@@ -108,6 +111,34 @@ func (h *OrderHandler) UploadPaymentProof(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.Response{Message: "upload success"})
 }
 
+func (h *OrderHandler) UploadPrescription(c *gin.Context) {
+	prescription, err := h.usecase.UploadPrescription(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewOrderPrescriptionResponse(prescription)})
+}
+
+func (h *OrderHandler) ReviewPrescription(c *gin.Context) {
+	var requestUri dto.OrderUri
+	var request dto.ReviewPrescriptionRequest
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.ReviewPrescription(c.Request.Context(), request.ToOrder(requestUri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "update success"})
+}
+
 func (h *OrderHandler) OrderDetail(c *gin.Context) {
 	var requestUri dto.OrderUri
 	if err := c.ShouldBindUri(&requestUri); err != nil {
@@ -119,44 +150,83 @@ func (h *OrderHandler) OrderDetail(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	roleId := c.Request.Context().Value("role_id").(entity.RoleId)
 	var orderItems []*dto.OrderItemResponse
+	shipmentM := make(map[uint]*dto.OrderShipmentResponse)
+	var shipments []*dto.OrderShipmentResponse
+	for i := range order.Shipments {
+		shipment := dto.NewOrderShipmentResponse(&order.Shipments[i])
+		if roleId == entity.RoleSuperAdmin {
+			shipment.PharmacyContact = order.Shipments[i].Pharmacy.Admin.AdminContact.Phone
+			shipment.PharmacyEmail = order.Shipments[i].Pharmacy.Admin.AdminContact.User.Email
+		}
+		shipmentM[shipment.Id] = shipment
+		shipments = append(shipments, shipment)
+	}
 	for _, item := range itemOrder {
-		orderItems = append(orderItems, &dto.OrderItemResponse{
+		orderItem := &dto.OrderItemResponse{
 			Id:       item.Id,
 			Name:     item.PharmacyProduct.Product.Name,
 			Quantity: item.Quantity,
 			SubTotal: item.SubTotal.String(),
 			Image:    item.PharmacyProduct.Product.Image,
-		})
+		}
+		orderItems = append(orderItems, orderItem)
+		if shipment, ok := shipmentM[item.ShipmentId]; ok {
+			shipment.OrderItem = append(shipment.OrderItem, orderItem)
+		}
 	}
-	roleId := c.Request.Context().Value("role_id").(entity.RoleId)
 	orderDetailRes := dto.OrderDetailResponse{
-		Id:            fmt.Sprintf("%s%04d", "7", order.Id),
-		OrderItem:     orderItems,
-		OrderedAt:     order.OrderedAt.Format(time.RFC3339),
-		ExpiredAt:     order.ExpiredAt.Format(time.RFC3339),
-		OrderStatus:   order.OrderStatus.Name,
-		PaymentProof:  order.PaymentProof,
-		ProductPrice:  (order.TotalPayment.Sub(order.ShippingPrice)).String(),
-		ShippingName:  order.ShippingName,
-		ShippingPrice: order.ShippingPrice.String(),
-		ShippingEta:   order.ShippingEta,
-		TotalPrice:    order.TotalPayment.String(),
-		Name:          address.Name,
-		StreetName:    address.StreetName,
-		PostalCode:    address.PostalCode,
-		Phone:         address.Phone,
-		Detail:        address.Detail,
-		Province:      address.Province.Name,
-		City:          address.City.Name,
-	}
-	if roleId == entity.RoleSuperAdmin {
-		orderDetailRes.PharmacyContact = order.OrderItems[0].PharmacyProduct.Pharmacy.Admin.AdminContact.Phone
-		orderDetailRes.PharmacyEmail = order.OrderItems[0].PharmacyProduct.Pharmacy.Admin.AdminContact.User.Email
+		Id:               fmt.Sprintf("%s%04d", "7", order.Id),
+		OrderItem:        orderItems,
+		OrderedAt:        order.OrderedAt.Format(time.RFC3339),
+		ExpiredAt:        order.ExpiredAt.Format(time.RFC3339),
+		OrderStatus:      order.OrderStatus.Name,
+		PaymentProof:     order.PaymentProof,
+		ProductPrice:     (order.TotalPayment.Sub(order.ShippingPrice).Add(order.Discount).Add(order.ShippingDiscount)).String(),
+		ShippingName:     order.ShippingName,
+		ShippingPrice:    order.ShippingPrice.String(),
+		ShippingEta:      order.ShippingEta,
+		VoucherCode:      order.VoucherCode,
+		Discount:         order.Discount.String(),
+		ShippingDiscount: order.ShippingDiscount.String(),
+		TotalPrice:       order.TotalPayment.String(),
+		Name:             address.Name,
+		StreetName:       address.StreetName,
+		PostalCode:       address.PostalCode,
+		Phone:            address.Phone,
+		Detail:           address.Detail,
+		Province:         address.Province.Name,
+		City:             address.City.Name,
+		Shipments:        shipments,
+	}
+	if order.Prescription != nil {
+		orderDetailRes.Prescription = order.Prescription.File
+	}
+	for _, history := range order.Histories {
+		orderDetailRes.Timeline = append(orderDetailRes.Timeline, dto.NewOrderStatusHistoryResponse(history))
+	}
+	if roleId == entity.RoleSuperAdmin && len(shipments) != 0 {
+		orderDetailRes.PharmacyContact = shipments[0].PharmacyContact
+		orderDetailRes.PharmacyEmail = shipments[0].PharmacyEmail
 	}
 	c.JSON(http.StatusOK, dto.Response{Data: orderDetailRes})
 }
 
+func (h *OrderHandler) GetInvoice(c *gin.Context) {
+	var requestUri dto.OrderUri
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	invoice, err := h.usecase.GetInvoice(c.Request.Context(), requestUri.Id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.InvoiceResponse{Number: invoice.Number, Url: invoice.Url}})
+}
+
 func (h *OrderHandler) UserUpdateOrderStatus(c *gin.Context) {
 	var requestUri dto.OrderUri
 	var request dto.UserUpdateOrderStatusRequest
@@ -203,7 +273,7 @@ func (h *OrderHandler) GetAvailableProduct(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	total, pharmacyProducts, orderItems, _, err := h.usecase.GetAvailableProduct(c.Request.Context(), orderAddressUri.Id)
+	total, pharmacyProducts, shipments, _, err := h.usecase.GetAvailableProduct(c.Request.Context(), orderAddressUri.Id)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -219,10 +289,16 @@ func (h *OrderHandler) GetAvailableProduct(c *gin.Context) {
 			Price:      item.Price.String(),
 			Image:      item.Product.Image,
 		}
-		for _, orderItem := range orderItems {
-			if orderItem.PharmacyProductId == item.Id {
-				itemDto.Quantity = orderItem.Quantity
-				itemDto.SubTotal = orderItem.SubTotal.String()
+		for _, shipment := range shipments {
+			for _, orderItem := range shipment.OrderItems {
+				if orderItem.PharmacyProductId == item.Id {
+					itemDto.PharmacyId = shipment.PharmacyId
+					if shipment.Pharmacy != nil {
+						itemDto.PharmacyName = shipment.Pharmacy.Name
+					}
+					itemDto.Quantity = orderItem.Quantity
+					itemDto.SubTotal = orderItem.SubTotal.String()
+				}
 			}
 		}
 		orderDto.OrderItems = append(orderDto.OrderItems, itemDto)
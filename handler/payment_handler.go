@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/usecase"
+)
+
+type PaymentHandler struct {
+	usecase usecase.PaymentUsecase
+}
+
+func NewPaymentHandler(u usecase.PaymentUsecase) *PaymentHandler {
+	return &PaymentHandler{usecase: u}
+}
+
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
+	var request dto.CreatePaymentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	payment, err := h.usecase.CreatePayment(c.Request.Context(), request.ToModel())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewPaymentResponse(payment)})
+}
+
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	var request dto.WebhookUri
+	if err := c.ShouldBindUri(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = h.usecase.HandleWebhook(c.Request.Context(), request.Provider, c.Request.Header, body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "webhook received"})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/usecase"
+)
+
+type PharmacyStaffHandler struct {
+	usecase usecase.PharmacyStaffUsecase
+}
+
+func NewPharmacyStaffHandler(u usecase.PharmacyStaffUsecase) *PharmacyStaffHandler {
+	return &PharmacyStaffHandler{usecase: u}
+}
+
+func (h *PharmacyStaffHandler) GetAllStaff(c *gin.Context) {
+	var uri dto.RequestPharmacyUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	staffs, err := h.usecase.ListStaff(c.Request.Context(), uri.Id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	data := []*dto.PharmacyStaffRes{}
+	for _, staff := range staffs {
+		data = append(data, dto.NewPharmacyStaffRes(staff))
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: data})
+}
+
+func (h *PharmacyStaffHandler) PostStaff(c *gin.Context) {
+	var uri dto.RequestPharmacyUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.InviteStaffReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	staff, err := h.usecase.InviteStaff(c.Request.Context(), request.ToModel(uri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{Data: dto.NewPharmacyStaffRes(staff)})
+}
+
+func (h *PharmacyStaffHandler) DeleteStaff(c *gin.Context) {
+	var uri dto.PharmacyStaffUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.RemoveStaff(c.Request.Context(), uri.PharmacyId, uri.UserId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "delete success"})
+}
@@ -44,6 +44,8 @@ func (h *ProductHandler) ListProduct(c *gin.Context) {
 			FloorPrice:  floor[product.Id],
 			SellingUnit: product.SellingUnit,
 			Image:       product.Image,
+			Rating:      product.RatingAverage.StringFixed(2),
+			ReviewCount: product.ReviewCount,
 		})
 	}
 	c.JSON(200, dto.Response{
@@ -115,6 +117,8 @@ func (h *ProductHandler) ListNearbyProduct(c *gin.Context) {
 			FloorPrice:  floor[product.Id],
 			SellingUnit: product.SellingUnit,
 			Image:       product.Image,
+			Rating:      product.RatingAverage.StringFixed(2),
+			ReviewCount: product.ReviewCount,
 		})
 	}
 	c.JSON(200, dto.Response{
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/usecase"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type ProductReviewHandler struct {
+	reviewUsecase usecase.ProductReviewUsecase
+}
+
+func NewProductReviewHandler(u usecase.ProductReviewUsecase) *ProductReviewHandler {
+	return &ProductReviewHandler{reviewUsecase: u}
+}
+
+func (h *ProductReviewHandler) PostReview(c *gin.Context) {
+	var requestUri dto.RequestUri
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.ProductReviewReq
+	if err := c.ShouldBind(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	review, err := h.reviewUsecase.CreateReview(c.Request.Context(), request.ToModel(uint(requestUri.Id)))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewProductReviewRes(review), Message: "created success"})
+}
+
+func (h *ProductReviewHandler) ListProductReviews(c *gin.Context) {
+	var requestUri dto.RequestUri
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.ProductReviewQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	request.IsHidden = nil
+	query := request.ToQuery().
+		Condition("product_id", valueobject.Equal, requestUri.Id).
+		Condition("is_hidden", valueobject.Equal, false)
+	h.listReviews(c, query)
+}
+
+func (h *ProductReviewHandler) ListAllReviews(c *gin.Context) {
+	var request dto.ProductReviewQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.listReviews(c, request.ToQuery())
+}
+
+func (h *ProductReviewHandler) PatchReview(c *gin.Context) {
+	var requestUri dto.RequestUri
+	if err := c.ShouldBindUri(&requestUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.ModerateProductReviewReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	review, err := h.reviewUsecase.ModerateReview(c.Request.Context(), &entity.ProductReview{Id: uint(requestUri.Id), IsHidden: *request.IsHidden})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewProductReviewRes(review), Message: "updated success"})
+}
+
+func (h *ProductReviewHandler) listReviews(c *gin.Context, query *valueobject.Query) {
+	pagedResult, err := h.reviewUsecase.ListReviews(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	reviews := []*dto.ProductReviewRes{}
+	for _, review := range pagedResult.Data.([]*entity.ProductReview) {
+		reviews = append(reviews, dto.NewProductReviewRes(review))
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Data:        reviews,
+		CurrentPage: &pagedResult.CurrentPage,
+		CurrentItem: &pagedResult.CurrentItems,
+		TotalPage:   &pagedResult.TotalPage,
+		TotalItem:   &pagedResult.TotalItem,
+	})
+}
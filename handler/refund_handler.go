@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/usecase"
+)
+
+type RefundHandler struct {
+	usecase usecase.RefundUsecase
+}
+
+func NewRefundHandler(u usecase.RefundUsecase) *RefundHandler {
+	return &RefundHandler{usecase: u}
+}
+
+func (h *RefundHandler) ListRefunds(c *gin.Context) {
+	var request dto.ListRefundQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	query, err := request.ToQuery()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	pagedResult, err := h.usecase.ListRefunds(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	data := []*dto.RefundResponse{}
+	for _, refund := range pagedResult.Data.([]*entity.Refund) {
+		data = append(data, dto.NewRefundResponse(refund))
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Data:        data,
+		CurrentPage: &pagedResult.CurrentPage,
+		CurrentItem: &pagedResult.CurrentItems,
+		TotalPage:   &pagedResult.TotalPage,
+		TotalItem:   &pagedResult.TotalItem,
+	})
+}
+
+func (h *RefundHandler) RequestItemRefund(c *gin.Context) {
+	var uri dto.OrderUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.CreateRefundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	refund, err := h.usecase.RequestItemRefund(c.Request.Context(), request.ToModel(uri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewRefundResponse(refund), Message: "refund requested"})
+}
+
+func (h *RefundHandler) ApproveRefund(c *gin.Context) {
+	var uri dto.RequestUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.ApproveRefund(c.Request.Context(), uint(uri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "refund approved"})
+}
+
+func (h *RefundHandler) MarkRefundPaid(c *gin.Context) {
+	var uri dto.RequestUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.MarkRefundPaid(c.Request.Context(), uint(uri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "refund paid"})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/usecase"
+)
+
+type RoleHandler struct {
+	usecase usecase.RoleUsecase
+}
+
+func NewRoleHandler(u usecase.RoleUsecase) *RoleHandler {
+	return &RoleHandler{usecase: u}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.usecase.ListRoles(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	data := []*dto.RoleDetailRes{}
+	for _, role := range roles {
+		data = append(data, dto.NewRoleDetailRes(role))
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: data})
+}
+
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.usecase.ListPermissions(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	data := []*dto.PermissionRes{}
+	for _, permission := range permissions {
+		data = append(data, dto.NewPermissionRes(permission))
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: data})
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var request dto.RoleReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	role, err := h.usecase.CreateRole(c.Request.Context(), request.ToModel())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{Data: dto.NewRoleDetailRes(role)})
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var uri dto.RoleUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.UpdateRoleReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	role, err := h.usecase.UpdateRole(c.Request.Context(), request.ToModel(uri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewRoleDetailRes(role)})
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	var uri dto.RoleUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.DeleteRole(c.Request.Context(), entity.RoleId(uri.Id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "delete success"})
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	var uri dto.RoleUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.AssignRoleReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.AssignRole(c.Request.Context(), uri.Id, entity.RoleId(request.RoleId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "assign role success"})
+}
@@ -18,23 +18,42 @@ func NewShippingMethodHandler(shippingMethodUsecase usecase.ShippingMethodUsecas
 
 func (h *ShippingMethodHandler) GetShippingMethod(c *gin.Context) {
 	var addressUri dto.AddressUri
+	var queryParam dto.ShippingMethodQueryParam
 	if err := c.ShouldBindUri(&addressUri); err != nil {
 		_ = c.Error(err)
 		return
 	}
+	if err := c.ShouldBindQuery(&queryParam); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var voucherCode string
+	if queryParam.VoucherCode != nil {
+		voucherCode = *queryParam.VoucherCode
+	}
 
-	shippingMethods, err := h.usecase.GetShippingMethod(c.Request.Context(), addressUri.Id)
+	shippingMethods, err := h.usecase.GetShippingMethod(c.Request.Context(), addressUri.Id, voucherCode)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
-	var response []dto.ShippingMethodResponse
-	for _, method := range shippingMethods {
-		response = append(response, dto.ShippingMethodResponse{
-			Name:     method.Name,
-			Duration: method.EstimatedDuration,
-			Cost:     method.Cost,
-		})
+	var response []dto.PharmacyShippingMethodResponse
+	for _, shipping := range shippingMethods {
+		pharmacyShipping := dto.PharmacyShippingMethodResponse{
+			PharmacyId:      shipping.Pharmacy.Id,
+			PharmacyName:    shipping.Pharmacy.Name,
+			ShippingMethods: make([]dto.ShippingMethodResponse, 0),
+		}
+		for _, method := range shipping.Methods {
+			pharmacyShipping.ShippingMethods = append(pharmacyShipping.ShippingMethods, dto.ShippingMethodResponse{
+				Name:     method.Name,
+				Duration: method.EstimatedDuration,
+				Cost:      method.Cost,
+				Discount:  method.Discount,
+				FinalCost: method.FinalCost,
+			})
+		}
+		response = append(response, pharmacyShipping)
 	}
 	c.JSON(http.StatusOK, dto.Response{Data: response})
 }
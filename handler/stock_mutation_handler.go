@@ -37,7 +37,7 @@ func (h *StockMutationHandler) GetAllStockMutation(c *gin.Context) {
 	pharmacies := pageResult.Data.([]*entity.StockMutation)
 	pharmaciesRes := []*dto.StockMutationRes{}
 	for _, StockMutation := range pharmacies {
-		StockMutationres := dto.NewStockMutationRes(StockMutation, c.Request.Context().Value("user_id").(uint))
+		StockMutationres := dto.NewStockMutationRes(StockMutation)
 		pharmaciesRes = append(pharmaciesRes, StockMutationres)
 	}
 	c.JSON(http.StatusOK, dto.Response{Data: pharmaciesRes,
@@ -93,7 +93,7 @@ func (h *StockMutationHandler) GetStockMutationDetail(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{Data: dto.NewStockMutationRes(stockMutation, c.Request.Context().Value("user_id").(uint))})
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewStockMutationRes(stockMutation)})
 }
 
 func (h *StockMutationHandler) GetAllAvailablePharmacyStockMutation(c *gin.Context) {
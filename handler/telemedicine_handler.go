@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/night1010/everhealth/dto"
@@ -31,6 +32,20 @@ func (h *TelemedicineHandler) PostTelemedicine(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTelemedicinRes(telemedicine), Message: "created success"})
 }
 
+func (h *TelemedicineHandler) PostBookingTelemedicine(c *gin.Context) {
+	var request dto.TelemedicineBookingReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	telemedicine, err := h.telemedicineUsecase.BookTelemedicine(c.Request.Context(), &entity.Telemedicine{DoctorId: request.DoctorId, ScheduledAt: &request.ScheduledAt})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTelemedicinRes(telemedicine), Message: "booked success"})
+}
+
 func (h *TelemedicineHandler) PostPaymentTelemedicine(c *gin.Context) {
 	var request dto.RequestUri
 	if err := c.ShouldBindUri(&request); err != nil {
@@ -123,6 +138,22 @@ func (h *TelemedicineHandler) GetTelemedicineDetail(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTelemedicinRes(telemedicine)})
 }
 
+func (h *TelemedicineHandler) GetTranscript(c *gin.Context) {
+	var telemedicineUri dto.TelemedicineUri
+	if err := c.ShouldBindUri(&telemedicineUri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	transcript, err := h.telemedicineUsecase.GetTranscript(c.Request.Context(), telemedicineUri.Id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, "application/pdf", transcript, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=transcript-%d.pdf", telemedicineUri.Id),
+	})
+}
+
 func (h *TelemedicineHandler) EndChat(c *gin.Context) {
 	var telemedicineUri dto.RequestUri
 	if err := c.ShouldBindUri(&telemedicineUri); err != nil {
@@ -140,3 +171,35 @@ func (h *TelemedicineHandler) EndChat(c *gin.Context) {
 		Message: "ended telemedicine successfully",
 	})
 }
+
+func (h *TelemedicineHandler) PostQueue(c *gin.Context) {
+	var request dto.TelemedicineQueueReq
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	queue, err := h.telemedicineUsecase.JoinQueue(c.Request.Context(), request.DoctorId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTelemedicineQueueRes(queue), Message: "joined queue"})
+}
+
+func (h *TelemedicineHandler) GetQueue(c *gin.Context) {
+	queue, err := h.telemedicineUsecase.FindQueue(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewTelemedicineQueueRes(queue)})
+}
+
+func (h *TelemedicineHandler) DeleteQueue(c *gin.Context) {
+	err := h.telemedicineUsecase.LeaveQueue(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "left queue"})
+}
@@ -108,6 +108,8 @@ func (h *UserHandler) ListDoctor(c *gin.Context) {
 			YearOfExperience: doctor.YearOfExperience,
 			Certificate:      doctor.Certificate,
 			Status:           entity.DoctorStatusMap[doctor.Status],
+			Rating:           doctor.RatingAverage.StringFixed(2),
+			ReviewCount:      doctor.ReviewCount,
 		})
 	}
 	c.JSON(200, dto.Response{
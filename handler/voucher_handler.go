@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/dto"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/usecase"
+)
+
+type VoucherHandler struct {
+	usecase usecase.VoucherUsecase
+}
+
+func NewVoucherHandler(u usecase.VoucherUsecase) *VoucherHandler {
+	return &VoucherHandler{usecase: u}
+}
+
+func (h *VoucherHandler) ListVouchers(c *gin.Context) {
+	var request dto.ListVoucherQueryParam
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	query, err := request.ToQuery()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	pagedResult, err := h.usecase.ListVouchers(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	data := []*dto.VoucherResponse{}
+	for _, voucher := range pagedResult.Data.([]*entity.Voucher) {
+		data = append(data, dto.NewVoucherResponse(voucher))
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Data:        data,
+		CurrentPage: &pagedResult.CurrentPage,
+		CurrentItem: &pagedResult.CurrentItems,
+		TotalPage:   &pagedResult.TotalPage,
+		TotalItem:   &pagedResult.TotalItem,
+	})
+}
+
+func (h *VoucherHandler) GetVoucher(c *gin.Context) {
+	var uri dto.VoucherUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	voucher, err := h.usecase.GetVoucher(c.Request.Context(), uri.Id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewVoucherResponse(voucher)})
+}
+
+func (h *VoucherHandler) CreateVoucher(c *gin.Context) {
+	var request dto.VoucherRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	voucher, err := request.ToModel()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	createdVoucher, err := h.usecase.CreateVoucher(c.Request.Context(), voucher)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{Data: dto.NewVoucherResponse(createdVoucher)})
+}
+
+func (h *VoucherHandler) UpdateVoucher(c *gin.Context) {
+	var uri dto.VoucherUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var request dto.VoucherRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	voucher, err := request.ToModel()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	voucher.Id = uri.Id
+	updatedVoucher, err := h.usecase.UpdateVoucher(c.Request.Context(), voucher)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Data: dto.NewVoucherResponse(updatedVoucher)})
+}
+
+func (h *VoucherHandler) DeleteVoucher(c *gin.Context) {
+	var uri dto.VoucherUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err := h.usecase.DeleteVoucher(c.Request.Context(), uri.Id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Message: "delete success"})
+}
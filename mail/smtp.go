@@ -3,6 +3,7 @@ package mail
 import (
 	"fmt"
 	"net/smtp"
+	"time"
 
 	"github.com/night1010/everhealth/config"
 	"github.com/jordan-wright/email"
@@ -17,6 +18,8 @@ const (
 type SmtpGmail interface {
 	SendEmail(string, string, bool) error
 	SendEmailTest(string, string, bool) error
+	SendLockoutEmail(string, time.Duration) error
+	SendAccountExistsEmail(string) error
 }
 
 type smtpGmail struct {
@@ -89,6 +92,45 @@ func emailForgotPasswordContent(link string) (subject, content string) {
 	return subject, content
 }
 
+func emailNoticeContent(intro, body string) string {
+	return fmt.Sprintf(`
+	<div style="background-color: #F2F2F2; padding: 5px; border-radius: 0.5rem; display: grid; grid-template-columns: 1fr; gap: 2rem; align-items: center; justify-items: center; height: 100vh;">
+		<div style="display: grid; grid-template-columns: 1fr; background-color: white; width: 100%%; border-radius: 0.5rem; align-items: center; gap: 2rem; padding: 2rem; margin: auto; text-align: center;">
+			<div>
+				<img style="height: auto; width: 10rem; object-fit: contain; margin-top: 2rem;" src="https://everhealth-asset.irfancen.com/assets/eh.png" alt="Everhealth logo" />
+			</div>
+
+			<div style="width: 25rem; margin: auto;">
+				<h1 style="color: black; margin: 0;">Hello!</h1>
+				<p>%s <span style="color: #36A5B2; font-weight: bold;">Everhealth!</span></p>
+				<br />
+				%s
+				<p>Best,</p>
+				<p style="margin-bottom: 3rem; color: #36A5B2; font-weight: bold;">Everhealth</p>
+			</div>
+		</div>
+	</div>
+	`, intro, body)
+}
+
+func emailLockoutContent(duration time.Duration) (subject, content string) {
+	subject = "Account Temporarily Locked"
+	content = emailNoticeContent("Security Alert", fmt.Sprintf(`<p>We noticed too many failed sign in attempts on your account, so it has been locked for %v.</p>
+				<br />
+				<p>If this wasn't you, we recommend resetting your password once the lock expires.</p>`, duration))
+	return subject, content
+}
+
+func emailAccountExistsContent(link string) (subject, content string) {
+	subject = "Account Already Registered"
+	content = emailNoticeContent("Welcome back to", fmt.Sprintf(`<p>Someone tried to register with this email, but it already belongs to an account. If you forgot your password, you can reset it below:</p>
+				<br />
+				<a href="%s" style="text-decoration: none; color: white; background-color: #36A5B2; padding: 10px 20px; border-radius: 0.3rem; font-weight: bold; display: inline-block;">Reset Password</a>
+				<br />
+				<p>If this wasn't you, you can safely ignore this email.</p>`, link))
+	return subject, content
+}
+
 func (r *smtpGmail) SendEmail(token, to string, isVerify bool) error {
 	receiver := []string{to}
 	link := r.prefixLink + token
@@ -98,13 +140,7 @@ func (r *smtpGmail) SendEmail(token, to string, isVerify bool) error {
 	} else {
 		subject, content = emailForgotPasswordContent(link)
 	}
-	e := email.NewEmail()
-	e.From = fmt.Sprintf("%s <%s>", r.name, r.address)
-	e.Subject = subject
-	e.HTML = []byte(content)
-	e.To = receiver
-	smtpAuth := smtp.PlainAuth("", r.address, r.password, smtpAuthAddress)
-	return e.Send(smtpServerAddress, smtpAuth)
+	return r.send(receiver, subject, content)
 }
 
 func (r *smtpGmail) SendEmailTest(token, to string, isVerify bool) error {
@@ -120,3 +156,23 @@ func (r *smtpGmail) SendEmailTest(token, to string, isVerify bool) error {
 	smtpAuth := smtp.PlainAuth("", "", "", "localhost")
 	return smtp.SendMail(smtpTestServer, smtpAuth, r.address, receiver, []byte(message))
 }
+
+func (r *smtpGmail) SendLockoutEmail(to string, duration time.Duration) error {
+	subject, content := emailLockoutContent(duration)
+	return r.send([]string{to}, subject, content)
+}
+
+func (r *smtpGmail) SendAccountExistsEmail(to string) error {
+	subject, content := emailAccountExistsContent(r.prefixLink + "forgot-password")
+	return r.send([]string{to}, subject, content)
+}
+
+func (r *smtpGmail) send(to []string, subject, content string) error {
+	e := email.NewEmail()
+	e.From = fmt.Sprintf("%s <%s>", r.name, r.address)
+	e.Subject = subject
+	e.HTML = []byte(content)
+	e.To = to
+	smtpAuth := smtp.PlainAuth("", r.address, r.password, smtpAuthAddress)
+	return e.Send(smtpServerAddress, smtpAuth)
+}
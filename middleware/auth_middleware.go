@@ -7,33 +7,49 @@ import (
 	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/appjwt"
 	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/rbac"
 	"github.com/night1010/everhealth/util"
 	"github.com/gin-gonic/gin"
 )
 
-func Auth(roles ...entity.RoleId) gin.HandlerFunc {
+type Authenticator struct {
+	jwt      appjwt.Jwt
+	resolver *rbac.Resolver
+}
+
+func NewAuthenticator(jwt appjwt.Jwt, resolver *rbac.Resolver) *Authenticator {
+	return &Authenticator{jwt: jwt, resolver: resolver}
+}
+
+func (a *Authenticator) Auth(roles ...entity.RoleId) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		bearerToken := c.GetHeader("Authorization")
-		token, err := extractBearerToken(bearerToken)
+		grant, err := a.authenticate(c)
 		if err != nil {
 			c.Abort()
 			_ = c.Error(err)
 			return
 		}
 
-		newJwt := appjwt.NewJwt()
-		claims, err := newJwt.ValidateToken(token)
-		if err != nil {
+		if !util.IsMemberOf(roles, grant.BaseRoleId) {
 			c.Abort()
-			_ = c.Error(apperror.NewInvalidTokenError())
+			_ = c.Error(apperror.NewForbiddenActionError("permission denied"))
 			return
 		}
 
-		ctx := context.WithValue(c.Request.Context(), "user_id", claims.Id)
-		ctx = context.WithValue(ctx, "role_id", claims.RoleId)
-		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func (a *Authenticator) Require(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		grant, err := a.authenticate(c)
+		if err != nil {
+			c.Abort()
+			_ = c.Error(err)
+			return
+		}
 
-		if !util.IsMemberOf(roles, claims.RoleId) {
+		if !grant.Can(permissions...) {
 			c.Abort()
 			_ = c.Error(apperror.NewForbiddenActionError("permission denied"))
 			return
@@ -43,6 +59,30 @@ func Auth(roles ...entity.RoleId) gin.HandlerFunc {
 	}
 }
 
+func (a *Authenticator) authenticate(c *gin.Context) (*rbac.Grant, error) {
+	bearerToken := c.GetHeader("Authorization")
+	token, err := extractBearerToken(bearerToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := a.jwt.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		return nil, apperror.NewInvalidTokenError()
+	}
+
+	grant, err := a.resolver.Resolve(c.Request.Context(), claims.RoleId)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "user_id", claims.Id)
+	ctx = context.WithValue(ctx, "role_id", grant.BaseRoleId)
+	ctx = rbac.WithGrant(ctx, grant)
+	c.Request = c.Request.WithContext(ctx)
+	return grant, nil
+}
+
 func extractBearerToken(bearerToken string) (string, error) {
 	if bearerToken == "" {
 		return "", apperror.NewMissingTokenError()
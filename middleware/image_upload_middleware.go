@@ -3,13 +3,24 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/night1010/everhealth/apperror"
 	"github.com/gin-gonic/gin"
 )
 
-func ImageUploadMiddleware() gin.HandlerFunc {
+func ImageUploadMiddleware(imageTypes ...string) gin.HandlerFunc {
+	if len(imageTypes) == 0 {
+		imageTypes = []string{"image/png"}
+	}
+	var typeNames []string
+	for _, imageType := range imageTypes {
+		typeNames = append(typeNames, strings.TrimPrefix(imageType, "image/"))
+	}
+	errImageType := fmt.Errorf("image type must be %s", strings.Join(typeNames, " or "))
+
 	return func(c *gin.Context) {
 		const (
 			MB = 1 << 20
@@ -20,7 +31,7 @@ func ImageUploadMiddleware() gin.HandlerFunc {
 		}
 		file, _, err := c.Request.FormFile("image")
 		if err != nil {
-			if err == http.ErrMissingFile && c.Request.Method == http.MethodPut {
+			if err == http.ErrMissingFile && (c.Request.Method == http.MethodPut || c.Request.Context().Value("pdf") != nil) {
 				c.Next()
 				return
 			}
@@ -59,13 +70,15 @@ func ImageUploadMiddleware() gin.HandlerFunc {
 			return
 		}
 		imageType := http.DetectContentType(fileHeader)
-		if imageType == "image/png" {
-			ctx := context.WithValue(c.Request.Context(), "image", file)
-			c.Request = c.Request.WithContext(ctx)
-			c.Next()
-			return
+		for _, allowedType := range imageTypes {
+			if imageType == allowedType {
+				ctx := context.WithValue(c.Request.Context(), "image", file)
+				c.Request = c.Request.WithContext(ctx)
+				c.Next()
+				return
+			}
 		}
-		c.Error(apperror.NewClientError(errors.New("image type must be png")))
+		c.Error(apperror.NewClientError(errImageType))
 		c.Abort()
 	}
 }
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/night1010/everhealth/apperror"
+)
+
+func MultipleImageUpload(maxImage int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const (
+			MB = 1 << 20
+		)
+
+		if err := c.Request.ParseMultipartForm(2 * MB); err != nil {
+			c.Error(apperror.NewClientError(err))
+			c.Abort()
+			return
+		}
+
+		fileHeaders := c.Request.MultipartForm.File["images"]
+		if len(fileHeaders) > maxImage {
+			c.Error(apperror.NewClientError(fmt.Errorf("cannot upload more than %v images", maxImage)))
+			c.Abort()
+			return
+		}
+
+		var files []multipart.File
+		defer func() {
+			for _, file := range files {
+				_ = file.Close()
+			}
+		}()
+		for _, fileHeader := range fileHeaders {
+			if fileHeader.Size > 500000 {
+				c.Error(apperror.NewClientError(errors.New("image must below 500 kb")))
+				c.Abort()
+				return
+			}
+			file, err := fileHeader.Open()
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			files = append(files, file)
+
+			header := make([]byte, 512)
+			if _, err := file.Read(header); err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			if _, err := file.Seek(0, 0); err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			if http.DetectContentType(header) != "image/png" {
+				c.Error(apperror.NewClientError(errors.New("image type must be png")))
+				c.Abort()
+				return
+			}
+		}
+
+		ctx := context.WithValue(c.Request.Context(), "images", files)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -30,12 +30,38 @@ func Migrate(db *gorm.DB) {
 	sm := &entity.StockMutation{}
 	po := &entity.ProductOrder{}
 	oi := &entity.OrderItem{}
+	osh := &entity.OrderShipment{}
+	osHistory := &entity.OrderStatusHistory{}
+	invoice := &entity.Invoice{}
+	voucher := &entity.Voucher{}
+	orderPrescription := &entity.OrderPrescription{}
+	prescriptionItem := &entity.PrescriptionItem{}
+	voucherUsage := &entity.VoucherUsage{}
+	sr := &entity.StockReservation{}
+	pay := &entity.Payment{}
+	refund := &entity.Refund{}
+	refundItem := &entity.RefundItem{}
 	ac := &entity.AdminContact{}
 	telemedicine := &entity.Telemedicine{}
 	chat := &entity.Chat{}
+	doctorAvailability := &entity.DoctorAvailability{}
+	doctorAvailabilityException := &entity.DoctorAvailabilityException{}
+	telemedicineQueue := &entity.TelemedicineQueue{}
+	doctorReview := &entity.DoctorReview{}
+	productReview := &entity.ProductReview{}
+	productReviewPhoto := &entity.ProductReviewPhoto{}
+	chatReceipt := &entity.ChatReceipt{}
+	refreshToken := &entity.RefreshToken{}
+	userTwoFactor := &entity.UserTwoFactor{}
+	twoFactorRecoveryCode := &entity.TwoFactorRecoveryCode{}
+	authAttempt := &entity.AuthAttempt{}
+	permission := &entity.Permission{}
+	pharmacyStaff := &entity.PharmacyStaff{}
+	chatPresence := &entity.ChatPresence{}
 	// _ = db.SetupJoinTable(pharmacy, "Products", pharmacyProduct)
 
-	_ = db.Migrator().DropTable(u, drugForm, pc, p, d, dc, r, dp, ds, profile, ftp, pharmacy, pharmacyProduct, pr, ct, ors, spm, a, c, ci, ts, sm, ac, po, oi, telemedicine, chat)
+	_ = db.Migrator().DropTable("role_permissions")
+	_ = db.Migrator().DropTable(u, drugForm, pc, p, d, dc, r, dp, ds, profile, ftp, pharmacy, pharmacyProduct, pr, ct, ors, spm, a, c, ci, ts, sm, ac, po, osh, osHistory, invoice, orderPrescription, voucher, voucherUsage, oi, sr, telemedicine, prescriptionItem, pay, refund, refundItem, chat, doctorAvailability, doctorAvailabilityException, telemedicineQueue, doctorReview, productReview, productReviewPhoto, chatReceipt, refreshToken, userTwoFactor, twoFactorRecoveryCode, authAttempt, permission, pharmacyStaff, chatPresence)
 
-	_ = db.AutoMigrate(u, drugForm, pc, p, d, dc, r, dp, ds, profile, ftp, pharmacy, pharmacyProduct, pr, ct, ors, spm, a, c, ci, ts, sm, ac, po, oi, telemedicine, chat)
+	_ = db.AutoMigrate(u, drugForm, pc, p, d, dc, r, dp, ds, profile, ftp, pharmacy, pharmacyProduct, pr, ct, ors, spm, a, c, ci, ts, sm, ac, po, osh, osHistory, invoice, orderPrescription, voucher, voucherUsage, oi, sr, telemedicine, prescriptionItem, pay, refund, refundItem, chat, doctorAvailability, doctorAvailabilityException, telemedicineQueue, doctorReview, productReview, productReviewPhoto, chatReceipt, refreshToken, userTwoFactor, twoFactorRecoveryCode, authAttempt, permission, pharmacyStaff, chatPresence)
 }
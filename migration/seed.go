@@ -13,10 +13,19 @@ import (
 
 func Seed(db *gorm.DB) {
 	roles := []*entity.Role{
-		{Id: entity.RoleUser, Name: "user"},
-		{Id: entity.RoleDoctor, Name: "doctor"},
-		{Id: entity.RoleAdmin, Name: "pharmacist-admin"},
-		{Id: entity.RoleSuperAdmin, Name: "super-admin"},
+		{Id: entity.RoleUser, Name: "user", BaseRoleId: entity.RoleUser, IsSystem: true},
+		{Id: entity.RoleDoctor, Name: "doctor", BaseRoleId: entity.RoleDoctor, IsSystem: true},
+		{Id: entity.RoleAdmin, Name: "pharmacist-admin", BaseRoleId: entity.RoleAdmin, IsSystem: true},
+		{Id: entity.RoleSuperAdmin, Name: "super-admin", BaseRoleId: entity.RoleSuperAdmin, IsSystem: true},
+	}
+	permissionM := make(map[string]*entity.Permission)
+	for _, permission := range entity.Permissions {
+		permissionM[permission.Name] = permission
+	}
+	for _, role := range roles {
+		for _, name := range entity.DefaultRolePermissions[role.Id] {
+			role.Permissions = append(role.Permissions, permissionM[name])
+		}
 	}
 
 	users := []*entity.User{
@@ -90,7 +99,7 @@ func Seed(db *gorm.DB) {
 
 	drugClassifications := []*entity.DrugClassification{
 		{Name: "obat bebas"},
-		{Name: "obat keras"},
+		{Name: "obat keras", RequiresPrescription: true},
 		{Name: "obat bebas terbatas"},
 	}
 
@@ -393,6 +402,24 @@ func Seed(db *gorm.DB) {
 		},
 	}
 
+	subTotalM := make(map[uint]decimal.Decimal)
+	for i := range orderItems {
+		orderItems[i].ShipmentId = orderItems[i].OrderId
+		subTotalM[orderItems[i].OrderId] = subTotalM[orderItems[i].OrderId].Add(orderItems[i].SubTotal)
+	}
+	var orderShipments []entity.OrderShipment
+	for i, order := range orders {
+		orderShipments = append(orderShipments, entity.OrderShipment{
+			OrderId:       uint(i + 1),
+			PharmacyId:    1,
+			OrderStatusId: order.OrderStatusId,
+			ShippingName:  order.ShippingName,
+			ShippingEta:   order.ShippingEta,
+			ShippingPrice: order.ShippingPrice,
+			SubTotal:      subTotalM[uint(i+1)],
+		})
+	}
+
 	pharmacyProduct := generatePharmacyProduct(301, 0)
 	pharmacyProduct2 := generatePharmacyProduct(601, 300)
 	pharmacyProduct3 := generatePharmacyProduct(908, 600)
@@ -422,7 +449,9 @@ func Seed(db *gorm.DB) {
 		{PharmacyProductId: 21, Quantity: 15, IsReduction: false, ChangeAt: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)},
 		{PharmacyProductId: 21, Quantity: 10, IsReduction: true, ChangeAt: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)},
 	}
+	db.Create(entity.Permissions)
 	db.Create(roles)
+	db.Exec("SELECT setval(pg_get_serial_sequence('roles', 'id'), (SELECT MAX(id) FROM roles))")
 	db.Create(doctorSpecialist)
 	db.Create(users)
 	db.Create(adminContact)
@@ -448,8 +477,14 @@ func Seed(db *gorm.DB) {
 	db.Create(cities)
 	db.Create(addresses)
 	db.Create(pharmacies)
+	var pharmacyStaffs []*entity.PharmacyStaff
+	for _, pharmacy := range pharmacies {
+		pharmacyStaffs = append(pharmacyStaffs, &entity.PharmacyStaff{PharmacyId: pharmacy.Id, UserId: pharmacy.AdminId, Role: entity.PharmacyOwner})
+	}
+	db.Create(pharmacyStaffs)
 	db.Create(carts)
 	db.Create(orders)
+	db.Create(orderShipments)
 	db.Create(pharmacyProduct)
 	db.Create(pharmacyProduct2)
 	db.Create(pharmacyProduct3)
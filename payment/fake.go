@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+const FakeSignatureHeader = "X-Fake-Signature"
+
+type FakeWebhookBody struct {
+	Reference  string          `json:"reference"`
+	ExternalId string          `json:"external_id"`
+	Status     Status          `json:"status"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+type FakeProvider struct {
+	secret  string
+	mu      sync.Mutex
+	counter int
+	charges map[string]*Charge
+}
+
+func NewFakeProvider(secret string) (*FakeProvider, error) {
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+	return &FakeProvider{secret: secret, charges: make(map[string]*Charge)}, nil
+}
+
+func (p *FakeProvider) Name() string {
+	return FakeMethod
+}
+
+func (p *FakeProvider) CreateCharge(ctx context.Context, reference string, amount decimal.Decimal) (*Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counter++
+	externalId := fmt.Sprintf("fake-%d", p.counter)
+	charge := &Charge{
+		Reference:  reference,
+		ExternalId: externalId,
+		Amount:     amount,
+		Status:     StatusPending,
+		PaymentUrl: "https://fake-payment.local/pay/" + externalId,
+	}
+	p.charges[externalId] = charge
+	copied := *charge
+	return &copied, nil
+}
+
+func (p *FakeProvider) VerifyWebhook(ctx context.Context, header http.Header, body []byte) (*Notification, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+	var webhookBody FakeWebhookBody
+	if err := json.Unmarshal(body, &webhookBody); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	if charge, ok := p.charges[webhookBody.ExternalId]; ok {
+		charge.Status = webhookBody.Status
+	}
+	p.mu.Unlock()
+	return &Notification{
+		Reference:  webhookBody.Reference,
+		ExternalId: webhookBody.ExternalId,
+		Status:     webhookBody.Status,
+		Amount:     webhookBody.Amount,
+	}, nil
+}
+
+func (p *FakeProvider) QueryStatus(ctx context.Context, externalId string) (Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	charge, ok := p.charges[externalId]
+	if !ok {
+		return "", ErrChargeNotFound
+	}
+	return charge.Status, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, externalId string, amount decimal.Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	charge, ok := p.charges[externalId]
+	if !ok {
+		return ErrChargeNotFound
+	}
+	if charge.Status != StatusPaid && charge.Status != StatusRefunded {
+		return fmt.Errorf("cannot refund %s charge", charge.Status)
+	}
+	if amount.GreaterThan(charge.Amount) {
+		return fmt.Errorf("refund amount exceeds charge amount")
+	}
+	charge.Amount = charge.Amount.Sub(amount)
+	charge.Status = StatusRefunded
+	return nil
+}
+
+func (p *FakeProvider) Sign(body []byte) string {
+	return hex.EncodeToString(p.sign(body))
+}
+
+func (p *FakeProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
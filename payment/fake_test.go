@@ -0,0 +1,58 @@
+package payment_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/night1010/everhealth/payment"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFakeProvider(t *testing.T) {
+	t.Run("should reject empty secret", func(t *testing.T) {
+		provider, err := payment.NewFakeProvider("")
+
+		assert.Nil(t, provider)
+		assert.ErrorIs(t, err, payment.ErrMissingSecret)
+	})
+}
+
+func TestFakeProviderVerifyWebhook(t *testing.T) {
+	provider, err := payment.NewFakeProvider("secret")
+	assert.NoError(t, err)
+	charge, err := provider.CreateCharge(context.Background(), "order-1", decimal.NewFromInt(15000))
+	assert.NoError(t, err)
+	assert.Equal(t, payment.StatusPending, charge.Status)
+
+	body, _ := json.Marshal(payment.FakeWebhookBody{
+		Reference:  charge.Reference,
+		ExternalId: charge.ExternalId,
+		Status:     payment.StatusPaid,
+		Amount:     charge.Amount,
+	})
+
+	t.Run("should reject invalid signature", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(payment.FakeSignatureHeader, "00")
+
+		_, err := provider.VerifyWebhook(context.Background(), header, body)
+
+		assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+	})
+
+	t.Run("should accept signed body and mark charge paid", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(payment.FakeSignatureHeader, provider.Sign(body))
+
+		notification, err := provider.VerifyWebhook(context.Background(), header, body)
+		status, _ := provider.QueryStatus(context.Background(), charge.ExternalId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "order-1", notification.Reference)
+		assert.True(t, charge.Amount.Equal(notification.Amount))
+		assert.Equal(t, payment.StatusPaid, status)
+	})
+}
@@ -0,0 +1,69 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/night1010/everhealth/config"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ManualMethod = "manual"
+	FakeMethod   = "fake"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusPaid     Status = "paid"
+	StatusFailed   Status = "failed"
+	StatusRefunded Status = "refunded"
+)
+
+var (
+	ErrInvalidSignature    = errors.New("invalid webhook signature")
+	ErrWebhookNotSupported = errors.New("provider does not support webhook")
+	ErrChargeNotFound      = errors.New("charge not found")
+	ErrMissingSecret       = errors.New("payment provider secret is not configured")
+)
+
+type Charge struct {
+	Reference  string
+	ExternalId string
+	Amount     decimal.Decimal
+	Status     Status
+	PaymentUrl string
+}
+
+type Notification struct {
+	Reference  string
+	ExternalId string
+	Status     Status
+	Amount     decimal.Decimal
+}
+
+type PaymentProvider interface {
+	Name() string
+	CreateCharge(ctx context.Context, reference string, amount decimal.Decimal) (*Charge, error)
+	VerifyWebhook(ctx context.Context, header http.Header, body []byte) (*Notification, error)
+	QueryStatus(ctx context.Context, externalId string) (Status, error)
+	Refund(ctx context.Context, externalId string, amount decimal.Decimal) error
+}
+
+func NewPaymentProvider(impl string) (PaymentProvider, error) {
+	if impl == ManualMethod {
+		return &manualProvider{}, nil
+	}
+	if impl == FakeMethod {
+		paymentConfig := config.NewPaymentConfig()
+		provider, err := NewFakeProvider(paymentConfig.FakeSecret)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	}
+	return nil, errors.New("unknown payment provider " + impl)
+}
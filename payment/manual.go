@@ -0,0 +1,35 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+)
+
+type manualProvider struct{}
+
+func (p *manualProvider) Name() string {
+	return ManualMethod
+}
+
+func (p *manualProvider) CreateCharge(ctx context.Context, reference string, amount decimal.Decimal) (*Charge, error) {
+	return &Charge{
+		Reference:  reference,
+		ExternalId: reference,
+		Amount:     amount,
+		Status:     StatusPending,
+	}, nil
+}
+
+func (p *manualProvider) VerifyWebhook(ctx context.Context, header http.Header, body []byte) (*Notification, error) {
+	return nil, ErrWebhookNotSupported
+}
+
+func (p *manualProvider) QueryStatus(ctx context.Context, externalId string) (Status, error) {
+	return StatusPending, nil
+}
+
+func (p *manualProvider) Refund(ctx context.Context, externalId string, amount decimal.Decimal) error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package promotion
+
+import (
+	"errors"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrVoucherInactive    = errors.New("voucher is not active")
+	ErrVoucherNotStarted  = errors.New("voucher is not valid yet")
+	ErrVoucherExpired     = errors.New("voucher has expired")
+	ErrQuotaExhausted     = errors.New("voucher quota has been used up")
+	ErrUserLimitReached   = errors.New("voucher usage limit for this user has been reached")
+	ErrNoEligibleItem     = errors.New("no item in this order is eligible for the voucher")
+	ErrMinSpendNotReached = errors.New("order does not reach the minimum spend of the voucher")
+)
+
+type Line struct {
+	ProductCategoryId uint
+	SubTotal          decimal.Decimal
+}
+
+type Cart struct {
+	Lines        []*Line
+	ShippingCost decimal.Decimal
+	UserUsage    int
+	Now          time.Time
+}
+
+type Result struct {
+	Discount         decimal.Decimal
+	ShippingDiscount decimal.Decimal
+}
+
+func (r *Result) Total() decimal.Decimal {
+	return r.Discount.Add(r.ShippingDiscount)
+}
+
+func Validate(voucher *entity.Voucher, cart *Cart) error {
+	if !voucher.IsActive {
+		return ErrVoucherInactive
+	}
+	if cart.Now.Before(voucher.StartAt) {
+		return ErrVoucherNotStarted
+	}
+	if !cart.Now.Before(voucher.EndAt) {
+		return ErrVoucherExpired
+	}
+	if voucher.Quota > 0 && voucher.UsedCount >= voucher.Quota {
+		return ErrQuotaExhausted
+	}
+	if voucher.PerUserLimit > 0 && cart.UserUsage >= voucher.PerUserLimit {
+		return ErrUserLimitReached
+	}
+	return nil
+}
+
+func Apply(voucher *entity.Voucher, cart *Cart) (*Result, error) {
+	err := Validate(voucher, cart)
+	if err != nil {
+		return nil, err
+	}
+	var eligible decimal.Decimal
+	var found bool
+	for _, line := range cart.Lines {
+		if voucher.ProductCategoryId != nil && *voucher.ProductCategoryId != line.ProductCategoryId {
+			continue
+		}
+		eligible = eligible.Add(line.SubTotal)
+		found = true
+	}
+	if !found {
+		return nil, ErrNoEligibleItem
+	}
+	if eligible.LessThan(voucher.MinSpend) {
+		return nil, ErrMinSpendNotReached
+	}
+	result := &Result{}
+	switch voucher.Type {
+	case entity.VoucherPercentage:
+		result.Discount = capDiscount(eligible.Mul(voucher.Value).Div(decimal.NewFromInt(100)).Round(2), voucher.MaxDiscount)
+	case entity.VoucherFixed:
+		result.Discount = voucher.Value
+	case entity.VoucherFreeShipping:
+		result.ShippingDiscount = capDiscount(cart.ShippingCost, voucher.MaxDiscount)
+	}
+	if result.Discount.GreaterThan(eligible) {
+		result.Discount = eligible
+	}
+	return result, nil
+}
+
+func capDiscount(discount decimal.Decimal, max decimal.NullDecimal) decimal.Decimal {
+	if max.Valid && discount.GreaterThan(max.Decimal) {
+		return max.Decimal
+	}
+	return discount
+}
@@ -0,0 +1,95 @@
+package promotion_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/promotion"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	now := time.Now()
+	category := uint(2)
+	cart := &promotion.Cart{
+		Lines: []*promotion.Line{
+			{ProductCategoryId: 1, SubTotal: decimal.NewFromInt(60000)},
+			{ProductCategoryId: category, SubTotal: decimal.NewFromInt(40000)},
+		},
+		ShippingCost: decimal.NewFromInt(15000),
+		Now:          now,
+	}
+	newVoucher := func(voucherType entity.VoucherType, value int64) *entity.Voucher {
+		return &entity.Voucher{
+			Type:     voucherType,
+			Value:    decimal.NewFromInt(value),
+			StartAt:  now.Add(-time.Hour),
+			EndAt:    now.Add(time.Hour),
+			IsActive: true,
+		}
+	}
+
+	t.Run("should cap percentage discount", func(t *testing.T) {
+		voucher := newVoucher(entity.VoucherPercentage, 10)
+		voucher.MaxDiscount = decimal.NewNullDecimal(decimal.NewFromInt(5000))
+
+		result, err := promotion.Apply(voucher, cart)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Discount.Equal(decimal.NewFromInt(5000)))
+	})
+
+	t.Run("should only count items of the voucher category", func(t *testing.T) {
+		voucher := newVoucher(entity.VoucherFixed, 50000)
+		voucher.ProductCategoryId = &category
+
+		result, err := promotion.Apply(voucher, cart)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Discount.Equal(decimal.NewFromInt(40000)))
+	})
+
+	t.Run("should discount shipping for free shipping voucher", func(t *testing.T) {
+		voucher := newVoucher(entity.VoucherFreeShipping, 0)
+
+		result, err := promotion.Apply(voucher, cart)
+
+		assert.NoError(t, err)
+		assert.True(t, result.ShippingDiscount.Equal(cart.ShippingCost))
+		assert.True(t, result.Discount.IsZero())
+	})
+
+	t.Run("should reject when minimum spend is not reached", func(t *testing.T) {
+		voucher := newVoucher(entity.VoucherFixed, 5000)
+		voucher.MinSpend = decimal.NewFromInt(150000)
+
+		_, err := promotion.Apply(voucher, cart)
+
+		assert.ErrorIs(t, err, promotion.ErrMinSpendNotReached)
+	})
+
+	t.Run("should reject when quota or user limit is used up", func(t *testing.T) {
+		voucher := newVoucher(entity.VoucherFixed, 5000)
+		voucher.Quota = 1
+		voucher.UsedCount = 1
+
+		_, err := promotion.Apply(voucher, cart)
+		assert.ErrorIs(t, err, promotion.ErrQuotaExhausted)
+
+		voucher.Quota = 0
+		voucher.PerUserLimit = 1
+		_, err = promotion.Apply(voucher, &promotion.Cart{Lines: cart.Lines, UserUsage: 1, Now: now})
+		assert.ErrorIs(t, err, promotion.ErrUserLimitReached)
+	})
+
+	t.Run("should reject outside validity window", func(t *testing.T) {
+		voucher := newVoucher(entity.VoucherFixed, 5000)
+		voucher.EndAt = now
+
+		_, err := promotion.Apply(voucher, cart)
+
+		assert.ErrorIs(t, err, promotion.ErrVoucherExpired)
+	})
+}
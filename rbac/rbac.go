@@ -0,0 +1,115 @@
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+)
+
+// cacheDuration bounds how long a changed role keeps its old permissions on
+// other instances, since Invalidate only clears the local cache.
+const cacheDuration = 10 * time.Second
+
+type Grant struct {
+	RoleId      entity.RoleId
+	BaseRoleId  entity.RoleId
+	Permissions map[string]bool
+}
+
+func NewGrant(role *entity.Role) *Grant {
+	grant := &Grant{
+		RoleId:      role.Id,
+		BaseRoleId:  role.GetBaseRoleId(),
+		Permissions: make(map[string]bool, len(role.Permissions)),
+	}
+	for _, permission := range role.Permissions {
+		grant.Permissions[permission.Name] = true
+	}
+	return grant
+}
+
+func (g *Grant) Can(permissions ...string) bool {
+	for _, permission := range permissions {
+		if !g.Permissions[permission] {
+			return false
+		}
+	}
+	return true
+}
+
+type RoleFinder interface {
+	FindRoleWithPermissions(ctx context.Context, roleId entity.RoleId) (*entity.Role, error)
+}
+
+type cachedGrant struct {
+	grant     *Grant
+	expiredAt time.Time
+}
+
+type Resolver struct {
+	finder RoleFinder
+	mu     sync.RWMutex
+	cache  map[entity.RoleId]*cachedGrant
+}
+
+func NewResolver(finder RoleFinder) *Resolver {
+	return &Resolver{finder: finder, cache: make(map[entity.RoleId]*cachedGrant)}
+}
+
+func (r *Resolver) Resolve(ctx context.Context, roleId entity.RoleId) (*Grant, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[roleId]
+	r.mu.RUnlock()
+	if ok && cached.expiredAt.After(time.Now()) {
+		return cached.grant, nil
+	}
+	if r.finder == nil {
+		return defaultGrant(roleId), nil
+	}
+	role, err := r.finder.FindRoleWithPermissions(ctx, roleId)
+	if err != nil {
+		return nil, err
+	}
+	grant := &Grant{RoleId: roleId, BaseRoleId: roleId}
+	if role != nil {
+		grant = NewGrant(role)
+	}
+	r.mu.Lock()
+	r.cache[roleId] = &cachedGrant{grant: grant, expiredAt: time.Now().Add(cacheDuration)}
+	r.mu.Unlock()
+	return grant, nil
+}
+
+func (r *Resolver) Invalidate(roleIds ...entity.RoleId) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(roleIds) == 0 {
+		r.cache = make(map[entity.RoleId]*cachedGrant)
+		return
+	}
+	for _, roleId := range roleIds {
+		delete(r.cache, roleId)
+	}
+}
+
+func WithGrant(ctx context.Context, grant *Grant) context.Context {
+	return context.WithValue(ctx, "grant", grant)
+}
+
+func Can(ctx context.Context, permissions ...string) bool {
+	grant, ok := ctx.Value("grant").(*Grant)
+	if !ok {
+		return false
+	}
+	return grant.Can(permissions...)
+}
+
+func defaultGrant(roleId entity.RoleId) *Grant {
+	role := &entity.Role{Id: roleId}
+	for _, name := range entity.DefaultRolePermissions[roleId] {
+		role.Permissions = append(role.Permissions, &entity.Permission{Name: name})
+	}
+	return NewGrant(role)
+}
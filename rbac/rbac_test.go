@@ -0,0 +1,66 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRoleFinder struct {
+	roles map[entity.RoleId]*entity.Role
+	calls int
+}
+
+func (f *fakeRoleFinder) FindRoleWithPermissions(ctx context.Context, roleId entity.RoleId) (*entity.Role, error) {
+	f.calls++
+	return f.roles[roleId], nil
+}
+
+func TestResolve(t *testing.T) {
+	auditor := entity.RoleId(5)
+	finder := &fakeRoleFinder{roles: map[entity.RoleId]*entity.Role{
+		auditor: {
+			Id:          auditor,
+			BaseRoleId:  entity.RoleSuperAdmin,
+			Permissions: []*entity.Permission{{Name: entity.PermissionOrderRead}, {Name: entity.PermissionRefundRead}},
+		},
+	}}
+	resolver := rbac.NewResolver(finder)
+
+	t.Run("should map custom role to its base role and permissions", func(t *testing.T) {
+		grant, err := resolver.Resolve(context.Background(), auditor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleSuperAdmin, grant.BaseRoleId)
+		assert.True(t, grant.Can(entity.PermissionOrderRead, entity.PermissionRefundRead))
+		assert.False(t, grant.Can(entity.PermissionRefundProcess))
+	})
+
+	t.Run("should cache grant until invalidated", func(t *testing.T) {
+		resolver.Invalidate(auditor)
+		calls := finder.calls
+
+		_, _ = resolver.Resolve(context.Background(), auditor)
+		_, _ = resolver.Resolve(context.Background(), auditor)
+
+		assert.Equal(t, calls+1, finder.calls)
+	})
+
+	t.Run("should deny unknown role", func(t *testing.T) {
+		grant, err := resolver.Resolve(context.Background(), entity.RoleId(99))
+
+		assert.NoError(t, err)
+		assert.False(t, grant.Can(entity.PermissionOrderRead))
+	})
+
+	t.Run("should read grant from context", func(t *testing.T) {
+		grant, _ := resolver.Resolve(context.Background(), auditor)
+		ctx := rbac.WithGrant(context.Background(), grant)
+
+		assert.True(t, rbac.Can(ctx, entity.PermissionOrderRead))
+		assert.False(t, rbac.Can(context.Background(), entity.PermissionOrderRead))
+	})
+}
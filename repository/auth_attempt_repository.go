@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuthAttemptRepository interface {
+	BaseRepository[entity.AuthAttempt]
+	FindOrCreateForUpdate(ctx context.Context, scope entity.AuthAttemptScope, identifier string) (*entity.AuthAttempt, error)
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type authAttemptRepository struct {
+	*baseRepository[entity.AuthAttempt]
+	db *gorm.DB
+}
+
+func NewAuthAttemptRepository(db *gorm.DB) AuthAttemptRepository {
+	return &authAttemptRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.AuthAttempt]{db: db},
+	}
+}
+
+func (r *authAttemptRepository) FindOrCreateForUpdate(ctx context.Context, scope entity.AuthAttemptScope, identifier string) (*entity.AuthAttempt, error) {
+	err := r.conn(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.AuthAttempt{Scope: scope, Identifier: identifier, LastFailedAt: time.Now()}).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return r.FindOne(ctx, valueobject.NewQuery().
+		Condition("scope", valueobject.Equal, scope).
+		Condition("identifier", valueobject.Equal, identifier).
+		Lock())
+}
+
+func (r *authAttemptRepository) DeleteStale(ctx context.Context, before time.Time) error {
+	return r.conn(ctx).
+		Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, time.Now()).
+		Delete(&entity.AuthAttempt{}).
+		Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatPresenceRepository interface {
+	BaseRepository[entity.ChatPresence]
+	Upsert(ctx context.Context, presence *entity.ChatPresence) (*entity.ChatPresence, error)
+	DeleteByClient(ctx context.Context, clientId string) error
+	DeleteExpired(ctx context.Context, now time.Time) ([]*entity.ChatPresence, error)
+	FindActiveUserIds(ctx context.Context, telemedicineId uint, now time.Time) ([]uint, error)
+	CountActive(ctx context.Context, userId uint, telemedicineId *uint, now time.Time) (int64, error)
+}
+
+type chatPresenceRepository struct {
+	*baseRepository[entity.ChatPresence]
+	db *gorm.DB
+}
+
+func NewChatPresenceRepository(db *gorm.DB) ChatPresenceRepository {
+	return &chatPresenceRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.ChatPresence]{db: db},
+	}
+}
+
+func (r *chatPresenceRepository) Upsert(ctx context.Context, presence *entity.ChatPresence) (*entity.ChatPresence, error) {
+	err := r.conn(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "client_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"expired_at", "updated_at"}),
+		}, clause.Returning{}).
+		Create(presence).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return presence, nil
+}
+
+func (r *chatPresenceRepository) DeleteByClient(ctx context.Context, clientId string) error {
+	return r.conn(ctx).Where("client_id = ?", clientId).Delete(&entity.ChatPresence{}).Error
+}
+
+func (r *chatPresenceRepository) DeleteExpired(ctx context.Context, now time.Time) ([]*entity.ChatPresence, error) {
+	var presences []*entity.ChatPresence
+	err := r.conn(ctx).
+		Clauses(clause.Returning{}).
+		Where("expired_at <= ?", now).
+		Delete(&presences).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return presences, nil
+}
+
+func (r *chatPresenceRepository) FindActiveUserIds(ctx context.Context, telemedicineId uint, now time.Time) ([]uint, error) {
+	var userIds []uint
+	err := r.conn(ctx).
+		Model(&entity.ChatPresence{}).
+		Distinct("user_id").
+		Where("telemedicine_id = ? AND expired_at > ?", telemedicineId, now).
+		Order("user_id").
+		Pluck("user_id", &userIds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+func (r *chatPresenceRepository) CountActive(ctx context.Context, userId uint, telemedicineId *uint, now time.Time) (int64, error) {
+	var count int64
+	db := r.conn(ctx).
+		Model(&entity.ChatPresence{}).
+		Where("user_id = ? AND expired_at > ?", userId, now)
+	if telemedicineId != nil {
+		db.Where("telemedicine_id = ?", *telemedicineId)
+	}
+	err := db.Count(&count).Error
+	return count, err
+}
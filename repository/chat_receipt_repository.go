@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatReceiptRepository interface {
+	BaseRepository[entity.ChatReceipt]
+	Upsert(ctx context.Context, receipt *entity.ChatReceipt) (*entity.ChatReceipt, error)
+}
+
+type chatReceiptRepository struct {
+	*baseRepository[entity.ChatReceipt]
+	db *gorm.DB
+}
+
+func NewChatReceiptRepository(db *gorm.DB) ChatReceiptRepository {
+	return &chatReceiptRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.ChatReceipt]{db: db},
+	}
+}
+
+func (r *chatReceiptRepository) Upsert(ctx context.Context, receipt *entity.ChatReceipt) (*entity.ChatReceipt, error) {
+	err := r.conn(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telemedicine_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "delivered_up_to"}, Value: gorm.Expr("GREATEST(chat_receipts.delivered_up_to, excluded.delivered_up_to)")},
+				{Column: clause.Column{Name: "read_up_to"}, Value: gorm.Expr("GREATEST(chat_receipts.read_up_to, excluded.read_up_to)")},
+				{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("excluded.updated_at")},
+			},
+		}, clause.Returning{}).
+		Create(receipt).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/night1010/everhealth/entity"
 	"gorm.io/gorm"
 )
 
 type ChatRepository interface {
 	BaseRepository[entity.Chat]
+	CountUnread(ctx context.Context, userId uint, telemedicineIds []uint) (map[uint]int, error)
 }
 
 type chatRepository struct {
@@ -20,3 +23,32 @@ func NewChatRepository(db *gorm.DB) ChatRepository {
 		baseRepository: &baseRepository[entity.Chat]{db: db},
 	}
 }
+
+func (r *chatRepository) CountUnread(ctx context.Context, userId uint, telemedicineIds []uint) (map[uint]int, error) {
+	var rows []struct {
+		TelemedicineId uint
+		UnreadCount    int
+	}
+	unread := make(map[uint]int)
+	if len(telemedicineIds) == 0 {
+		return unread, nil
+	}
+	err := r.conn(ctx).
+		Table("chats c").
+		Select("c.telemedicine_id, COUNT(*) AS unread_count").
+		Joins("LEFT JOIN chat_receipts r ON r.telemedicine_id = c.telemedicine_id AND r.user_id = ?", userId).
+		Where("c.telemedicine_id IN ?", telemedicineIds).
+		Where("c.user_id <> ?", userId).
+		Where("c.deleted IS NULL").
+		Where("c.id > COALESCE(r.read_up_to, 0)").
+		Group("c.telemedicine_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		unread[row.TelemedicineId] = row.UnreadCount
+	}
+	return unread, nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type DoctorAvailabilityExceptionRepository interface {
+	BaseRepository[entity.DoctorAvailabilityException]
+}
+
+type doctorAvailabilityExceptionRepository struct {
+	*baseRepository[entity.DoctorAvailabilityException]
+	db *gorm.DB
+}
+
+func NewDoctorAvailabilityExceptionRepository(db *gorm.DB) DoctorAvailabilityExceptionRepository {
+	return &doctorAvailabilityExceptionRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.DoctorAvailabilityException]{db: db},
+	}
+}
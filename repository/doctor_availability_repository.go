@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type DoctorAvailabilityRepository interface {
+	BaseRepository[entity.DoctorAvailability]
+	DeleteByDoctorId(ctx context.Context, doctorId uint) error
+}
+
+type doctorAvailabilityRepository struct {
+	*baseRepository[entity.DoctorAvailability]
+	db *gorm.DB
+}
+
+func NewDoctorAvailabilityRepository(db *gorm.DB) DoctorAvailabilityRepository {
+	return &doctorAvailabilityRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.DoctorAvailability]{db: db},
+	}
+}
+
+func (r *doctorAvailabilityRepository) DeleteByDoctorId(ctx context.Context, doctorId uint) error {
+	return r.conn(ctx).Where("doctor_id = ?", doctorId).Delete(&entity.DoctorAvailability{}).Error
+}
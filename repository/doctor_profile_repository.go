@@ -33,6 +33,8 @@ func (r *doctorProfileRepository) FindAllDoctors(ctx context.Context, query *val
 			query.WithSortBy("Status ASC,\"Profile\".name")
 		case "fee":
 			query.WithSortBy("Status ASC,fee")
+		case "rating":
+			query.WithSortBy("Status ASC,rating_average")
 		}
 
 		specialization := query.GetConditionValue("specialization")
@@ -46,6 +48,11 @@ func (r *doctorProfileRepository) FindAllDoctors(ctx context.Context, query *val
 		if name != nil {
 			db.Where("\"Profile\".name ILIKE ?", name)
 		}
+
+		minRating := query.GetConditionValue("min_rating")
+		if minRating != nil {
+			db.Where("rating_average >= ?", minRating)
+		}
 		return db
 	})
 }
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type DoctorReviewRepository interface {
+	BaseRepository[entity.DoctorReview]
+	FindAllReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	FindRatingSummary(ctx context.Context, doctorId uint) (decimal.Decimal, int, error)
+}
+
+type doctorReviewRepository struct {
+	*baseRepository[entity.DoctorReview]
+	db *gorm.DB
+}
+
+func NewDoctorReviewRepository(db *gorm.DB) DoctorReviewRepository {
+	return &doctorReviewRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.DoctorReview]{db: db},
+	}
+}
+
+func (r *doctorReviewRepository) FindAllReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return r.paginate(ctx, query, func(db *gorm.DB) *gorm.DB {
+		db.Joins("Profile")
+		doctorId := query.GetConditionValue("doctor_id")
+		if doctorId != nil {
+			db.Where("\"doctor_reviews\".doctor_id = ?", doctorId)
+		}
+		rating := query.GetConditionValue("rating")
+		if rating != nil {
+			db.Where("\"doctor_reviews\".rating = ?", rating)
+		}
+		isHidden := query.GetConditionValue("is_hidden")
+		if isHidden != nil {
+			db.Where("\"doctor_reviews\".is_hidden = ?", isHidden)
+		}
+		return db
+	})
+}
+
+func (r *doctorReviewRepository) FindRatingSummary(ctx context.Context, doctorId uint) (decimal.Decimal, int, error) {
+	var summary struct {
+		Average decimal.NullDecimal
+		Count   int
+	}
+	err := r.conn(ctx).
+		Model(&entity.DoctorReview{}).
+		Select("AVG(rating) AS average, COUNT(*) AS count").
+		Where("doctor_id = ?", doctorId).
+		Where("is_hidden = ?", false).
+		Scan(&summary).
+		Error
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+	return summary.Average.Decimal.Round(2), summary.Count, nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type InvoiceRepository interface {
+	BaseRepository[entity.Invoice]
+}
+
+type invoiceRepository struct {
+	*baseRepository[entity.Invoice]
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.Invoice]{db: db},
+	}
+}
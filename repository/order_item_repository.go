@@ -14,7 +14,7 @@ type OrderItemRepository interface {
 	BulkCreate(context.Context, []*entity.OrderItem) error
 	MonthlyReport(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
 	MonthlyReportAdminPharmacy(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
-	ListOfOrderItem(ctx context.Context, orderId uint, userId uint) ([]*entity.OrderItem, error)
+	ListOfOrderItem(ctx context.Context, shipmentId uint) ([]*entity.OrderItem, error)
 }
 
 type orderItemRepository struct {
@@ -108,6 +108,7 @@ func (r *orderItemRepository) MonthlyReportAdminPharmacy(ctx context.Context, qu
 	pharmacyGraph := []*dto.MonthlySalesReport{}
 	productGraph := []*dto.MonthlySalesReport{}
 	productCategoryGraph := []*dto.MonthlySalesReport{}
+	adminPharmacies := staffPharmacyIds(r.db, ctx.Value("user_id").(uint), entity.PermissionOrderReport)
 	err := r.db.WithContext(ctx).Raw(`select ph.name as pharmacy_name,sum(oi.quantity) as total_item,
 	sum(po.total_payment) as total_sales,
 	TO_CHAR(
@@ -120,8 +121,8 @@ from order_items as oi
   JOIN product_orders as po ON po.id = oi.order_id
   JOIN public.product_categories pc on pc.id = p.product_category_id
 WHERE ph.id = ?
-AND ph.admin_id = ?
-group by month, ph.name`, pharmacy, ctx.Value("user_id").(uint)).Scan(&pharmacyGraph).Error
+AND ph.id IN (?)
+group by month, ph.name`, pharmacy, adminPharmacies).Scan(&pharmacyGraph).Error
 	if err != nil {
 		return nil, err
 	}
@@ -138,8 +139,8 @@ from order_items as oi
 	  JOIN public.product_categories pc on pc.id = p.product_category_id
 WHERE ph.id = ?
 AND pp.id = ?
-AND ph.admin_id = ?
-group by month, p.name`, pharmacy, product, ctx.Value("user_id").(uint)).Scan(&productGraph).Error
+AND ph.id IN (?)
+group by month, p.name`, pharmacy, product, adminPharmacies).Scan(&productGraph).Error
 	if err != nil {
 		return nil, err
 	}
@@ -156,8 +157,8 @@ from order_items as oi
 	  JOIN public.product_categories pc on pc.id = p.product_category_id
 WHERE ph.id = ?
 AND  pc.id = ?
-AND ph.admin_id = ?
-group by month, pc.id`, pharmacy, productCategory, ctx.Value("user_id").(uint)).Scan(&productCategoryGraph).Error
+AND ph.id IN (?)
+group by month, pc.id`, pharmacy, productCategory, adminPharmacies).Scan(&productCategoryGraph).Error
 	if err != nil {
 		return nil, err
 	}
@@ -167,15 +168,11 @@ group by month, pc.id`, pharmacy, productCategory, ctx.Value("user_id").(uint)).
 	return &valueobject.PagedResult{Data: result}, nil
 }
 
-func (r *orderItemRepository) ListOfOrderItem(ctx context.Context, orderId uint, userId uint) ([]*entity.OrderItem, error) {
+func (r *orderItemRepository) ListOfOrderItem(ctx context.Context, shipmentId uint) ([]*entity.OrderItem, error) {
 	var orderItems []*entity.OrderItem
 	err := r.conn(ctx).
 		Model(&entity.OrderItem{}).
-		Joins("JOIN pharmacy_products ON pharmacy_products.id = order_items.pharmacy_product_id ").
-		Joins("JOIN pharmacies ON pharmacies.id = pharmacy_products.pharmacy_id").
-		Joins("JOIN product_orders ON product_orders.id = order_items.order_id").
-		Where("order_id = ?", orderId).
-		Where("pharmacies.admin_id = ?", userId).
+		Where("shipment_id = ?", shipmentId).
 		Preload("PharmacyProduct").
 		Preload("PharmacyProduct.Pharmacy").
 		Find(&orderItems).Error
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type OrderPrescriptionRepository interface {
+	BaseRepository[entity.OrderPrescription]
+}
+
+type orderPrescriptionRepository struct {
+	*baseRepository[entity.OrderPrescription]
+	db *gorm.DB
+}
+
+func NewOrderPrescriptionRepository(db *gorm.DB) OrderPrescriptionRepository {
+	return &orderPrescriptionRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.OrderPrescription]{db: db},
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderShipmentRepository interface {
+	BaseRepository[entity.OrderShipment]
+	FindAdminShipments(ctx context.Context, orderId, shipmentId, userId uint, permission string) ([]*entity.OrderShipment, error)
+}
+
+type orderShipmentRepository struct {
+	*baseRepository[entity.OrderShipment]
+	db *gorm.DB
+}
+
+func NewOrderShipmentRepository(db *gorm.DB) OrderShipmentRepository {
+	return &orderShipmentRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.OrderShipment]{db: db},
+	}
+}
+
+func (r *orderShipmentRepository) FindAdminShipments(ctx context.Context, orderId, shipmentId, userId uint, permission string) ([]*entity.OrderShipment, error) {
+	var shipments []*entity.OrderShipment
+	query := r.conn(ctx).
+		Model(&entity.OrderShipment{}).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "order_shipments"}}).
+		Where("order_shipments.order_id = ?", orderId).
+		Where("order_shipments.pharmacy_id IN (?)", staffPharmacyIds(r.db, userId, permission))
+	if shipmentId != 0 {
+		query = query.Where("order_shipments.id = ?", shipmentId)
+	}
+	err := query.Order("order_shipments.id").Find(&shipments).Error
+	if err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
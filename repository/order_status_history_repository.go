@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistoryRepository interface {
+	BaseRepository[entity.OrderStatusHistory]
+}
+
+type orderStatusHistoryRepository struct {
+	*baseRepository[entity.OrderStatusHistory]
+	db *gorm.DB
+}
+
+func NewOrderStatusHistoryRepository(db *gorm.DB) OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.OrderStatusHistory]{db: db},
+	}
+}
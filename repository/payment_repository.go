@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	BaseRepository[entity.Payment]
+}
+
+type paymentRepository struct {
+	*baseRepository[entity.Payment]
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.Payment]{db: db},
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type PermissionRepository interface {
+	BaseRepository[entity.Permission]
+}
+
+type permissionRepository struct {
+	*baseRepository[entity.Permission]
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.Permission]{db: db},
+	}
+}
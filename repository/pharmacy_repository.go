@@ -37,7 +37,7 @@ func (r *pharmacyRepository) FindAllPharmacy(ctx context.Context, query *valueob
 		case "id":
 			query.WithSortBy("\"pharmacies\".id ")
 		}
-		db.Where("\"pharmacies\".admin_id = ?", ctx.Value("user_id").(uint))
+		db.Where("\"pharmacies\".id IN (?)", staffPharmacyIds(r.db, ctx.Value("user_id").(uint), entity.PermissionPharmacyRead))
 		name := query.GetConditionValue("name")
 		db.Joins("City").Joins("Province")
 
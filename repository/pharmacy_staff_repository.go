@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"gorm.io/gorm"
+)
+
+type PharmacyStaffRepository interface {
+	BaseRepository[entity.PharmacyStaff]
+	FindMember(ctx context.Context, pharmacyId, userId uint) (*entity.PharmacyStaff, error)
+	DeleteByPharmacyId(ctx context.Context, pharmacyId uint) error
+}
+
+type pharmacyStaffRepository struct {
+	*baseRepository[entity.PharmacyStaff]
+	db *gorm.DB
+}
+
+func NewPharmacyStaffRepository(db *gorm.DB) PharmacyStaffRepository {
+	return &pharmacyStaffRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.PharmacyStaff]{db: db},
+	}
+}
+
+func (r *pharmacyStaffRepository) FindMember(ctx context.Context, pharmacyId, userId uint) (*entity.PharmacyStaff, error) {
+	return r.FindOne(ctx, valueobject.NewQuery().
+		Condition("pharmacy_id", valueobject.Equal, pharmacyId).
+		Condition("user_id", valueobject.Equal, userId))
+}
+
+func (r *pharmacyStaffRepository) DeleteByPharmacyId(ctx context.Context, pharmacyId uint) error {
+	return r.conn(ctx).Where("pharmacy_id = ?", pharmacyId).Delete(&entity.PharmacyStaff{}).Error
+}
+
+func staffPharmacyIds(db *gorm.DB, userId uint, permission string) *gorm.DB {
+	return db.Model(&entity.PharmacyStaff{}).
+		Select("pharmacy_id").
+		Where("user_id = ?", userId).
+		Where("role IN ?", entity.PharmacyStaffRolesWith(permission))
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type PrescriptionItemRepository interface {
+	BaseRepository[entity.PrescriptionItem]
+}
+
+type prescriptionItemRepository struct {
+	*baseRepository[entity.PrescriptionItem]
+	db *gorm.DB
+}
+
+func NewPrescriptionItemRepository(db *gorm.DB) PrescriptionItemRepository {
+	return &prescriptionItemRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.PrescriptionItem]{db: db},
+	}
+}
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"strings"
-	"time"
 
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/valueobject"
@@ -12,8 +11,6 @@ import (
 
 type ProductOrderRepository interface {
 	BaseRepository[entity.ProductOrder]
-	CancelOrder(context.Context) error
-	ConfirmOrder(context.Context) error
 	FindAllOrders(context.Context, *valueobject.Query, uint, entity.RoleId) (*valueobject.PagedResult, error)
 	FindOrderDetail(context.Context, uint, uint, entity.RoleId) (*entity.ProductOrder, error)
 }
@@ -30,29 +27,6 @@ func NewProductOrderRepository(db *gorm.DB) ProductOrderRepository {
 	}
 }
 
-func (r *productOrderRepository) CancelOrder(ctx context.Context) error {
-	err := r.conn(ctx).
-		Model(&entity.ProductOrder{}).
-		Where("expired_at < ?", time.Now()).
-		Update("order_status_id", 6).Error
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (r *productOrderRepository) ConfirmOrder(ctx context.Context) error {
-	err := r.conn(ctx).
-		Model(&entity.ProductOrder{}).
-		Where("ordered_at < ?", time.Now().Add(time.Hour*-7*24)).
-		Where("order_status_id = ?", 4).
-		Update("order_status_id", 5).Error
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 func (r *productOrderRepository) FindAllOrders(ctx context.Context, query *valueobject.Query, userId uint, roleId entity.RoleId) (*valueobject.PagedResult, error) {
 	return r.paginate(ctx, query, func(db *gorm.DB) *gorm.DB {
 		orderStatus := query.GetConditionValue("order_status")
@@ -68,19 +42,28 @@ func (r *productOrderRepository) FindAllOrders(ctx context.Context, query *value
 			Joins("LEFT JOIN products ON pharmacy_products.product_id = products.id").
 			Joins("LEFT JOIN order_statuses ON order_statuses.id = product_orders.order_status_id")
 
-		if orderStatus != nil {
-			db.Where("order_status_id = ?", orderStatus)
-		}
-
 		switch roleId {
 		case entity.RoleUser:
 			db.Where("profile_id = ?", userId)
 			if name != nil {
 				db.Where("products.name ILIKE ? ", name)
 			}
+			if orderStatus != nil {
+				db.Where("product_orders.order_status_id = ?", orderStatus)
+			}
 		case entity.RoleAdmin:
-			db.Joins("LEFT JOIN pharmacies ON pharmacies.id = pharmacy_products.pharmacy_id").
-				Where("pharmacies.admin_id = ?", userId)
+			adminPharmacies := staffPharmacyIds(r.db, userId, entity.PermissionOrderRead)
+			db.Joins("JOIN order_shipments ON order_shipments.id = order_items.shipment_id").
+				Where("order_shipments.pharmacy_id IN (?)", adminPharmacies)
+			if orderStatus != nil {
+				db.Where("order_shipments.order_status_id = ?", orderStatus)
+			}
+			db.Preload("Shipments", "pharmacy_id IN (?)", adminPharmacies).
+				Preload("OrderItems", "shipment_id IN (?)", r.db.Model(&entity.OrderShipment{}).Select("id").Where("pharmacy_id IN (?)", adminPharmacies))
+		default:
+			if orderStatus != nil {
+				db.Where("product_orders.order_status_id = ?", orderStatus)
+			}
 		}
 		if roleId != entity.RoleUser {
 			db.Joins("LEFT JOIN profiles ON profiles.user_id = product_orders.profile_id")
@@ -92,6 +75,8 @@ func (r *productOrderRepository) FindAllOrders(ctx context.Context, query *value
 		db.Group("product_orders.id")
 		db.Preload("OrderItems.PharmacyProduct.Product")
 		db.Preload("OrderStatus")
+		db.Preload("Shipments.Pharmacy")
+		db.Preload("Shipments.OrderStatus")
 		return db
 	})
 }
@@ -100,21 +85,23 @@ func (r *productOrderRepository) FindOrderDetail(ctx context.Context, orderId, u
 	var order entity.ProductOrder
 	query := r.conn(ctx).
 		Model(&entity.ProductOrder{}).
-		Joins("LEFT JOIN order_items ON order_items.order_id = product_orders.id").
-		Joins("LEFT JOIN pharmacy_products ON pharmacy_products.id = order_items.pharmacy_product_id").
-		Joins("LEFT JOIN pharmacies ON pharmacies.id = pharmacy_products.pharmacy_id").
-		Joins("LEFT JOIN admin_contacts ON admin_contacts.user_id = pharmacies.admin_id").
-		Where("order_id = ? ", orderId).
-		Preload("OrderItems.PharmacyProduct.Pharmacy").
+		Joins("LEFT JOIN order_shipments ON order_shipments.order_id = product_orders.id").
+		Joins("LEFT JOIN pharmacies ON pharmacies.id = order_shipments.pharmacy_id").
+		Where("product_orders.id = ? ", orderId).
 		Preload("OrderStatus")
 	switch roleId {
 	case entity.RoleUser:
-		query = query.Where("profile_id = ?", userId)
+		query = query.Where("profile_id = ?", userId).
+			Preload("Shipments.Pharmacy")
 	case entity.RoleAdmin:
-		query = query.Where("pharmacies.admin_id = ?", userId)
+		adminPharmacies := staffPharmacyIds(r.db, userId, entity.PermissionOrderRead)
+		query = query.Where("pharmacies.id IN (?)", adminPharmacies).
+			Preload("Shipments", "pharmacy_id IN (?)", adminPharmacies).
+			Preload("Shipments.Pharmacy")
 	case entity.RoleSuperAdmin:
-		query = query.Preload("OrderItems.PharmacyProduct.Pharmacy.Admin.AdminContact.User")
+		query = query.Preload("Shipments.Pharmacy.Admin.AdminContact.User")
 	}
+	query = query.Preload("Shipments.OrderStatus").Preload("Prescription")
 	err := query.Find(&order).Error
 	if err != nil {
 		return nil, err
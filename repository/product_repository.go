@@ -33,11 +33,14 @@ func (r *productRepository) FindAllProducts(ctx context.Context, query *valueobj
 		switch strings.Split(query.GetOrder(), " ")[0] {
 		case "price":
 			query.WithSortBy("price")
+		case "rating":
+			query.WithSortBy("\"products\".rating_average")
 		}
 
 		category := query.GetConditionValue("category")
 		name := query.GetConditionValue("name")
 		isHidden := query.GetConditionValue("is_hidden")
+		minRating := query.GetConditionValue("min_rating")
 		db.Joins("ProductCategory")
 
 		if category != nil {
@@ -51,6 +54,10 @@ func (r *productRepository) FindAllProducts(ctx context.Context, query *valueobj
 		if isHidden != nil {
 			db.Where("products.is_hidden = ?", isHidden)
 		}
+
+		if minRating != nil {
+			db.Where("products.rating_average >= ?", minRating)
+		}
 		return db
 	})
 }
@@ -61,10 +68,12 @@ func (r *productRepository) FindNearbyProducts(ctx context.Context, query *value
 		query.WithSortBy("\"products\".name")
 	case "price":
 		query.WithSortBy("\"products\".price")
+	case "rating":
+		query.WithSortBy("\"products\".rating_average")
 	}
 	pagedResult, err := r.paginate(ctx, query, func(db *gorm.DB) *gorm.DB {
 		db.
-			Select("\"products\".name as name", "\"products\".id as id", "\"products\".image as image", "\"products\".selling_unit as selling_unit").
+			Select("\"products\".name as name", "\"products\".id as id", "\"products\".image as image", "\"products\".selling_unit as selling_unit", "\"products\".rating_average as rating_average", "\"products\".review_count as review_count").
 			Joins("JOIN pharmacy_products pp on \"products\".id=pp.product_id").
 			Joins("JOIN pharmacies ph ON ph.id=pp.pharmacy_id").
 			Joins(fmt.Sprintf("JOIN addresses a on st_dwithin(ph.location, a.location, %d)", distanceInMeter)).
@@ -74,6 +83,7 @@ func (r *productRepository) FindNearbyProducts(ctx context.Context, query *value
 
 		category := query.GetConditionValue("category")
 		name := query.GetConditionValue("name")
+		minRating := query.GetConditionValue("min_rating")
 
 		if category != nil {
 			db.Where("product_category_id", category)
@@ -83,6 +93,10 @@ func (r *productRepository) FindNearbyProducts(ctx context.Context, query *value
 			db.Where("\"products\".name ILIKE ?", name)
 		}
 
+		if minRating != nil {
+			db.Where("\"products\".rating_average >= ?", minRating)
+		}
+
 		db.Group("\"products\".id")
 
 		return db
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type ProductReviewPhotoRepository interface {
+	BaseRepository[entity.ProductReviewPhoto]
+}
+
+type productReviewPhotoRepository struct {
+	*baseRepository[entity.ProductReviewPhoto]
+	db *gorm.DB
+}
+
+func NewProductReviewPhotoRepository(db *gorm.DB) ProductReviewPhotoRepository {
+	return &productReviewPhotoRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.ProductReviewPhoto]{db: db},
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type ProductReviewRepository interface {
+	BaseRepository[entity.ProductReview]
+	FindAllReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	FindRatingSummary(ctx context.Context, productId uint) (decimal.Decimal, int, error)
+}
+
+type productReviewRepository struct {
+	*baseRepository[entity.ProductReview]
+	db *gorm.DB
+}
+
+func NewProductReviewRepository(db *gorm.DB) ProductReviewRepository {
+	return &productReviewRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.ProductReview]{db: db},
+	}
+}
+
+func (r *productReviewRepository) FindAllReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return r.paginate(ctx, query, func(db *gorm.DB) *gorm.DB {
+		db.Joins("Profile").Preload("Photos")
+		productId := query.GetConditionValue("product_id")
+		if productId != nil {
+			db.Where("\"product_reviews\".product_id = ?", productId)
+		}
+		rating := query.GetConditionValue("rating")
+		if rating != nil {
+			db.Where("\"product_reviews\".rating = ?", rating)
+		}
+		isHidden := query.GetConditionValue("is_hidden")
+		if isHidden != nil {
+			db.Where("\"product_reviews\".is_hidden = ?", isHidden)
+		}
+		return db
+	})
+}
+
+func (r *productReviewRepository) FindRatingSummary(ctx context.Context, productId uint) (decimal.Decimal, int, error) {
+	var summary struct {
+		Average decimal.NullDecimal
+		Count   int
+	}
+	err := r.conn(ctx).
+		Model(&entity.ProductReview{}).
+		Select("AVG(rating) AS average, COUNT(*) AS count").
+		Where("product_id = ?", productId).
+		Where("is_hidden = ?", false).
+		Scan(&summary).
+		Error
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+	return summary.Average.Decimal.Round(2), summary.Count, nil
+}
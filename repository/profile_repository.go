@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/night1010/everhealth/entity"
 	"gorm.io/gorm"
 )
 
 type ProfileRepository interface {
 	BaseRepository[entity.Profile]
+	UpdateLastSeen(ctx context.Context, userId uint, lastSeenAt time.Time) error
 }
 
 type profileRepository struct {
@@ -20,3 +24,7 @@ func NewProfileRepository(db *gorm.DB) ProfileRepository {
 		baseRepository: &baseRepository[entity.Profile]{db: db},
 	}
 }
+
+func (r *profileRepository) UpdateLastSeen(ctx context.Context, userId uint, lastSeenAt time.Time) error {
+	return r.conn(ctx).Model(&entity.Profile{}).Where("user_id = ?", userId).UpdateColumn("last_seen_at", lastSeenAt).Error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	BaseRepository[entity.RefreshToken]
+	RevokeAllByUserId(ctx context.Context, userId uint) error
+}
+
+type refreshTokenRepository struct {
+	*baseRepository[entity.RefreshToken]
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.RefreshToken]{db: db},
+	}
+}
+
+func (r *refreshTokenRepository) RevokeAllByUserId(ctx context.Context, userId uint) error {
+	return r.conn(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", time.Now()).
+		Error
+}
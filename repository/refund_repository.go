@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type RefundRepository interface {
+	BaseRepository[entity.Refund]
+	FindAllRefunds(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	SumAmountByShipment(ctx context.Context, shipmentId uint) (decimal.Decimal, error)
+	SumPaidAmountByPayment(ctx context.Context, paymentId uint) (decimal.Decimal, error)
+	FindRefundedQuantity(ctx context.Context, orderItemIds []uint) (map[uint]int, error)
+}
+
+type refundRepository struct {
+	*baseRepository[entity.Refund]
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.Refund]{db: db},
+	}
+}
+
+func (r *refundRepository) FindAllRefunds(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return r.paginate(ctx, query, func(db *gorm.DB) *gorm.DB {
+		db.Preload("RefundItems.OrderItem.PharmacyProduct.Product").
+			Preload("Shipment.Pharmacy")
+		switch ctx.Value("role_id").(entity.RoleId) {
+		case entity.RoleUser:
+			db.Where("\"refunds\".profile_id = ?", ctx.Value("user_id").(uint))
+		case entity.RoleAdmin:
+			db.Joins("JOIN order_shipments ON order_shipments.id = refunds.shipment_id").
+				Where("order_shipments.pharmacy_id IN (?)", staffPharmacyIds(r.db, ctx.Value("user_id").(uint), entity.PermissionRefundRead))
+		}
+		status := query.GetConditionValue("status")
+		if status != nil {
+			db.Where("\"refunds\".status = ?", status)
+		}
+		paymentType := query.GetConditionValue("payment_type")
+		if paymentType != nil {
+			db.Where("\"refunds\".payment_type = ?", paymentType)
+		}
+		return db
+	})
+}
+
+func (r *refundRepository) SumAmountByShipment(ctx context.Context, shipmentId uint) (decimal.Decimal, error) {
+	var total decimal.NullDecimal
+	err := r.conn(ctx).
+		Model(&entity.Refund{}).
+		Select("SUM(amount)").
+		Where("shipment_id = ?", shipmentId).
+		Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return total.Decimal, nil
+}
+
+func (r *refundRepository) SumPaidAmountByPayment(ctx context.Context, paymentId uint) (decimal.Decimal, error) {
+	var total decimal.NullDecimal
+	err := r.conn(ctx).
+		Model(&entity.Refund{}).
+		Select("SUM(amount)").
+		Where("payment_id = ?", paymentId).
+		Where("status = ?", entity.RefundPaid).
+		Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return total.Decimal, nil
+}
+
+func (r *refundRepository) FindRefundedQuantity(ctx context.Context, orderItemIds []uint) (map[uint]int, error) {
+	type Result struct {
+		OrderItemId uint
+		Quantity    int
+	}
+	var results []Result
+	err := r.conn(ctx).
+		Model(&entity.RefundItem{}).
+		Select("order_item_id, SUM(quantity) AS quantity").
+		Where("order_item_id IN ?", orderItemIds).
+		Group("order_item_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	refunded := make(map[uint]int)
+	for _, result := range results {
+		refunded[result.OrderItemId] = result.Quantity
+	}
+	return refunded, nil
+}
@@ -1,12 +1,20 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
 	"gorm.io/gorm"
 )
 
+const BaseRoleIdColumn = "COALESCE(NULLIF(\"Role\".base_role_id, 0), \"Role\".id)"
+
 type RoleRepository interface {
 	BaseRepository[entity.Role]
+	FindRoleWithPermissions(ctx context.Context, roleId entity.RoleId) (*entity.Role, error)
+	ReplacePermissions(ctx context.Context, role *entity.Role, permissions []*entity.Permission) error
+	CountMembers(ctx context.Context, roleId entity.RoleId) (int64, error)
 }
 
 type roleRepository struct {
@@ -20,3 +28,22 @@ func NewRoleRepository(db *gorm.DB) RoleRepository {
 		baseRepository: &baseRepository[entity.Role]{db: db},
 	}
 }
+
+func (r *roleRepository) FindRoleWithPermissions(ctx context.Context, roleId entity.RoleId) (*entity.Role, error) {
+	return r.FindOne(ctx, valueobject.NewQuery().
+		Condition("id", valueobject.Equal, roleId).
+		WithPreload("Permissions"))
+}
+
+func (r *roleRepository) ReplacePermissions(ctx context.Context, role *entity.Role, permissions []*entity.Permission) error {
+	return r.conn(ctx).Model(role).Association("Permissions").Replace(permissions)
+}
+
+func (r *roleRepository) CountMembers(ctx context.Context, roleId entity.RoleId) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Model(&entity.User{}).Where("role_id = ?", roleId).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -39,8 +39,8 @@ func (r *stockMutationRepository) FindAllStockMutation(ctx context.Context, quer
 
 		status := query.GetConditionValue("status")
 		name := query.GetConditionValue("pharmacy_name")
-		adminId := ctx.Value("user_id").(uint)
-		db.Where("\"ToPharmacyProduct__Pharmacy\".admin_id = ? OR \"FromPharmacyProduct__Pharmacy\".admin_id = ?", adminId, adminId)
+		adminPharmacies := staffPharmacyIds(r.db, ctx.Value("user_id").(uint), entity.PermissionStockMutationRead)
+		db.Where("(\"ToPharmacyProduct\".pharmacy_id IN (?) OR \"FromPharmacyProduct\".pharmacy_id IN (?))", adminPharmacies, adminPharmacies)
 		if status != nil {
 			db.Where("\"stock_mutations\".status = ?", status)
 		}
@@ -44,7 +44,7 @@ func (r *stockRecordRepository) FindAllStockRecord(ctx context.Context, query *v
 		isReduction := query.GetConditionValue("is_reduction")
 		name := query.GetConditionValue("name")
 		ProductId := query.GetConditionValue("PharmacyProductId")
-		db.Where("\"PharmacyProduct__Pharmacy\".admin_id =?", ctx.Value("user_id").(uint))
+		db.Where("\"PharmacyProduct\".pharmacy_id IN (?)", staffPharmacyIds(r.db, ctx.Value("user_id").(uint), entity.PermissionStockRecordRead))
 		if isReduction != nil {
 			db.Where("\"stock_records\".is_reduction = ?", isReduction)
 		}
@@ -92,7 +92,8 @@ JOIN pharmacies as ph on pp.pharmacy_id = ph.id`
 	JOIN pharmacy_products as pp ON pp.id = calc.pharmacy_product_id
 	JOIN public.products p on p.id = pp.product_id
 	JOIN pharmacies as ph on pp.pharmacy_id = ph.id`
-	where := fmt.Sprintf(" Where ph.admin_id = %v", ctx.Value("user_id").(uint))
+	where := " Where ph.id IN (?)"
+	adminPharmacies := staffPharmacyIds(r.db, ctx.Value("user_id").(uint), entity.PermissionStockRecordRead)
 	name := query.GetConditionValue("product_name")
 	month := query.GetConditionValue("month")
 	sortBy := query.GetOrder()
@@ -125,11 +126,11 @@ JOIN pharmacies as ph on pp.pharmacy_id = ph.id`
 	if limit != nil {
 		newQuery = newQuery + fmt.Sprintf(" Limit %v", *limit)
 	}
-	err := r.db.WithContext(ctx).Raw(newQuery).Scan(&result).Error
+	err := r.db.WithContext(ctx).Raw(newQuery, adminPharmacies).Scan(&result).Error
 	if err != nil {
 		return nil, err
 	}
-	err = r.db.WithContext(ctx).Raw(rawQuery).Scan(&totalItem).Error
+	err = r.db.WithContext(ctx).Raw(rawQuery, adminPharmacies).Scan(&totalItem).Error
 	if err != nil {
 		return nil, err
 	}
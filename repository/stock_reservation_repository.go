@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type StockReservationRepository interface {
+	BaseRepository[entity.StockReservation]
+	BulkCreate(ctx context.Context, reservations []*entity.StockReservation) error
+	FindHeldQuantity(ctx context.Context, pharmacyProductIds []uint) (map[uint]int, error)
+	UpdateStatusByShipment(ctx context.Context, shipmentId uint, from, to entity.StockReservationStatus) error
+	KeepByShipment(ctx context.Context, shipmentId uint) error
+}
+
+type stockReservationRepository struct {
+	*baseRepository[entity.StockReservation]
+	db *gorm.DB
+}
+
+func NewStockReservationRepository(db *gorm.DB) StockReservationRepository {
+	return &stockReservationRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.StockReservation]{db: db},
+	}
+}
+
+func (r *stockReservationRepository) BulkCreate(ctx context.Context, reservations []*entity.StockReservation) error {
+	return r.conn(ctx).Model(&entity.StockReservation{}).Create(reservations).Error
+}
+
+func (r *stockReservationRepository) FindHeldQuantity(ctx context.Context, pharmacyProductIds []uint) (map[uint]int, error) {
+	type Result struct {
+		PharmacyProductId uint
+		Quantity          int
+	}
+	var results []Result
+	err := r.conn(ctx).
+		Model(&entity.StockReservation{}).
+		Select("pharmacy_product_id, SUM(quantity) AS quantity").
+		Where("pharmacy_product_id IN ?", pharmacyProductIds).
+		Where("status = ?", entity.ReservationHeld).
+		Where("expired_at IS NULL OR expired_at > ?", time.Now()).
+		Group("pharmacy_product_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[uint]int)
+	for _, result := range results {
+		held[result.PharmacyProductId] = result.Quantity
+	}
+	return held, nil
+}
+
+func (r *stockReservationRepository) UpdateStatusByShipment(ctx context.Context, shipmentId uint, from, to entity.StockReservationStatus) error {
+	return r.conn(ctx).
+		Model(&entity.StockReservation{}).
+		Where("shipment_id = ?", shipmentId).
+		Where("status = ?", from).
+		Update("status", to).Error
+}
+
+func (r *stockReservationRepository) KeepByShipment(ctx context.Context, shipmentId uint) error {
+	return r.conn(ctx).
+		Model(&entity.StockReservation{}).
+		Where("shipment_id = ?", shipmentId).
+		Where("status = ?", entity.ReservationHeld).
+		Update("expired_at", nil).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type TelemedicineQueueRepository interface {
+	BaseRepository[entity.TelemedicineQueue]
+	CountWaitingBefore(ctx context.Context, queue *entity.TelemedicineQueue) (int64, error)
+}
+
+type telemedicineQueueRepository struct {
+	*baseRepository[entity.TelemedicineQueue]
+	db *gorm.DB
+}
+
+func NewTelemedicineQueueRepository(db *gorm.DB) TelemedicineQueueRepository {
+	return &telemedicineQueueRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.TelemedicineQueue]{db: db},
+	}
+}
+
+func (r *telemedicineQueueRepository) CountWaitingBefore(ctx context.Context, queue *entity.TelemedicineQueue) (int64, error) {
+	var count int64
+	err := r.conn(ctx).
+		Model(&entity.TelemedicineQueue{}).
+		Where("doctor_id = ?", queue.DoctorId).
+		Where("status = ?", entity.QueueWaiting).
+		Where("id < ?", queue.Id).
+		Count(&count).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
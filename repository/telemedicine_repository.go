@@ -33,7 +33,7 @@ func NewTelemedicineRepository(db *gorm.DB) TelemedicineRepository {
 
 func (r *telemedicineRepository) FindTelemedicineWhereStatusNotCancelAndEnd(ctx context.Context, telemedicine *entity.Telemedicine) (*entity.Telemedicine, error) {
 	var newTelemedicine *entity.Telemedicine
-	err := r.conn(ctx).Where("profile_id =?", telemedicine.ProfileId).Where("status not in (?)", []entity.TelemedicineStatus{entity.Cancel, entity.End}).First(&newTelemedicine).Error
+	err := r.conn(ctx).Where("profile_id =?", telemedicine.ProfileId).Where("status not in (?)", []entity.TelemedicineStatus{entity.Cancel, entity.End}).Where("(scheduled_at IS NULL OR status = ?)", entity.Ongoing).First(&newTelemedicine).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
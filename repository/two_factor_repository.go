@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type UserTwoFactorRepository interface {
+	BaseRepository[entity.UserTwoFactor]
+}
+
+type userTwoFactorRepository struct {
+	*baseRepository[entity.UserTwoFactor]
+	db *gorm.DB
+}
+
+func NewUserTwoFactorRepository(db *gorm.DB) UserTwoFactorRepository {
+	return &userTwoFactorRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.UserTwoFactor]{db: db},
+	}
+}
+
+type TwoFactorRecoveryCodeRepository interface {
+	BaseRepository[entity.TwoFactorRecoveryCode]
+	DeleteByUserId(ctx context.Context, userId uint) error
+}
+
+type twoFactorRecoveryCodeRepository struct {
+	*baseRepository[entity.TwoFactorRecoveryCode]
+	db *gorm.DB
+}
+
+func NewTwoFactorRecoveryCodeRepository(db *gorm.DB) TwoFactorRecoveryCodeRepository {
+	return &twoFactorRecoveryCodeRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.TwoFactorRecoveryCode]{db: db},
+	}
+}
+
+func (r *twoFactorRecoveryCodeRepository) DeleteByUserId(ctx context.Context, userId uint) error {
+	return r.conn(ctx).Where("user_id = ?", userId).Delete(&entity.TwoFactorRecoveryCode{}).Error
+}
@@ -14,6 +14,7 @@ type UserRepository interface {
 	FindAllAdminPharmacy(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
 	HardDelete(ctx context.Context, user *entity.User) error
 	FindAllUser(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	FindTokenVersion(ctx context.Context, userId uint) (int, error)
 }
 
 type userRepository struct {
@@ -36,7 +37,7 @@ func (r *userRepository) FindAllAdminPharmacy(ctx context.Context, query *valueo
 		case "id":
 			query.WithSortBy("\"users\".id ")
 		}
-		db.Where("role_id", entity.RoleAdmin).Preload("AdminContact")
+		db.Joins("Role").Where(BaseRoleIdColumn+" = ?", entity.RoleAdmin).Preload("AdminContact")
 		name := query.GetConditionValue("email")
 		if name != nil {
 			db.Where("\"users\".email ILIKE ?", name)
@@ -61,10 +62,9 @@ func (r *userRepository) FindAllUser(ctx context.Context, query *valueobject.Que
 			db.Where("\"users\".email ILIKE ?", name)
 		}
 		if roleId != nil {
-			db.Where("\"users\".role_id = ?", roleId)
-
+			db.Where("\"users\".role_id = ? OR "+BaseRoleIdColumn+" = ?", roleId, roleId)
 		} else {
-			db.Where("\"users\".role_id IN ?", []entity.RoleId{entity.RoleAdmin, entity.RoleDoctor, entity.RoleUser})
+			db.Where(BaseRoleIdColumn+" IN ?", []entity.RoleId{entity.RoleAdmin, entity.RoleDoctor, entity.RoleUser})
 		}
 		db.Preload("AdminContact").Preload("Profile")
 		if isVerified != nil {
@@ -81,3 +81,12 @@ func (r *userRepository) HardDelete(ctx context.Context, user *entity.User) erro
 	}
 	return nil
 }
+
+func (r *userRepository) FindTokenVersion(ctx context.Context, userId uint) (int, error) {
+	var user entity.User
+	err := r.conn(ctx).Select("token_version").Where("id = ?", userId).Take(&user).Error
+	if err != nil {
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
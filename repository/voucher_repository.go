@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+	"gorm.io/gorm"
+)
+
+type VoucherRepository interface {
+	BaseRepository[entity.Voucher]
+	FindAllVouchers(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+}
+
+type voucherRepository struct {
+	*baseRepository[entity.Voucher]
+	db *gorm.DB
+}
+
+func NewVoucherRepository(db *gorm.DB) VoucherRepository {
+	return &voucherRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.Voucher]{db: db},
+	}
+}
+
+func (r *voucherRepository) FindAllVouchers(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return r.paginate(ctx, query, func(db *gorm.DB) *gorm.DB {
+		db.Preload("ProductCategory")
+		name := query.GetConditionValue("name")
+		if name != nil {
+			db.Where("(name ILIKE ? OR code ILIKE ?)", name, name)
+		}
+		voucherType := query.GetConditionValue("type")
+		if voucherType != nil {
+			db.Where("type = ?", voucherType)
+		}
+		isActive := query.GetConditionValue("is_active")
+		if isActive != nil {
+			db.Where("is_active = ?", isActive)
+		}
+		return db
+	})
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/night1010/everhealth/entity"
+	"gorm.io/gorm"
+)
+
+type VoucherUsageRepository interface {
+	BaseRepository[entity.VoucherUsage]
+	CountByProfile(ctx context.Context, voucherId uint, profileId uint) (int, error)
+}
+
+type voucherUsageRepository struct {
+	*baseRepository[entity.VoucherUsage]
+	db *gorm.DB
+}
+
+func NewVoucherUsageRepository(db *gorm.DB) VoucherUsageRepository {
+	return &voucherUsageRepository{
+		db:             db,
+		baseRepository: &baseRepository[entity.VoucherUsage]{db: db},
+	}
+}
+
+func (r *voucherUsageRepository) CountByProfile(ctx context.Context, voucherId uint, profileId uint) (int, error) {
+	var count int64
+	err := r.conn(ctx).
+		Model(&entity.VoucherUsage{}).
+		Where("voucher_id = ?", voucherId).
+		Where("profile_id = ?", profileId).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
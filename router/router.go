@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/config"
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/handler"
 	"github.com/night1010/everhealth/middleware"
@@ -32,10 +33,19 @@ type Handlers struct {
 	StockMutation      *handler.StockMutationHandler
 	Chat               *handler.ChatHandler
 	Telemedicine       *handler.TelemedicineHandler
+	Payment            *handler.PaymentHandler
+	Refund             *handler.RefundHandler
+	Voucher            *handler.VoucherHandler
+	DoctorSchedule     *handler.DoctorScheduleHandler
+	DoctorReview       *handler.DoctorReviewHandler
+	ProductReview      *handler.ProductReviewHandler
+	Role               *handler.RoleHandler
+	PharmacyStaff      *handler.PharmacyStaffHandler
 }
 
-func New(handlers Handlers) http.Handler {
+func New(handlers Handlers, authenticator *middleware.Authenticator) http.Handler {
 	router := gin.New()
+	_ = router.SetTrustedProxies(config.NewRestConfig().TrustedProxies)
 
 	router.NoRoute(routeNotFoundHandler)
 
@@ -45,46 +55,63 @@ func New(handlers Handlers) http.Handler {
 	router.Use(middleware.Logger())
 	router.Use(middleware.Error())
 
-	auth := router.Group("/auth")
+	auth := router.Group("/auth", middleware.ClientIP())
 	auth.POST("/register", handlers.Auth.Register)
 	auth.POST("/verify/:id", middleware.PDFUpload(), handlers.Auth.Verify)
 	auth.POST("/login", handlers.Auth.Login)
+	auth.POST("/refresh", handlers.Auth.Refresh)
+	auth.POST("/logout", handlers.Auth.Logout)
 	auth.POST("/forgot-password", handlers.Auth.RequestForgotPassword)
 	auth.PUT("/forgot-password/apply", handlers.Auth.ApplyPassword)
+	auth.POST("/login/2fa", handlers.Auth.VerifyTwoFactorLogin)
+
+	twoFactor := auth.Group("/2fa")
+	twoFactor.POST("/challenge/enroll", handlers.Auth.EnrollTwoFactorChallenge)
+	twoFactor.POST("/challenge/activate", handlers.Auth.ActivateTwoFactorChallenge)
+	twoFactor.POST("/enroll", authenticator.Auth(entity.RoleDoctor, entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Auth.EnrollTwoFactor)
+	twoFactor.POST("/activate", authenticator.Auth(entity.RoleDoctor, entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Auth.ActivateTwoFactor)
+	twoFactor.POST("/recovery-codes", authenticator.Auth(entity.RoleDoctor, entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Auth.RegenerateRecoveryCodes)
+	twoFactor.POST("/disable", authenticator.Auth(entity.RoleDoctor, entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Auth.DisableTwoFactor)
 
 	user := router.Group("/users")
-	user.GET("", middleware.Auth(entity.RoleSuperAdmin), handlers.User.GetAllUser)
-	user.GET("/profile", middleware.Auth(entity.RoleUser, entity.RoleDoctor), handlers.User.GetProfile)
-	user.POST("/reset-password", middleware.Auth(entity.RoleUser, entity.RoleDoctor), handlers.User.ResetPassword)
-	user.PUT("/profile", middleware.Auth(entity.RoleUser, entity.RoleDoctor), middleware.ImageUploadMiddleware(), middleware.PDFUpload(), handlers.User.UpdateProfile)
-	user.PUT("/status", middleware.Auth(entity.RoleDoctor), handlers.User.UpdateStatus)
+	user.GET("", authenticator.Require(entity.PermissionUserRead), handlers.User.GetAllUser)
+	user.GET("/profile", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.User.GetProfile)
+	user.POST("/reset-password", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.User.ResetPassword)
+	user.PUT("/profile", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), middleware.ImageUploadMiddleware(), middleware.PDFUpload(), handlers.User.UpdateProfile)
+	user.PUT("/status", authenticator.Auth(entity.RoleDoctor), handlers.User.UpdateStatus)
+	user.PUT("/:id/role", authenticator.Require(entity.PermissionRoleManage), handlers.Role.AssignRole)
 
 	cart := router.Group("/cart")
-	cart.GET("", middleware.Auth(entity.RoleUser), handlers.Cart.GetCart)
-	cart.POST("", middleware.Auth(entity.RoleUser), handlers.Cart.AddItem)
-	cart.PATCH("/:id", middleware.Auth(entity.RoleUser), handlers.Cart.ChangeQty)
-	cart.DELETE("/:id", middleware.Auth(entity.RoleUser), handlers.Cart.DeleteItem)
-	cart.PATCH("/check/:id", middleware.Auth(entity.RoleUser), handlers.Cart.CheckItem)
-	cart.PUT("/check-all", middleware.Auth(entity.RoleUser), handlers.Cart.CheckAllItem)
+	cart.GET("", authenticator.Auth(entity.RoleUser), handlers.Cart.GetCart)
+	cart.POST("", authenticator.Auth(entity.RoleUser), handlers.Cart.AddItem)
+	cart.PATCH("/:id", authenticator.Auth(entity.RoleUser), handlers.Cart.ChangeQty)
+	cart.DELETE("/:id", authenticator.Auth(entity.RoleUser), handlers.Cart.DeleteItem)
+	cart.PATCH("/check/:id", authenticator.Auth(entity.RoleUser), handlers.Cart.CheckItem)
+	cart.PUT("/check-all", authenticator.Auth(entity.RoleUser), handlers.Cart.CheckAllItem)
+	cart.POST("/prescriptions/:id", authenticator.Auth(entity.RoleUser), handlers.Cart.AddPrescriptionItems)
 
 	router.GET("/doctors/:id", handlers.User.DoctorDetail)
+	router.GET("/doctors/:id/slots", handlers.DoctorSchedule.GetDoctorSlots)
+	router.GET("/doctors/:id/reviews", handlers.DoctorReview.ListDoctorReviews)
 	router.GET("/doctors", handlers.User.ListDoctor)
 
 	productCategory := router.Group("/product-categories")
 	productCategory.GET("", handlers.ProductCategory.GetProductCategories)
 	productCategory.GET("/:id", handlers.ProductCategory.GetProductCategoriesDetail)
-	productCategory.POST("", middleware.Auth(entity.RoleSuperAdmin), middleware.ImageUploadMiddleware(), handlers.ProductCategory.PostProductCategory)
-	productCategory.PUT("/:id", middleware.Auth(entity.RoleSuperAdmin), middleware.ImageUploadMiddleware(), handlers.ProductCategory.PutProductCategory)
-	productCategory.DELETE("/:id", middleware.Auth(entity.RoleSuperAdmin), handlers.ProductCategory.DeleteProductCategory)
+	productCategory.POST("", authenticator.Require(entity.PermissionProductCategoryWrite), middleware.ImageUploadMiddleware(), handlers.ProductCategory.PostProductCategory)
+	productCategory.PUT("/:id", authenticator.Require(entity.PermissionProductCategoryWrite), middleware.ImageUploadMiddleware(), handlers.ProductCategory.PutProductCategory)
+	productCategory.DELETE("/:id", authenticator.Require(entity.PermissionProductCategoryWrite), handlers.ProductCategory.DeleteProductCategory)
 
 	products := router.Group("/products")
 	products.GET("", handlers.Product.ListProduct)
-	products.GET("/admin", middleware.Auth(entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Product.ListProductAdmin)
-	products.GET("/:id/admin", middleware.Auth(entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Product.GetProductDetailAdmin)
-	products.GET("/nearby", middleware.Auth(entity.RoleUser), handlers.Product.ListNearbyProduct)
-	products.POST("", middleware.Auth(entity.RoleSuperAdmin), middleware.ImageUploadMiddleware(), handlers.Product.AddProduct)
-	products.PUT("/:id", middleware.Auth(entity.RoleSuperAdmin), middleware.ImageUploadMiddleware(), handlers.Product.UpdateProduct)
+	products.GET("/admin", authenticator.Require(entity.PermissionProductReadAdmin), handlers.Product.ListProductAdmin)
+	products.GET("/:id/admin", authenticator.Require(entity.PermissionProductReadAdmin), handlers.Product.GetProductDetailAdmin)
+	products.GET("/nearby", authenticator.Auth(entity.RoleUser), handlers.Product.ListNearbyProduct)
+	products.POST("", authenticator.Require(entity.PermissionProductWrite), middleware.ImageUploadMiddleware(), handlers.Product.AddProduct)
+	products.PUT("/:id", authenticator.Require(entity.PermissionProductWrite), middleware.ImageUploadMiddleware(), handlers.Product.UpdateProduct)
 	products.GET("/:id", handlers.Product.GetProductDetail)
+	products.GET("/:id/reviews", handlers.ProductReview.ListProductReviews)
+	products.POST("/:id/reviews", authenticator.Auth(entity.RoleUser), middleware.MultipleImageUpload(3), handlers.ProductReview.PostReview)
 
 	province := router.Group("/provinces")
 	province.GET("", handlers.Province.GetAllProvince)
@@ -103,67 +130,121 @@ func New(handlers Handlers) http.Handler {
 	doctorSpecialist.GET("", handlers.DoctorSpecialist.GetAllDoctorSpecialist)
 
 	address := router.Group("/addresses")
-	address.GET("", middleware.Auth(entity.RoleUser), handlers.Address.GetAddress)
-	address.POST("", middleware.Auth(entity.RoleUser), handlers.Address.CreateAddress)
-	address.PUT("/:id", middleware.Auth(entity.RoleUser), handlers.Address.UpdateAddress)
-	address.DELETE("/:id", middleware.Auth(entity.RoleUser), handlers.Address.DeleteAddress)
-	address.PATCH("/:id/default", middleware.Auth(entity.RoleUser), handlers.Address.ChangeDefaultAddress)
+	address.GET("", authenticator.Auth(entity.RoleUser), handlers.Address.GetAddress)
+	address.POST("", authenticator.Auth(entity.RoleUser), handlers.Address.CreateAddress)
+	address.PUT("/:id", authenticator.Auth(entity.RoleUser), handlers.Address.UpdateAddress)
+	address.DELETE("/:id", authenticator.Auth(entity.RoleUser), handlers.Address.DeleteAddress)
+	address.PATCH("/:id/default", authenticator.Auth(entity.RoleUser), handlers.Address.ChangeDefaultAddress)
 	address.POST("/validate", handlers.Address.ValidateAddress)
 
 	pharmacy := router.Group("/pharmacies")
-	pharmacy.GET("", middleware.Auth(entity.RoleAdmin), handlers.Pharmacy.GetAllPharmacy)
-	pharmacy.GET("/super-admin", middleware.Auth(entity.RoleSuperAdmin, entity.RoleAdmin), handlers.Pharmacy.GetAllPharmacySuperAdmin)
-	pharmacy.GET("/:pharmacy_id", middleware.Auth(entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Pharmacy.GetPharmacyDetail)
-	pharmacy.POST("", middleware.Auth(entity.RoleAdmin), handlers.Pharmacy.PostPharmacy)
-	pharmacy.PUT("/:pharmacy_id", middleware.Auth(entity.RoleAdmin), handlers.Pharmacy.PutPharmacy)
+	pharmacy.GET("", authenticator.Require(entity.PermissionPharmacyRead), handlers.Pharmacy.GetAllPharmacy)
+	pharmacy.GET("/super-admin", authenticator.Require(entity.PermissionPharmacyRead), handlers.Pharmacy.GetAllPharmacySuperAdmin)
+	pharmacy.GET("/:pharmacy_id", authenticator.Require(entity.PermissionPharmacyRead), handlers.Pharmacy.GetPharmacyDetail)
+	pharmacy.POST("", authenticator.Require(entity.PermissionPharmacyWrite), handlers.Pharmacy.PostPharmacy)
+	pharmacy.PUT("/:pharmacy_id", authenticator.Require(entity.PermissionPharmacyWrite), handlers.Pharmacy.PutPharmacy)
+
+	pharmacyStaff := pharmacy.Group("/:pharmacy_id/staff")
+	pharmacyStaff.GET("", authenticator.Require(entity.PermissionPharmacyRead), handlers.PharmacyStaff.GetAllStaff)
+	pharmacyStaff.POST("", authenticator.Require(entity.PermissionPharmacyStaffManage), handlers.PharmacyStaff.PostStaff)
+	pharmacyStaff.DELETE("/:user_id", authenticator.Require(entity.PermissionPharmacyStaffManage), handlers.PharmacyStaff.DeleteStaff)
 
 	pharmacyProduct := pharmacy.Group("/:pharmacy_id/products")
-	pharmacyProduct.GET("", middleware.Auth(entity.RoleAdmin), handlers.PharmacyProduct.GetAllPharmacy)
-	pharmacyProduct.POST("", middleware.Auth(entity.RoleAdmin), handlers.PharmacyProduct.PostPharmacyProduct)
-	pharmacyProduct.GET("/:product_id", middleware.Auth(entity.RoleAdmin), handlers.PharmacyProduct.GetPharmacyProductDetail)
-	pharmacyProduct.PUT("/:product_id", middleware.Auth(entity.RoleAdmin), handlers.PharmacyProduct.PutPharmacyProduct)
+	pharmacyProduct.GET("", authenticator.Require(entity.PermissionPharmacyProductRead), handlers.PharmacyProduct.GetAllPharmacy)
+	pharmacyProduct.POST("", authenticator.Require(entity.PermissionPharmacyProductCreate), handlers.PharmacyProduct.PostPharmacyProduct)
+	pharmacyProduct.GET("/:product_id", authenticator.Require(entity.PermissionPharmacyProductRead), handlers.PharmacyProduct.GetPharmacyProductDetail)
+	pharmacyProduct.PUT("/:product_id", authenticator.Require(entity.PermissionPharmacyProductUpdate), handlers.PharmacyProduct.PutPharmacyProduct)
 
 	adminPharmacy := router.Group("/admins-pharmacy")
-	adminPharmacy.GET("", middleware.Auth(entity.RoleSuperAdmin), handlers.AdminPharmacy.GetAllAdminPharmacy)
-	adminPharmacy.GET("/:id", middleware.Auth(entity.RoleSuperAdmin), handlers.AdminPharmacy.GetDetailAdminPharmacy)
-	adminPharmacy.POST("", middleware.Auth(entity.RoleSuperAdmin), handlers.AdminPharmacy.PostAdminPharmacy)
-	adminPharmacy.DELETE("/:id", middleware.Auth(entity.RoleSuperAdmin), handlers.AdminPharmacy.DeleteAdminPharmacy)
-	adminPharmacy.PUT("/:id", middleware.Auth(entity.RoleSuperAdmin), handlers.AdminPharmacy.UpdateAdminPharmacy)
+	adminPharmacy.GET("", authenticator.Require(entity.PermissionAdminPharmacyManage), handlers.AdminPharmacy.GetAllAdminPharmacy)
+	adminPharmacy.GET("/:id", authenticator.Require(entity.PermissionAdminPharmacyManage), handlers.AdminPharmacy.GetDetailAdminPharmacy)
+	adminPharmacy.POST("", authenticator.Require(entity.PermissionAdminPharmacyManage), handlers.AdminPharmacy.PostAdminPharmacy)
+	adminPharmacy.DELETE("/:id", authenticator.Require(entity.PermissionAdminPharmacyManage), handlers.AdminPharmacy.DeleteAdminPharmacy)
+	adminPharmacy.PUT("/:id", authenticator.Require(entity.PermissionAdminPharmacyManage), handlers.AdminPharmacy.UpdateAdminPharmacy)
 
 	stockRecord := router.Group("/stock-records")
-	stockRecord.GET("", middleware.Auth(entity.RoleAdmin), handlers.StockRecord.GetAllStockRecord)
-	stockRecord.GET("/monthly", middleware.Auth(entity.RoleAdmin), handlers.StockRecord.GetStockMonthlyReport)
-	stockRecord.POST("", middleware.Auth(entity.RoleAdmin), handlers.StockRecord.PostStockRecord)
+	stockRecord.GET("", authenticator.Require(entity.PermissionStockRecordRead), handlers.StockRecord.GetAllStockRecord)
+	stockRecord.GET("/monthly", authenticator.Require(entity.PermissionStockRecordRead), handlers.StockRecord.GetStockMonthlyReport)
+	stockRecord.POST("", authenticator.Require(entity.PermissionStockRecordWrite), handlers.StockRecord.PostStockRecord)
 
 	stockMutation := router.Group("/stock-mutations")
-	stockMutation.GET("", middleware.Auth(entity.RoleAdmin), handlers.StockMutation.GetAllStockMutation)
-	stockMutation.GET("/:id", middleware.Auth(entity.RoleAdmin), handlers.StockMutation.GetStockMutationDetail)
-	stockMutation.POST("", middleware.Auth(entity.RoleAdmin), handlers.StockMutation.PostStockMutation)
-	stockMutation.POST("/:id/change-status", middleware.Auth(entity.RoleAdmin), handlers.StockMutation.ChangeStatusStockMutation)
-	stockMutation.GET("/pharmacy", middleware.Auth(entity.RoleAdmin), handlers.StockMutation.GetAllAvailablePharmacyStockMutation)
-	router.GET("/shipping-method/:id", middleware.Auth(entity.RoleUser), handlers.ShippingMethod.GetShippingMethod)
+	stockMutation.GET("", authenticator.Require(entity.PermissionStockMutationRead), handlers.StockMutation.GetAllStockMutation)
+	stockMutation.GET("/:id", authenticator.Require(entity.PermissionStockMutationRead), handlers.StockMutation.GetStockMutationDetail)
+	stockMutation.POST("", authenticator.Require(entity.PermissionStockMutationWrite), handlers.StockMutation.PostStockMutation)
+	stockMutation.POST("/:id/change-status", authenticator.Require(entity.PermissionStockMutationWrite), handlers.StockMutation.ChangeStatusStockMutation)
+	stockMutation.GET("/pharmacy", authenticator.Require(entity.PermissionStockMutationRead), handlers.StockMutation.GetAllAvailablePharmacyStockMutation)
+	router.GET("/shipping-method/:id", authenticator.Auth(entity.RoleUser), handlers.ShippingMethod.GetShippingMethod)
 
 	order := router.Group("/order")
-	order.POST("", middleware.Auth(entity.RoleUser), handlers.Order.CreateOrder)
-	order.GET("", middleware.Auth(entity.RoleUser, entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Order.OrderHistory)
-	order.GET("/:id", middleware.Auth(entity.RoleUser, entity.RoleAdmin, entity.RoleSuperAdmin), handlers.Order.OrderDetail)
-	order.GET("/items/:id", middleware.Auth(entity.RoleUser), handlers.Order.GetAvailableProduct)
-	order.POST("/:id/upload-proof", middleware.Auth(entity.RoleUser), middleware.ImageUploadMiddleware(), handlers.Order.UploadPaymentProof)
-	order.GET("/monthly-sales", middleware.Auth(entity.RoleSuperAdmin, entity.RoleAdmin), handlers.Order.GetMonthlySaleReport)
-	order.PATCH("/:id/status-admin", middleware.Auth(entity.RoleAdmin), handlers.Order.AdminUpdateOrderStatus)
-	order.PATCH("/:id/status-user", middleware.Auth(entity.RoleUser), handlers.Order.UserUpdateOrderStatus)
+	order.POST("", authenticator.Auth(entity.RoleUser), handlers.Order.CreateOrder)
+	order.GET("", authenticator.Require(entity.PermissionOrderRead), handlers.Order.OrderHistory)
+	order.GET("/:id", authenticator.Require(entity.PermissionOrderRead), handlers.Order.OrderDetail)
+	order.GET("/:id/invoice", authenticator.Require(entity.PermissionOrderInvoiceRead), handlers.Order.GetInvoice)
+	order.GET("/items/:id", authenticator.Auth(entity.RoleUser), handlers.Order.GetAvailableProduct)
+	order.POST("/prescriptions", authenticator.Auth(entity.RoleUser), middleware.ImageUploadMiddleware(), handlers.Order.UploadPrescription)
+	order.PATCH("/:id/prescription", authenticator.Require(entity.PermissionOrderReviewPrescription), handlers.Order.ReviewPrescription)
+	order.POST("/:id/upload-proof", authenticator.Auth(entity.RoleUser), middleware.ImageUploadMiddleware(), handlers.Order.UploadPaymentProof)
+	order.GET("/monthly-sales", authenticator.Require(entity.PermissionOrderReport), handlers.Order.GetMonthlySaleReport)
+	order.PATCH("/:id/status-admin", authenticator.Require(entity.PermissionOrderUpdateStatus), handlers.Order.AdminUpdateOrderStatus)
+	order.PATCH("/:id/status-user", authenticator.Auth(entity.RoleUser), handlers.Order.UserUpdateOrderStatus)
+	order.POST("/:id/refunds", authenticator.Require(entity.PermissionRefundRequest), handlers.Refund.RequestItemRefund)
 
 	chat := router.Group("/chat")
 	chat.GET("/:id", handlers.Chat.Handle)
 
 	telemedicine := router.Group("/telemedicines")
-	telemedicine.POST("", middleware.Auth(entity.RoleUser), handlers.Telemedicine.PostTelemedicine)
-	telemedicine.POST("/:id/payment", middleware.Auth(entity.RoleUser), middleware.ImageUploadMiddleware(), handlers.Telemedicine.PostPaymentTelemedicine)
-	telemedicine.POST("/:id/sick-leave", middleware.Auth(entity.RoleDoctor), handlers.Telemedicine.PostSickLeave)
-	telemedicine.POST("/:id/prescription", middleware.Auth(entity.RoleDoctor), handlers.Telemedicine.PostPrescription)
-	telemedicine.GET("", middleware.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.GetAllTelemedicine)
-	telemedicine.GET("/:id", middleware.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.GetTelemedicineDetail)
-	telemedicine.PUT("/:id/end", middleware.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.EndChat)
+	telemedicine.POST("", authenticator.Auth(entity.RoleUser), handlers.Telemedicine.PostTelemedicine)
+	telemedicine.POST("/bookings", authenticator.Auth(entity.RoleUser), handlers.Telemedicine.PostBookingTelemedicine)
+	telemedicine.POST("/queues", authenticator.Auth(entity.RoleUser), handlers.Telemedicine.PostQueue)
+	telemedicine.GET("/queues", authenticator.Auth(entity.RoleUser), handlers.Telemedicine.GetQueue)
+	telemedicine.DELETE("/queues", authenticator.Auth(entity.RoleUser), handlers.Telemedicine.DeleteQueue)
+	telemedicine.POST("/:id/payment", authenticator.Auth(entity.RoleUser), middleware.ImageUploadMiddleware(), handlers.Telemedicine.PostPaymentTelemedicine)
+	telemedicine.POST("/:id/sick-leave", authenticator.Auth(entity.RoleDoctor), handlers.Telemedicine.PostSickLeave)
+	telemedicine.POST("/:id/prescription", authenticator.Auth(entity.RoleDoctor), handlers.Telemedicine.PostPrescription)
+	telemedicine.GET("", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.GetAllTelemedicine)
+	telemedicine.GET("/:id", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.GetTelemedicineDetail)
+	telemedicine.GET("/:id/messages", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Chat.GetMessages)
+	telemedicine.GET("/:id/transcript.pdf", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.GetTranscript)
+	telemedicine.POST("/:id/attachments", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), middleware.PDFUpload(), middleware.ImageUploadMiddleware("image/png", "image/jpeg"), handlers.Chat.PostAttachment)
+	telemedicine.PUT("/:id/end", authenticator.Auth(entity.RoleUser, entity.RoleDoctor), handlers.Telemedicine.EndChat)
+	telemedicine.POST("/:id/review", authenticator.Auth(entity.RoleUser), handlers.DoctorReview.PostReview)
+
+	payment := router.Group("/payments")
+	payment.POST("", authenticator.Auth(entity.RoleUser), handlers.Payment.CreatePayment)
+	payment.POST("/webhook/:provider", handlers.Payment.Webhook)
+
+	refund := router.Group("/refunds")
+	refund.GET("", authenticator.Require(entity.PermissionRefundRead), handlers.Refund.ListRefunds)
+	refund.PATCH("/:id/approve", authenticator.Require(entity.PermissionRefundProcess), handlers.Refund.ApproveRefund)
+	refund.PATCH("/:id/paid", authenticator.Require(entity.PermissionRefundProcess), handlers.Refund.MarkRefundPaid)
+
+	voucher := router.Group("/vouchers", authenticator.Require(entity.PermissionVoucherManage))
+	voucher.GET("", handlers.Voucher.ListVouchers)
+	voucher.GET("/:id", handlers.Voucher.GetVoucher)
+	voucher.POST("", handlers.Voucher.CreateVoucher)
+	voucher.PUT("/:id", handlers.Voucher.UpdateVoucher)
+	voucher.DELETE("/:id", handlers.Voucher.DeleteVoucher)
+
+	schedule := router.Group("/schedules", authenticator.Auth(entity.RoleDoctor))
+	schedule.GET("", handlers.DoctorSchedule.GetSchedule)
+	schedule.PUT("/availabilities", handlers.DoctorSchedule.PutAvailabilities)
+	schedule.POST("/exceptions", handlers.DoctorSchedule.PostException)
+	schedule.DELETE("/exceptions/:id", handlers.DoctorSchedule.DeleteException)
+
+	doctorReview := router.Group("/doctor-reviews", authenticator.Require(entity.PermissionDoctorReviewModerate))
+	doctorReview.GET("", handlers.DoctorReview.ListAllReviews)
+	doctorReview.PATCH("/:id", handlers.DoctorReview.PatchReview)
+
+	productReview := router.Group("/product-reviews", authenticator.Require(entity.PermissionProductReviewModerate))
+	productReview.GET("", handlers.ProductReview.ListAllReviews)
+	productReview.PATCH("/:id", handlers.ProductReview.PatchReview)
+
+	role := router.Group("/roles", authenticator.Require(entity.PermissionRoleManage))
+	role.GET("", handlers.Role.ListRoles)
+	role.POST("", handlers.Role.CreateRole)
+	role.PUT("/:id", handlers.Role.UpdateRole)
+	role.DELETE("/:id", handlers.Role.DeleteRole)
+	router.GET("/permissions", authenticator.Require(entity.PermissionRoleManage), handlers.Role.ListPermissions)
 	return router
 }
 
@@ -0,0 +1,79 @@
+package throttle
+
+import (
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+)
+
+type Policy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+var (
+	LoginAccountPolicy = &Policy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+	LoginIPPolicy = &Policy{
+		FreeAttempts:     20,
+		BaseDelay:        time.Second,
+		MaxDelay:         15 * time.Minute,
+		LockoutThreshold: 100,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	}
+	RequestPolicy = &Policy{
+		FreeAttempts: 5,
+		BaseDelay:    30 * time.Second,
+		MaxDelay:     time.Hour,
+		Window:       time.Hour,
+	}
+	RequestIPPolicy = &Policy{
+		FreeAttempts: 20,
+		BaseDelay:    30 * time.Second,
+		MaxDelay:     time.Hour,
+		Window:       time.Hour,
+	}
+)
+
+func (p *Policy) RetryAfter(attempt *entity.AuthAttempt, now time.Time) time.Duration {
+	if attempt == nil || attempt.LockedUntil == nil || !attempt.LockedUntil.After(now) {
+		return 0
+	}
+	return attempt.LockedUntil.Sub(now)
+}
+
+func (p *Policy) Fail(attempt *entity.AuthAttempt, now time.Time) (lockedOut bool) {
+	if now.Sub(attempt.LastFailedAt) > p.Window {
+		attempt.Failures = 0
+		attempt.LockedUntil = nil
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	if p.LockoutThreshold > 0 && attempt.Failures >= p.LockoutThreshold {
+		lockedUntil := now.Add(p.LockoutDuration)
+		attempt.LockedUntil = &lockedUntil
+		return attempt.Failures == p.LockoutThreshold
+	}
+	if attempt.Failures <= p.FreeAttempts {
+		return false
+	}
+	delay := p.MaxDelay
+	exponent := attempt.Failures - p.FreeAttempts - 1
+	if exponent < 32 && p.BaseDelay<<exponent < p.MaxDelay {
+		delay = p.BaseDelay << exponent
+	}
+	lockedUntil := now.Add(delay)
+	attempt.LockedUntil = &lockedUntil
+	return false
+}
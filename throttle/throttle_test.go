@@ -0,0 +1,69 @@
+package throttle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/throttle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := &throttle.Policy{
+		FreeAttempts:     2,
+		BaseDelay:        time.Second,
+		MaxDelay:         4 * time.Second,
+		LockoutThreshold: 8,
+		LockoutDuration:  time.Minute,
+		Window:           time.Hour,
+	}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should not delay free attempts", func(t *testing.T) {
+		attempt := &entity.AuthAttempt{}
+
+		policy.Fail(attempt, now)
+		policy.Fail(attempt, now)
+
+		assert.Equal(t, time.Duration(0), policy.RetryAfter(attempt, now))
+	})
+
+	t.Run("should back off exponentially up to max delay", func(t *testing.T) {
+		attempt := &entity.AuthAttempt{}
+		var delays []time.Duration
+
+		for i := 0; i < 6; i++ {
+			policy.Fail(attempt, now)
+			delays = append(delays, policy.RetryAfter(attempt, now))
+		}
+
+		assert.Equal(t, []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}, delays)
+	})
+
+	t.Run("should lock out once when threshold is reached", func(t *testing.T) {
+		attempt := &entity.AuthAttempt{}
+		var lockouts int
+
+		for i := 0; i < 9; i++ {
+			if policy.Fail(attempt, now) {
+				lockouts++
+			}
+		}
+
+		assert.Equal(t, 1, lockouts)
+		assert.Equal(t, time.Minute, policy.RetryAfter(attempt, now))
+	})
+
+	t.Run("should reset failures after window", func(t *testing.T) {
+		attempt := &entity.AuthAttempt{}
+		for i := 0; i < 5; i++ {
+			policy.Fail(attempt, now)
+		}
+
+		policy.Fail(attempt, now.Add(2*time.Hour))
+
+		assert.Equal(t, 1, attempt.Failures)
+		assert.Equal(t, time.Duration(0), policy.RetryAfter(attempt, now.Add(2*time.Hour)))
+	})
+}
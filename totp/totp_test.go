@@ -0,0 +1,53 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/night1010/everhealth/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotp(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	t.Run("should match rfc 6238 test vectors", func(t *testing.T) {
+		code, err := totp.GenerateCode(secret, totp.Step(time.Unix(59, 0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "287082", code)
+
+		code, err = totp.GenerateCode(secret, totp.Step(time.Unix(1111111109, 0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "081804", code)
+	})
+
+	t.Run("should accept code from adjacent step", func(t *testing.T) {
+		now := time.Unix(1111111109, 0)
+		code, _ := totp.GenerateCode(secret, totp.Step(now)-1)
+
+		step, ok := totp.Validate(secret, code, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, totp.Step(now)-1, step)
+	})
+
+	t.Run("should reject code outside skew window", func(t *testing.T) {
+		now := time.Unix(1111111109, 0)
+		code, _ := totp.GenerateCode(secret, totp.Step(now)-2)
+
+		_, ok := totp.Validate(secret, code, now)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("should build provisioning uri", func(t *testing.T) {
+		uri := totp.ProvisioningURI(secret, "everhealth", "admin@mail.com")
+
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/everhealth:admin@mail.com?"))
+		assert.Contains(t, uri, "secret="+secret)
+	})
+}
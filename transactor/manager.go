@@ -19,6 +19,9 @@ func NewManager(db *gorm.DB) Manager {
 }
 
 func (t *manager) Run(ctx context.Context, runner func(c context.Context) error) error {
+	if _, ok := ctx.Value("tx").(*gorm.DB); ok {
+		return runner(ctx)
+	}
 	tx := t.db.Begin()
 	ctx = injectTx(ctx, tx)
 
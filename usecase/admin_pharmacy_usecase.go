@@ -24,12 +24,13 @@ type adminPharmacyUsecase struct {
 	adminPharmacyRepository repository.UserRepository
 	pharmacyRepository      repository.PharmacyRepository
 	adminContactRepository  repository.AdminContactRepository
+	staffRepository         repository.PharmacyStaffRepository
 	hash                    hasher.Hasher
 	manager                 transactor.Manager
 }
 
-func NewAdminPharmacyUsecase(r repository.UserRepository, h hasher.Hasher, p repository.PharmacyRepository, c repository.AdminContactRepository, m transactor.Manager) AdminPharmacyUsecase {
-	return &adminPharmacyUsecase{adminPharmacyRepository: r, hash: h, pharmacyRepository: p, adminContactRepository: c, manager: m}
+func NewAdminPharmacyUsecase(r repository.UserRepository, h hasher.Hasher, p repository.PharmacyRepository, c repository.AdminContactRepository, s repository.PharmacyStaffRepository, m transactor.Manager) AdminPharmacyUsecase {
+	return &adminPharmacyUsecase{adminPharmacyRepository: r, hash: h, pharmacyRepository: p, adminContactRepository: c, staffRepository: s, manager: m}
 }
 
 func (u *adminPharmacyUsecase) FindAllAdminPharmacy(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
@@ -37,7 +38,7 @@ func (u *adminPharmacyUsecase) FindAllAdminPharmacy(ctx context.Context, query *
 }
 
 func (u *adminPharmacyUsecase) FindOneAdminPharmacy(ctx context.Context, adminPharmacy *entity.User) (*entity.User, error) {
-	selectAdmin, err := u.adminPharmacyRepository.FindOne(ctx, valueobject.NewQuery().Condition("\"users\".id", valueobject.Equal, adminPharmacy.Id).Condition("\"users\".role_id", valueobject.Equal, entity.RoleAdmin).WithPreload("AdminContact"))
+	selectAdmin, err := u.adminPharmacyRepository.FindOne(ctx, valueobject.NewQuery().Condition("\"users\".id", valueobject.Equal, adminPharmacy.Id).Condition(repository.BaseRoleIdColumn, valueobject.Equal, entity.RoleAdmin).WithJoin("Role").WithPreload("AdminContact"))
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +85,7 @@ func (u *adminPharmacyUsecase) UpdateAdminPharmacy(ctx context.Context, adminPha
 		}
 		adminPharmacy.RoleId = selectAdmin.RoleId
 		adminPharmacy.Password = selectAdmin.Password
+		adminPharmacy.TokenVersion = selectAdmin.TokenVersion
 		_, err = u.adminPharmacyRepository.Update(c, adminPharmacy)
 		if err != nil {
 			return err
@@ -109,6 +111,13 @@ func (u *adminPharmacyUsecase) DeleteAdminPharmacy(ctx context.Context, adminPha
 	if checkAdmin != nil {
 		return apperror.NewClientError(errors.New("cannot delete admin pharmacy because this admin already manage pharmacy"))
 	}
+	checkStaff, err := u.staffRepository.FindOne(ctx, valueobject.NewQuery().Condition("user_id", valueobject.Equal, adminPharmacy.Id))
+	if err != nil {
+		return err
+	}
+	if checkStaff != nil {
+		return apperror.NewClientError(errors.New("cannot delete admin pharmacy because this admin is still a pharmacy staff"))
+	}
 	err = u.manager.Run(ctx, func(c context.Context) error {
 		err = u.adminContactRepository.HardDelete(c, &entity.AdminContact{UserId: adminPharmacy.Id})
 		if err != nil {
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/throttle"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+const authAttemptRetention = 24 * time.Hour
+
+type AuthAttemptUsecase interface {
+	Check(ctx context.Context, scope entity.AuthAttemptScope, email, ip string) error
+	Fail(ctx context.Context, scope entity.AuthAttemptScope, email, ip string) (bool, error)
+	Reset(ctx context.Context, scope entity.AuthAttemptScope, email string) error
+	PurgeStale(ctx context.Context) error
+}
+
+type authAttemptUsecase struct {
+	manager           transactor.Manager
+	attemptRepository repository.AuthAttemptRepository
+}
+
+func NewAuthAttemptUsecase(m transactor.Manager, ar repository.AuthAttemptRepository) AuthAttemptUsecase {
+	return &authAttemptUsecase{manager: m, attemptRepository: ar}
+}
+
+type authAttemptKey struct {
+	identifier string
+	policy     *throttle.Policy
+	isAccount  bool
+}
+
+func (u *authAttemptUsecase) Check(ctx context.Context, scope entity.AuthAttemptScope, email, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, key := range authAttemptKeys(scope, email, ip) {
+		attempt, err := u.attemptRepository.FindOne(ctx, valueobject.NewQuery().
+			Condition("scope", valueobject.Equal, scope).
+			Condition("identifier", valueobject.Equal, key.identifier))
+		if err != nil {
+			return err
+		}
+		if wait := key.policy.RetryAfter(attempt, now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+	if retryAfter > 0 {
+		return apperror.NewTooManyAttemptsError(retryAfter)
+	}
+	return nil
+}
+
+func (u *authAttemptUsecase) Fail(ctx context.Context, scope entity.AuthAttemptScope, email, ip string) (bool, error) {
+	var lockedOut bool
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		now := time.Now()
+		for _, key := range authAttemptKeys(scope, email, ip) {
+			attempt, err := u.attemptRepository.FindOrCreateForUpdate(c, scope, key.identifier)
+			if err != nil {
+				return err
+			}
+			if key.policy.Fail(attempt, now) && key.isAccount {
+				lockedOut = true
+			}
+			_, err = u.attemptRepository.Update(c, attempt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return lockedOut, nil
+}
+
+func (u *authAttemptUsecase) Reset(ctx context.Context, scope entity.AuthAttemptScope, email string) error {
+	attempt, err := u.attemptRepository.FindOne(ctx, valueobject.NewQuery().
+		Condition("scope", valueobject.Equal, scope).
+		Condition("identifier", valueobject.Equal, accountIdentifier(email)))
+	if err != nil {
+		return err
+	}
+	if attempt == nil {
+		return nil
+	}
+	return u.attemptRepository.Delete(ctx, attempt)
+}
+
+func (u *authAttemptUsecase) PurgeStale(ctx context.Context) error {
+	return u.attemptRepository.DeleteStale(ctx, time.Now().Add(-authAttemptRetention))
+}
+
+func authAttemptKeys(scope entity.AuthAttemptScope, email, ip string) []*authAttemptKey {
+	accountPolicy, ipPolicy := throttle.RequestPolicy, throttle.RequestIPPolicy
+	if scope == entity.LoginScope {
+		accountPolicy, ipPolicy = throttle.LoginAccountPolicy, throttle.LoginIPPolicy
+	}
+	keys := []*authAttemptKey{{identifier: accountIdentifier(email), policy: accountPolicy, isAccount: true}}
+	if ip != "" {
+		keys = append(keys, &authAttemptKey{identifier: "ip:" + ip, policy: ipPolicy})
+	}
+	return keys
+}
+
+func accountIdentifier(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
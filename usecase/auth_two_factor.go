@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/config"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/totp"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	recoveryCodeEncoding    = base32.StdEncoding.WithPadding(base32.NoPadding)
+	errInvalidTwoFactorCode = apperror.NewClientError(errors.New("invalid two factor code")).Unauthorized()
+	errInvalidRecoveryCode  = apperror.NewClientError(errors.New("invalid recovery code")).Unauthorized()
+)
+
+func (u *authUsecase) VerifyTwoFactorLogin(ctx context.Context, challengeToken, code, recoveryCode string) (*entity.User, error) {
+	fetchedUser, err := u.resolveChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	ip, _ := ctx.Value("client_ip").(string)
+	err = u.attemptUsecase.Check(ctx, entity.LoginScope, fetchedUser.Email, ip)
+	if err != nil {
+		return nil, err
+	}
+	var invalidCode bool
+	err = u.manager.Run(ctx, func(c context.Context) error {
+		twoFactor, err := u.findEnabledTwoFactor(c, fetchedUser.Id)
+		if err != nil {
+			return err
+		}
+		if recoveryCode != "" {
+			err = u.useRecoveryCode(c, fetchedUser.Id, recoveryCode)
+		} else {
+			err = u.verifyTwoFactorCode(c, twoFactor, code)
+		}
+		if err != nil {
+			invalidCode = errors.Is(err, errInvalidTwoFactorCode) || errors.Is(err, errInvalidRecoveryCode)
+			return err
+		}
+		return u.issueTokens(c, fetchedUser)
+	})
+	if invalidCode {
+		return nil, u.failLogin(ctx, fetchedUser.Email, fetchedUser)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = u.attemptUsecase.Reset(ctx, entity.LoginScope, fetchedUser.Email)
+	if err != nil {
+		return nil, err
+	}
+	return fetchedUser, nil
+}
+
+func (u *authUsecase) EnrollTwoFactor(ctx context.Context) (*entity.UserTwoFactor, error) {
+	return u.enrollTwoFactor(ctx, ctx.Value("user_id").(uint))
+}
+
+func (u *authUsecase) EnrollTwoFactorChallenge(ctx context.Context, challengeToken string) (*entity.UserTwoFactor, error) {
+	fetchedUser, err := u.resolveChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	return u.enrollTwoFactor(ctx, fetchedUser.Id)
+}
+
+func (u *authUsecase) ActivateTwoFactor(ctx context.Context, code string) ([]string, error) {
+	return u.activateTwoFactor(ctx, ctx.Value("user_id").(uint), code)
+}
+
+func (u *authUsecase) ActivateTwoFactorChallenge(ctx context.Context, challengeToken, code string) (*entity.User, []string, error) {
+	fetchedUser, err := u.resolveChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recoveryCodes []string
+	err = u.manager.Run(ctx, func(c context.Context) error {
+		recoveryCodes, err = u.activateTwoFactor(c, fetchedUser.Id, code)
+		if err != nil {
+			return err
+		}
+		return u.issueTokens(c, fetchedUser)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return fetchedUser, recoveryCodes, nil
+}
+
+func (u *authUsecase) DisableTwoFactor(ctx context.Context, code string) error {
+	userId := ctx.Value("user_id").(uint)
+	roleId := ctx.Value("role_id").(entity.RoleId)
+	if roleId.RequiresTwoFactor() {
+		return apperror.NewForbiddenActionError("two factor authentication is mandatory for this role")
+	}
+	return u.manager.Run(ctx, func(c context.Context) error {
+		twoFactor, err := u.findEnabledTwoFactor(c, userId)
+		if err != nil {
+			return err
+		}
+		err = u.verifyTwoFactorCode(c, twoFactor, code)
+		if err != nil {
+			return err
+		}
+		err = u.recoveryCodeRepo.DeleteByUserId(c, userId)
+		if err != nil {
+			return err
+		}
+		return u.twoFactorRepo.Delete(c, twoFactor)
+	})
+}
+
+func (u *authUsecase) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	userId := ctx.Value("user_id").(uint)
+	var recoveryCodes []string
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		twoFactor, err := u.findEnabledTwoFactor(c, userId)
+		if err != nil {
+			return err
+		}
+		err = u.verifyTwoFactorCode(c, twoFactor, code)
+		if err != nil {
+			return err
+		}
+		recoveryCodes, err = u.replaceRecoveryCodes(c, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+func (u *authUsecase) twoFactorChallenge(ctx context.Context, user *entity.User) (*entity.TwoFactorChallenge, error) {
+	twoFactor, err := u.findTwoFactor(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	enabled := twoFactor != nil && twoFactor.IsEnabled
+	if !enabled {
+		required, err := u.requiresTwoFactor(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+	}
+	token, err := u.jwt.GenerateChallengeToken(user)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.TwoFactorChallenge{Token: token, EnrollmentRequired: !enabled}, nil
+}
+
+func (u *authUsecase) requiresTwoFactor(ctx context.Context, user *entity.User) (bool, error) {
+	grant, err := u.roleResolver.Resolve(ctx, user.RoleId)
+	if err != nil {
+		return false, err
+	}
+	return grant.BaseRoleId.RequiresTwoFactor(), nil
+}
+
+func (u *authUsecase) resolveChallenge(ctx context.Context, challengeToken string) (*entity.User, error) {
+	claims, err := u.jwt.ValidateChallengeToken(ctx, challengeToken)
+	if err != nil {
+		return nil, apperror.NewInvalidTokenError()
+	}
+	fetchedUser, err := u.userRepo.FindById(ctx, claims.Id)
+	if err != nil {
+		return nil, err
+	}
+	if fetchedUser == nil {
+		return nil, apperror.NewInvalidTokenError()
+	}
+	return fetchedUser, nil
+}
+
+func (u *authUsecase) enrollTwoFactor(ctx context.Context, userId uint) (*entity.UserTwoFactor, error) {
+	var twoFactor *entity.UserTwoFactor
+	var email string
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		fetchedUser, err := u.userRepo.FindById(c, userId)
+		if err != nil {
+			return err
+		}
+		if fetchedUser == nil {
+			return apperror.NewResourceNotFoundError("user", "id", userId)
+		}
+		email = fetchedUser.Email
+		twoFactor, err = u.twoFactorRepo.FindOne(c, valueobject.NewQuery().Condition("user_id", valueobject.Equal, userId).Lock())
+		if err != nil {
+			return err
+		}
+		if twoFactor != nil && twoFactor.IsEnabled {
+			return apperror.NewResourceStateError("two factor authentication is already enabled")
+		}
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			return err
+		}
+		if twoFactor == nil {
+			twoFactor, err = u.twoFactorRepo.Create(c, &entity.UserTwoFactor{UserId: userId, Secret: secret})
+			return err
+		}
+		twoFactor.Secret = secret
+		twoFactor.LastUsedStep = 0
+		_, err = u.twoFactorRepo.Update(c, twoFactor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.ProvisioningUri = totp.ProvisioningURI(twoFactor.Secret, config.NewAppConfig().Name, email)
+	return twoFactor, nil
+}
+
+func (u *authUsecase) activateTwoFactor(ctx context.Context, userId uint, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		twoFactor, err := u.twoFactorRepo.FindOne(c, valueobject.NewQuery().Condition("user_id", valueobject.Equal, userId).Lock())
+		if err != nil {
+			return err
+		}
+		if twoFactor == nil {
+			return apperror.NewResourceNotFoundError("two factor enrollment", "user id", userId)
+		}
+		if twoFactor.IsEnabled {
+			return apperror.NewResourceStateError("two factor authentication is already enabled")
+		}
+		err = u.verifyTwoFactorCode(c, twoFactor, code)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		twoFactor.IsEnabled = true
+		twoFactor.EnabledAt = &now
+		_, err = u.twoFactorRepo.Update(c, twoFactor)
+		if err != nil {
+			return err
+		}
+		recoveryCodes, err = u.replaceRecoveryCodes(c, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+func (u *authUsecase) findTwoFactor(ctx context.Context, userId uint) (*entity.UserTwoFactor, error) {
+	return u.twoFactorRepo.FindOne(ctx, valueobject.NewQuery().Condition("user_id", valueobject.Equal, userId))
+}
+
+func (u *authUsecase) findEnabledTwoFactor(ctx context.Context, userId uint) (*entity.UserTwoFactor, error) {
+	twoFactor, err := u.twoFactorRepo.FindOne(ctx, valueobject.NewQuery().Condition("user_id", valueobject.Equal, userId).Lock())
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled {
+		return nil, apperror.NewResourceStateError("two factor authentication is not enabled")
+	}
+	return twoFactor, nil
+}
+
+func (u *authUsecase) verifyTwoFactorCode(ctx context.Context, twoFactor *entity.UserTwoFactor, code string) error {
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now())
+	if !ok || step <= twoFactor.LastUsedStep {
+		return errInvalidTwoFactorCode
+	}
+	twoFactor.LastUsedStep = step
+	_, err := u.twoFactorRepo.Update(ctx, twoFactor)
+	return err
+}
+
+func (u *authUsecase) useRecoveryCode(ctx context.Context, userId uint, recoveryCode string) error {
+	recoveryCodes, err := u.recoveryCodeRepo.Find(ctx, valueobject.NewQuery().
+		Condition("user_id", valueobject.Equal, userId).
+		Condition("used_at", valueobject.Is, nil).
+		Lock())
+	if err != nil {
+		return err
+	}
+	normalized := normalizeRecoveryCode(recoveryCode)
+	for _, fetchedCode := range recoveryCodes {
+		if u.hash.Compare(fetchedCode.CodeHash, normalized) {
+			now := time.Now()
+			fetchedCode.UsedAt = &now
+			_, err = u.recoveryCodeRepo.Update(ctx, fetchedCode)
+			return err
+		}
+	}
+	return errInvalidRecoveryCode
+}
+
+func (u *authUsecase) replaceRecoveryCodes(ctx context.Context, userId uint) ([]string, error) {
+	err := u.recoveryCodeRepo.DeleteByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		_, err = rand.Read(b)
+		if err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		hashedCode, err := u.hash.Hash(code)
+		if err != nil {
+			return nil, err
+		}
+		_, err = u.recoveryCodeRepo.Create(ctx, &entity.TwoFactorRecoveryCode{UserId: userId, CodeHash: string(hashedCode)})
+		if err != nil {
+			return nil, err
+		}
+		recoveryCodes = append(recoveryCodes, code[:4]+"-"+code[4:])
+	}
+	return recoveryCodes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
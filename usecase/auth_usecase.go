@@ -8,19 +8,34 @@ import (
 
 	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/appjwt"
+	"github.com/night1010/everhealth/config"
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/hasher"
 	"github.com/night1010/everhealth/imagehelper"
+	"github.com/night1010/everhealth/logger"
 	"github.com/night1010/everhealth/mail"
+	"github.com/night1010/everhealth/rbac"
 	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/throttle"
 	"github.com/night1010/everhealth/transactor"
 	"github.com/night1010/everhealth/valueobject"
 )
 
+const dummyPasswordHash = "$2a$10$JQaW.C8XNWWAH91hkKEO.O/ulXKX5.W6lb/KJY7culi3Ke1KIZJmO"
+
 type AuthUsecase interface {
 	Register(context.Context, *entity.User) error
 	Verify(context.Context, *entity.User, *entity.Profile, *entity.DoctorProfile) error
 	Login(context.Context, *entity.User) (*entity.User, error)
+	Refresh(context.Context, string) (*entity.User, error)
+	Logout(context.Context, string, bool) error
+	VerifyTwoFactorLogin(context.Context, string, string, string) (*entity.User, error)
+	EnrollTwoFactor(context.Context) (*entity.UserTwoFactor, error)
+	EnrollTwoFactorChallenge(context.Context, string) (*entity.UserTwoFactor, error)
+	ActivateTwoFactor(context.Context, string) ([]string, error)
+	ActivateTwoFactorChallenge(context.Context, string, string) (*entity.User, []string, error)
+	DisableTwoFactor(context.Context, string) error
+	RegenerateRecoveryCodes(context.Context, string) ([]string, error)
 	ForgotPassword(context.Context, *entity.User, *entity.ForgotPasswordToken) error
 	ResetPassword(context.Context, *entity.User, *entity.ForgotPasswordToken) error
 }
@@ -31,11 +46,16 @@ type authUsecase struct {
 	profileRepo        repository.ProfileRepository
 	doctorProfileRepo  repository.DoctorProfileRepository
 	forgotPasswordRepo repository.ForgotPasswordRepository
+	refreshTokenRepo   repository.RefreshTokenRepository
+	twoFactorRepo      repository.UserTwoFactorRepository
+	recoveryCodeRepo   repository.TwoFactorRecoveryCodeRepository
 	cartRepo           repository.CartRepository
 	smtpGmail          mail.SmtpGmail
 	hash               hasher.Hasher
 	jwt                appjwt.Jwt
 	imageHelper        imagehelper.ImageHelper
+	attemptUsecase     AuthAttemptUsecase
+	roleResolver       *rbac.Resolver
 }
 
 func NewAuthUsecase(
@@ -44,11 +64,16 @@ func NewAuthUsecase(
 	profileRepo repository.ProfileRepository,
 	doctorProfileRepo repository.DoctorProfileRepository,
 	forgotPasswordRepo repository.ForgotPasswordRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	twoFactorRepo repository.UserTwoFactorRepository,
+	recoveryCodeRepo repository.TwoFactorRecoveryCodeRepository,
 	cartRepo repository.CartRepository,
 	smtpGmail mail.SmtpGmail,
 	hash hasher.Hasher,
 	jwt appjwt.Jwt,
 	imageHelper imagehelper.ImageHelper,
+	attemptUsecase AuthAttemptUsecase,
+	roleResolver *rbac.Resolver,
 ) AuthUsecase {
 	return &authUsecase{
 		manager:            manager,
@@ -56,15 +81,24 @@ func NewAuthUsecase(
 		profileRepo:        profileRepo,
 		doctorProfileRepo:  doctorProfileRepo,
 		forgotPasswordRepo: forgotPasswordRepo,
+		refreshTokenRepo:   refreshTokenRepo,
+		twoFactorRepo:      twoFactorRepo,
+		recoveryCodeRepo:   recoveryCodeRepo,
 		cartRepo:           cartRepo,
 		smtpGmail:          smtpGmail,
 		hash:               hash,
 		jwt:                jwt,
 		imageHelper:        imageHelper,
+		attemptUsecase:     attemptUsecase,
+		roleResolver:       roleResolver,
 	}
 }
 
 func (u *authUsecase) Register(ctx context.Context, user *entity.User) error {
+	err := u.countRequest(ctx, entity.RegisterScope, user.Email)
+	if err != nil {
+		return err
+	}
 	emailQuery := valueobject.NewQuery().Condition("email", valueobject.Equal, user.Email)
 	fetchedUser, err := u.userRepo.FindOne(ctx, emailQuery)
 	if err != nil {
@@ -73,7 +107,7 @@ func (u *authUsecase) Register(ctx context.Context, user *entity.User) error {
 	var token string
 	if fetchedUser != nil {
 		if fetchedUser.IsVerified {
-			return apperror.NewResourceAlreadyExistError("user", "email", user.Email)
+			return u.smtpGmail.SendAccountExistsEmail(fetchedUser.Email)
 		}
 		token = fetchedUser.Token
 	} else {
@@ -157,16 +191,122 @@ func (u *authUsecase) Verify(ctx context.Context, user *entity.User, profile *en
 }
 
 func (u *authUsecase) Login(ctx context.Context, user *entity.User) (*entity.User, error) {
+	ip, _ := ctx.Value("client_ip").(string)
+	err := u.attemptUsecase.Check(ctx, entity.LoginScope, user.Email, ip)
+	if err != nil {
+		return nil, err
+	}
 	emailQuery := valueobject.NewQuery().Condition("email", valueobject.Equal, user.Email)
 	fetchedUser, err := u.userRepo.FindOne(ctx, emailQuery)
 	if err != nil {
 		return nil, err
 	}
 	if fetchedUser == nil {
-		return nil, apperror.NewResourceNotFoundError("user", "email", user.Email)
+		u.hash.Compare(dummyPasswordHash, user.Password)
+		return nil, u.failLogin(ctx, user.Email, nil)
 	}
 	if !(u.hash.Compare(fetchedUser.Password, user.Password)) {
-		return nil, apperror.NewInvalidCredentialsError()
+		return nil, u.failLogin(ctx, user.Email, fetchedUser)
+	}
+	challenge, err := u.twoFactorChallenge(ctx, fetchedUser)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		fetchedUser.Challenge = challenge
+		return fetchedUser, nil
+	}
+	err = u.issueTokens(ctx, fetchedUser)
+	if err != nil {
+		return nil, err
+	}
+	err = u.attemptUsecase.Reset(ctx, entity.LoginScope, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	return fetchedUser, nil
+}
+
+func (u *authUsecase) failLogin(ctx context.Context, email string, fetchedUser *entity.User) error {
+	ip, _ := ctx.Value("client_ip").(string)
+	lockedOut, err := u.attemptUsecase.Fail(ctx, entity.LoginScope, email, ip)
+	if err != nil {
+		return err
+	}
+	if lockedOut && fetchedUser != nil {
+		err = u.smtpGmail.SendLockoutEmail(fetchedUser.Email, throttle.LoginAccountPolicy.LockoutDuration)
+		if err != nil {
+			logger.Log.Error(err)
+		}
+	}
+	return apperror.NewInvalidCredentialsError()
+}
+
+func (u *authUsecase) countRequest(ctx context.Context, scope entity.AuthAttemptScope, email string) error {
+	ip, _ := ctx.Value("client_ip").(string)
+	err := u.attemptUsecase.Check(ctx, scope, email, ip)
+	if err != nil {
+		return err
+	}
+	_, err = u.attemptUsecase.Fail(ctx, scope, email, ip)
+	return err
+}
+
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*entity.User, error) {
+	var fetchedUser *entity.User
+	var reused bool
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		tokenQuery := valueobject.NewQuery().
+			Condition("token_hash", valueobject.Equal, appjwt.HashRefreshToken(refreshToken)).Lock()
+		fetchedToken, err := u.refreshTokenRepo.FindOne(c, tokenQuery)
+		if err != nil {
+			return err
+		}
+		if fetchedToken == nil {
+			return apperror.NewInvalidTokenError()
+		}
+		if fetchedToken.RevokedAt != nil {
+			reused = true
+			return u.refreshTokenRepo.RevokeAllByUserId(c, fetchedToken.UserId)
+		}
+		now := time.Now()
+		if fetchedToken.ExpiredAt.Before(now) {
+			return apperror.NewInvalidTokenError()
+		}
+		fetchedUser, err = u.userRepo.FindById(c, fetchedToken.UserId)
+		if err != nil {
+			return err
+		}
+		if fetchedUser == nil || fetchedUser.TokenVersion != fetchedToken.TokenVersion {
+			return apperror.NewInvalidTokenError()
+		}
+		required, err := u.requiresTwoFactor(c, fetchedUser)
+		if err != nil {
+			return err
+		}
+		if required {
+			twoFactor, err := u.findTwoFactor(c, fetchedUser.Id)
+			if err != nil {
+				return err
+			}
+			if twoFactor == nil || !twoFactor.IsEnabled {
+				return apperror.NewInvalidTokenError()
+			}
+		}
+		newToken, err := u.createRefreshToken(c, fetchedUser)
+		if err != nil {
+			return err
+		}
+		fetchedToken.RevokedAt = &now
+		fetchedToken.ReplacedById = &newToken.Id
+		_, err = u.refreshTokenRepo.Update(c, fetchedToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, apperror.NewInvalidTokenError()
 	}
 	token, err := u.jwt.GenerateToken(fetchedUser)
 	if err != nil {
@@ -176,17 +316,87 @@ func (u *authUsecase) Login(ctx context.Context, user *entity.User) (*entity.Use
 	return fetchedUser, nil
 }
 
+func (u *authUsecase) Logout(ctx context.Context, refreshToken string, allDevices bool) error {
+	return u.manager.Run(ctx, func(c context.Context) error {
+		tokenQuery := valueobject.NewQuery().
+			Condition("token_hash", valueobject.Equal, appjwt.HashRefreshToken(refreshToken)).Lock()
+		fetchedToken, err := u.refreshTokenRepo.FindOne(c, tokenQuery)
+		if err != nil {
+			return err
+		}
+		if fetchedToken == nil {
+			return apperror.NewInvalidTokenError()
+		}
+		if allDevices {
+			userQuery := valueobject.NewQuery().
+				Condition("id", valueobject.Equal, fetchedToken.UserId).Lock()
+			fetchedUser, err := u.userRepo.FindOne(c, userQuery)
+			if err != nil {
+				return err
+			}
+			if fetchedUser == nil {
+				return apperror.NewInvalidTokenError()
+			}
+			fetchedUser.TokenVersion++
+			_, err = u.userRepo.Update(c, fetchedUser)
+			if err != nil {
+				return err
+			}
+			return u.refreshTokenRepo.RevokeAllByUserId(c, fetchedUser.Id)
+		}
+		if fetchedToken.RevokedAt != nil {
+			return nil
+		}
+		now := time.Now()
+		fetchedToken.RevokedAt = &now
+		_, err = u.refreshTokenRepo.Update(c, fetchedToken)
+		return err
+	})
+}
+
+func (u *authUsecase) issueTokens(ctx context.Context, user *entity.User) error {
+	token, err := u.jwt.GenerateToken(user)
+	if err != nil {
+		return err
+	}
+	_, err = u.createRefreshToken(ctx, user)
+	if err != nil {
+		return err
+	}
+	user.Token = token
+	return nil
+}
+
+func (u *authUsecase) createRefreshToken(ctx context.Context, user *entity.User) (*entity.RefreshToken, error) {
+	refreshToken, err := appjwt.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	createdToken, err := u.refreshTokenRepo.Create(ctx, &entity.RefreshToken{
+		UserId:       user.Id,
+		TokenHash:    appjwt.HashRefreshToken(refreshToken),
+		TokenVersion: user.TokenVersion,
+		ExpiredAt:    time.Now().Add(config.NewJwtConfig().RefreshExpiryDuration),
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.RefreshToken = refreshToken
+	return createdToken, nil
+}
+
 func (u *authUsecase) ForgotPassword(ctx context.Context, user *entity.User, tokenEntity *entity.ForgotPasswordToken) error {
+	err := u.countRequest(ctx, entity.ForgotPasswordScope, user.Email)
+	if err != nil {
+		return err
+	}
 	emailQuery := valueobject.NewQuery().Condition("email", valueobject.Equal, user.Email)
 	fetchedUser, err := u.userRepo.FindOne(ctx, emailQuery)
 	if err != nil {
 		return err
 	}
-	if fetchedUser == nil {
-		return apperror.NewResourceNotFoundError("user", "email", user.Email)
-	}
-	if !fetchedUser.IsVerified {
-		return apperror.NewResourceNotFoundError("user", "email", user.Email)
+	if fetchedUser == nil || !fetchedUser.IsVerified {
+		return nil
 	}
 	hashedToken, err := u.hash.Hash(user.Email)
 	if err != nil {
@@ -237,6 +447,7 @@ func (u *authUsecase) ResetPassword(ctx context.Context, user *entity.User, toke
 			return err
 		}
 		fetchedUser.Password = string(hashPass)
+		fetchedUser.TokenVersion++
 		fetchedTokenEntity.IsActive = false
 		_, err = u.userRepo.Update(c, fetchedUser)
 		if err != nil {
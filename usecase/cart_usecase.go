@@ -17,6 +17,7 @@ type CartUsecase interface {
 	DeleteItem(context.Context, uint) error
 	CheckItem(context.Context, uint, bool) error
 	CheckAllItem(context.Context, bool) error
+	AddPrescriptionItems(context.Context, uint) error
 }
 
 type cartUsecase struct {
@@ -25,6 +26,8 @@ type cartUsecase struct {
 	cartItemRepo        repository.CartItemRepository
 	productRepo         repository.ProductRepository
 	pharmacyProductRepo repository.PharmacyProductRepository
+	telemedicineRepo    repository.TelemedicineRepository
+	prescriptionRepo    repository.PrescriptionItemRepository
 }
 
 func NewCartUsecase(
@@ -33,6 +36,8 @@ func NewCartUsecase(
 	cartItemRepo repository.CartItemRepository,
 	productRepo repository.ProductRepository,
 	pharmacyProductRepo repository.PharmacyProductRepository,
+	telemedicineRepo repository.TelemedicineRepository,
+	prescriptionRepo repository.PrescriptionItemRepository,
 ) CartUsecase {
 	return &cartUsecase{
 		manager:             manager,
@@ -40,6 +45,8 @@ func NewCartUsecase(
 		cartItemRepo:        cartItemRepo,
 		productRepo:         productRepo,
 		pharmacyProductRepo: pharmacyProductRepo,
+		telemedicineRepo:    telemedicineRepo,
+		prescriptionRepo:    prescriptionRepo,
 	}
 }
 
@@ -178,3 +185,31 @@ func (u *cartUsecase) CheckItem(ctx context.Context, itemId uint, check bool) er
 func (u *cartUsecase) CheckAllItem(ctx context.Context, check bool) error {
 	return u.cartItemRepo.CheckAllItem(ctx, check)
 }
+
+func (u *cartUsecase) AddPrescriptionItems(ctx context.Context, telemedicineId uint) error {
+	userId := ctx.Value("user_id").(uint)
+	fetchedTelemedicine, err := u.telemedicineRepo.FindById(ctx, telemedicineId)
+	if err != nil {
+		return err
+	}
+	if fetchedTelemedicine == nil || fetchedTelemedicine.ProfileId != userId {
+		return apperror.NewResourceNotFoundError("telemedicine", "id", telemedicineId)
+	}
+	prescriptionQuery := valueobject.NewQuery().Condition("telemedicine_id", valueobject.Equal, telemedicineId)
+	fetchedItems, err := u.prescriptionRepo.Find(ctx, prescriptionQuery)
+	if err != nil {
+		return err
+	}
+	if len(fetchedItems) == 0 {
+		return apperror.NewResourceStateError("telemedicine has no prescription")
+	}
+	return u.manager.Run(ctx, func(c context.Context) error {
+		for _, item := range fetchedItems {
+			err := u.AddItem(c, &entity.CartItem{ProductId: item.ProductId, Quantity: item.Quantity, IsChecked: true})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -2,26 +2,65 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/imagehelper"
 	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+const (
+	maxChatMessageLength = 2000
+	chatPresenceTTL      = 30 * time.Second
 )
 
 type ChatUsecase interface {
 	ValidateTelemedicine(ctx context.Context, telemedicineId uint) (bool, error)
+	ValidateQueue(ctx context.Context, doctorId uint) (bool, error)
 	AddChatMessage(ctx context.Context, chat *entity.Chat) (*entity.Chat, error)
+	AddAttachment(ctx context.Context, chat *entity.Chat) (*entity.Chat, error)
+	FindChatHistory(ctx context.Context, telemedicineId uint, before *uint, limit int) ([]*entity.Chat, *uint, error)
+	UpdateReceipt(ctx context.Context, receipt *entity.ChatReceipt) (*entity.ChatReceipt, error)
+	UpdateLastSeen(ctx context.Context, userId uint, lastSeenAt time.Time) error
+	SetDoctorOffline(ctx context.Context, doctorId uint) error
+	JoinPresence(ctx context.Context, presence *entity.ChatPresence) ([]uint, bool, error)
+	RefreshPresence(ctx context.Context, presence *entity.ChatPresence) error
+	LeavePresence(ctx context.Context, presence *entity.ChatPresence) (bool, error)
+	IsOnline(ctx context.Context, userId uint) (bool, error)
+	SweepPresence(ctx context.Context) error
 }
 
 type chatUsecase struct {
+	manager          transactor.Manager
+	imageHelper      imagehelper.ImageHelper
 	chatRepo         repository.ChatRepository
+	receiptRepo      repository.ChatReceiptRepository
 	telemedicineRepo repository.TelemedicineRepository
+	profileRepo      repository.ProfileRepository
+	doctorRepo       repository.DoctorProfileRepository
+	presenceRepo     repository.ChatPresenceRepository
+	queueRepo        repository.TelemedicineQueueRepository
 }
 
-func NewChatUsecase(chatRepo repository.ChatRepository, telemedicineRepo repository.TelemedicineRepository) ChatUsecase {
+func NewChatUsecase(manager transactor.Manager, imageHelper imagehelper.ImageHelper, chatRepo repository.ChatRepository, receiptRepo repository.ChatReceiptRepository, telemedicineRepo repository.TelemedicineRepository, profileRepo repository.ProfileRepository, doctorRepo repository.DoctorProfileRepository, presenceRepo repository.ChatPresenceRepository, queueRepo repository.TelemedicineQueueRepository) ChatUsecase {
 	return &chatUsecase{
+		manager:          manager,
+		imageHelper:      imageHelper,
 		chatRepo:         chatRepo,
+		receiptRepo:      receiptRepo,
 		telemedicineRepo: telemedicineRepo,
+		profileRepo:      profileRepo,
+		doctorRepo:       doctorRepo,
+		presenceRepo:     presenceRepo,
+		queueRepo:        queueRepo,
 	}
 }
 
@@ -43,6 +82,219 @@ func (u *chatUsecase) ValidateTelemedicine(ctx context.Context, telemedicineId u
 	return false, nil
 }
 
+func (u *chatUsecase) ValidateQueue(ctx context.Context, doctorId uint) (bool, error) {
+	userId := ctx.Value("user_id").(uint)
+	roleId := ctx.Value("role_id").(entity.RoleId)
+	if roleId != entity.RoleUser {
+		return false, nil
+	}
+
+	fetchedQueue, err := u.queueRepo.FindOne(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctorId).
+		Condition("profile_id", valueobject.Equal, userId).
+		Condition("status", valueobject.Equal, entity.QueueWaiting))
+	if err != nil {
+		return false, err
+	}
+
+	return fetchedQueue != nil, nil
+}
+
 func (u *chatUsecase) AddChatMessage(ctx context.Context, chat *entity.Chat) (*entity.Chat, error) {
+	message := strings.TrimSpace(chat.Message)
+	if message == "" {
+		return nil, apperror.NewClientError(errors.New("message cannot be empty"))
+	}
+	if utf8.RuneCountInString(message) > maxChatMessageLength {
+		return nil, apperror.NewClientError(fmt.Errorf("message cannot be longer than %d characters", maxChatMessageLength))
+	}
+	if err := u.validateChatRoom(ctx, chat); err != nil {
+		return nil, err
+	}
+	chat.Message = message
 	return u.chatRepo.Create(ctx, chat)
 }
+
+func (u *chatUsecase) AddAttachment(ctx context.Context, chat *entity.Chat) (*entity.Chat, error) {
+	image, _ := ctx.Value("image").(multipart.File)
+	pdf, _ := ctx.Value("pdf").(multipart.File)
+	if (image == nil) == (pdf == nil) {
+		return nil, apperror.NewClientError(errors.New("attachment must be either one image or one pdf"))
+	}
+	file := image
+	chat.MessageType = entity.MessageTypeImage
+	if pdf != nil {
+		file = pdf
+		chat.MessageType = entity.MessageTypePdf
+	}
+	if err := u.validateChatRoom(ctx, chat); err != nil {
+		return nil, err
+	}
+	attachmentKey := entity.ChatAttachmentPrefix + generateRandomString(10)
+	attachmentUrl, err := u.imageHelper.Upload(ctx, file, entity.ChatAttachmentFolder, attachmentKey)
+	if err != nil {
+		return nil, err
+	}
+	chat.Message = attachmentUrl
+	chat.ChatTime = time.Now()
+	createdChat, err := u.chatRepo.Create(ctx, chat)
+	if err != nil {
+		err2 := u.imageHelper.Destroy(ctx, entity.ChatAttachmentFolder, attachmentKey)
+		if err2 != nil {
+			return nil, err2
+		}
+		return nil, err
+	}
+	return createdChat, nil
+}
+
+func (u *chatUsecase) validateChatRoom(ctx context.Context, chat *entity.Chat) error {
+	telemedicine, err := u.telemedicineRepo.FindById(ctx, chat.TelemedicineId)
+	if err != nil {
+		return err
+	}
+	if telemedicine == nil || (telemedicine.ProfileId != chat.UserId && telemedicine.DoctorId != chat.UserId) {
+		return apperror.NewResourceNotFoundError("telemedicine", "id", chat.TelemedicineId)
+	}
+	if telemedicine.Status != entity.Ongoing {
+		return apperror.NewResourceStateError("telemedicine is not ongoing")
+	}
+	return nil
+}
+
+func (u *chatUsecase) FindChatHistory(ctx context.Context, telemedicineId uint, before *uint, limit int) ([]*entity.Chat, *uint, error) {
+	isValid, err := u.ValidateTelemedicine(ctx, telemedicineId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isValid {
+		return nil, nil, apperror.NewForbiddenActionError("can't read chat of other telemedicine")
+	}
+	query := valueobject.NewQuery().
+		Condition("telemedicine_id", valueobject.Equal, telemedicineId).
+		WithSortBy("id").
+		WithOrder(valueobject.OrderDesc).
+		WithLimit(limit + 1)
+	if before != nil {
+		query.Condition("id", valueobject.LessThan, *before)
+	}
+	chats, err := u.chatRepo.Find(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(chats) <= limit {
+		return chats, nil, nil
+	}
+	chats = chats[:limit]
+	return chats, &chats[limit-1].Id, nil
+}
+
+func (u *chatUsecase) UpdateReceipt(ctx context.Context, receipt *entity.ChatReceipt) (*entity.ChatReceipt, error) {
+	messageId := receipt.DeliveredUpTo
+	if receipt.ReadUpTo > messageId {
+		messageId = receipt.ReadUpTo
+	}
+	chat, err := u.chatRepo.FindOne(ctx, valueobject.NewQuery().
+		Condition("id", valueobject.Equal, messageId).
+		Condition("telemedicine_id", valueobject.Equal, receipt.TelemedicineId))
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, apperror.NewResourceNotFoundError("message", "id", messageId)
+	}
+	return u.receiptRepo.Upsert(ctx, receipt)
+}
+
+func (u *chatUsecase) UpdateLastSeen(ctx context.Context, userId uint, lastSeenAt time.Time) error {
+	return u.profileRepo.UpdateLastSeen(ctx, userId, lastSeenAt)
+}
+
+func (u *chatUsecase) SetDoctorOffline(ctx context.Context, doctorId uint) error {
+	return u.manager.Run(ctx, func(c context.Context) error {
+		doctor, err := u.doctorRepo.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, doctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if doctor == nil || doctor.Status != entity.Online {
+			return nil
+		}
+		doctor.Status = entity.Offline
+		_, err = u.doctorRepo.Update(c, doctor)
+		return err
+	})
+}
+
+func (u *chatUsecase) JoinPresence(ctx context.Context, presence *entity.ChatPresence) ([]uint, bool, error) {
+	err := u.RefreshPresence(ctx, presence)
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now()
+	online, err := u.presenceRepo.FindActiveUserIds(ctx, presence.TelemedicineId, now)
+	if err != nil {
+		return nil, false, err
+	}
+	connections, err := u.presenceRepo.CountActive(ctx, presence.UserId, &presence.TelemedicineId, now)
+	if err != nil {
+		return nil, false, err
+	}
+	return online, connections == 1, nil
+}
+
+func (u *chatUsecase) RefreshPresence(ctx context.Context, presence *entity.ChatPresence) error {
+	presence.ExpiredAt = time.Now().Add(chatPresenceTTL)
+	_, err := u.presenceRepo.Upsert(ctx, presence)
+	return err
+}
+
+func (u *chatUsecase) LeavePresence(ctx context.Context, presence *entity.ChatPresence) (bool, error) {
+	err := u.presenceRepo.DeleteByClient(ctx, presence.ClientId)
+	if err != nil {
+		return false, err
+	}
+	connections, err := u.presenceRepo.CountActive(ctx, presence.UserId, &presence.TelemedicineId, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return connections == 0, nil
+}
+
+func (u *chatUsecase) IsOnline(ctx context.Context, userId uint) (bool, error) {
+	connections, err := u.presenceRepo.CountActive(ctx, userId, nil, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return connections > 0, nil
+}
+
+func (u *chatUsecase) SweepPresence(ctx context.Context) error {
+	expired, err := u.presenceRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	lastSeenM := make(map[uint]time.Time)
+	for _, presence := range expired {
+		if presence.ExpiredAt.After(lastSeenM[presence.UserId]) {
+			lastSeenM[presence.UserId] = presence.ExpiredAt
+		}
+	}
+	for userId, lastSeenAt := range lastSeenM {
+		online, err := u.IsOnline(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if online {
+			continue
+		}
+		err = u.UpdateLastSeen(ctx, userId, lastSeenAt)
+		if err != nil {
+			return err
+		}
+		err = u.SetDoctorOffline(ctx, userId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type DoctorReviewUsecase interface {
+	CreateReview(ctx context.Context, review *entity.DoctorReview) (*entity.DoctorReview, error)
+	ListReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	ModerateReview(ctx context.Context, review *entity.DoctorReview) (*entity.DoctorReview, error)
+}
+
+type doctorReviewUsecase struct {
+	manager                transactor.Manager
+	reviewRepository       repository.DoctorReviewRepository
+	doctorRepository       repository.DoctorProfileRepository
+	telemedicineRepository repository.TelemedicineRepository
+}
+
+func NewDoctorReviewUsecase(m transactor.Manager, rr repository.DoctorReviewRepository, dr repository.DoctorProfileRepository, tr repository.TelemedicineRepository) DoctorReviewUsecase {
+	return &doctorReviewUsecase{manager: m, reviewRepository: rr, doctorRepository: dr, telemedicineRepository: tr}
+}
+
+func (u *doctorReviewUsecase) CreateReview(ctx context.Context, review *entity.DoctorReview) (*entity.DoctorReview, error) {
+	profileId := ctx.Value("user_id").(uint)
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		telemedicine, err := u.telemedicineRepository.FindById(c, review.TelemedicineId)
+		if err != nil {
+			return err
+		}
+		if telemedicine == nil || telemedicine.ProfileId != profileId {
+			return apperror.NewResourceNotFoundError("telemedicine", "id", review.TelemedicineId)
+		}
+		if telemedicine.Status != entity.End {
+			return apperror.NewClientError(fmt.Errorf("cannot review telemedicine because it is %v", telemedicine.Status))
+		}
+		doctor, err := u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, telemedicine.DoctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if doctor == nil {
+			return apperror.NewResourceNotFoundError("doctor", "id", telemedicine.DoctorId)
+		}
+		checkReview, err := u.reviewRepository.FindOne(c, valueobject.NewQuery().Condition("telemedicine_id", valueobject.Equal, telemedicine.Id))
+		if err != nil {
+			return err
+		}
+		if checkReview != nil {
+			return apperror.NewClientError(errors.New("telemedicine already reviewed"))
+		}
+		review.DoctorId = telemedicine.DoctorId
+		review.ProfileId = profileId
+		_, err = u.reviewRepository.Create(c, review)
+		if err != nil {
+			return err
+		}
+		return u.updateDoctorRating(c, doctor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (u *doctorReviewUsecase) ListReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return u.reviewRepository.FindAllReviews(ctx, query)
+}
+
+func (u *doctorReviewUsecase) ModerateReview(ctx context.Context, review *entity.DoctorReview) (*entity.DoctorReview, error) {
+	var fetchedReview *entity.DoctorReview
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		var err error
+		fetchedReview, err = u.reviewRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, review.Id).Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedReview == nil {
+			return apperror.NewResourceNotFoundError("review", "id", review.Id)
+		}
+		if fetchedReview.IsHidden == review.IsHidden {
+			return nil
+		}
+		doctor, err := u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, fetchedReview.DoctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if doctor == nil {
+			return apperror.NewResourceNotFoundError("doctor", "id", fetchedReview.DoctorId)
+		}
+		fetchedReview.IsHidden = review.IsHidden
+		_, err = u.reviewRepository.Update(c, fetchedReview)
+		if err != nil {
+			return err
+		}
+		return u.updateDoctorRating(c, doctor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fetchedReview, nil
+}
+
+func (u *doctorReviewUsecase) updateDoctorRating(ctx context.Context, doctor *entity.DoctorProfile) error {
+	average, count, err := u.reviewRepository.FindRatingSummary(ctx, doctor.ProfileId)
+	if err != nil {
+		return err
+	}
+	doctor.RatingAverage = average
+	doctor.ReviewCount = count
+	_, err = u.doctorRepository.Update(ctx, doctor)
+	return err
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/appointment"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type DoctorScheduleUsecase interface {
+	FindAvailabilities(ctx context.Context) ([]*entity.DoctorAvailability, error)
+	FindExceptions(ctx context.Context) ([]*entity.DoctorAvailabilityException, error)
+	UpdateAvailabilities(ctx context.Context, availabilities []*entity.DoctorAvailability) ([]*entity.DoctorAvailability, error)
+	CreateException(ctx context.Context, exception *entity.DoctorAvailabilityException) (*entity.DoctorAvailabilityException, error)
+	DeleteException(ctx context.Context, exceptionId uint) error
+	FindAvailableSlots(ctx context.Context, doctorId uint, date time.Time) ([]*appointment.Slot, error)
+}
+
+type doctorScheduleUsecase struct {
+	manager                transactor.Manager
+	doctorRepository       repository.DoctorProfileRepository
+	availabilityRepository repository.DoctorAvailabilityRepository
+	exceptionRepository    repository.DoctorAvailabilityExceptionRepository
+	telemedicineRepository repository.TelemedicineRepository
+}
+
+func NewDoctorScheduleUsecase(m transactor.Manager, dr repository.DoctorProfileRepository, ar repository.DoctorAvailabilityRepository, er repository.DoctorAvailabilityExceptionRepository, tr repository.TelemedicineRepository) DoctorScheduleUsecase {
+	return &doctorScheduleUsecase{manager: m, doctorRepository: dr, availabilityRepository: ar, exceptionRepository: er, telemedicineRepository: tr}
+}
+
+func (u *doctorScheduleUsecase) FindAvailabilities(ctx context.Context) ([]*entity.DoctorAvailability, error) {
+	query := valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, ctx.Value("user_id").(uint)).
+		WithSortBy("weekday, start_time")
+	return u.availabilityRepository.Find(ctx, query)
+}
+
+func (u *doctorScheduleUsecase) FindExceptions(ctx context.Context) ([]*entity.DoctorAvailabilityException, error) {
+	query := valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, ctx.Value("user_id").(uint)).
+		Condition("date", valueobject.GreaterThanEqual, time.Now().Format("2006-01-02")).
+		WithSortBy("date")
+	return u.exceptionRepository.Find(ctx, query)
+}
+
+func (u *doctorScheduleUsecase) UpdateAvailabilities(ctx context.Context, availabilities []*entity.DoctorAvailability) ([]*entity.DoctorAvailability, error) {
+	doctorId := ctx.Value("user_id").(uint)
+	sort.Slice(availabilities, func(i, j int) bool {
+		if availabilities[i].Weekday != availabilities[j].Weekday {
+			return availabilities[i].Weekday < availabilities[j].Weekday
+		}
+		return availabilities[i].StartTime.Before(availabilities[j].StartTime)
+	})
+	for i, availability := range availabilities {
+		if availability.EndTime.Sub(availability.StartTime) < appointment.SlotDuration {
+			return nil, apperror.NewClientError(fmt.Errorf("availability on %v must be at least %v long", availability.Weekday, appointment.SlotDuration))
+		}
+		if i > 0 && availabilities[i-1].Weekday == availability.Weekday && availability.StartTime.Before(availabilities[i-1].EndTime) {
+			return nil, apperror.NewClientError(fmt.Errorf("availabilities on %v overlap", availability.Weekday))
+		}
+		availability.DoctorId = doctorId
+	}
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		doctor, err := u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, doctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if doctor == nil {
+			return apperror.NewResourceNotFoundError("doctor", "id", doctorId)
+		}
+		err = u.availabilityRepository.DeleteByDoctorId(c, doctorId)
+		if err != nil {
+			return err
+		}
+		for _, availability := range availabilities {
+			_, err = u.availabilityRepository.Create(c, availability)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return availabilities, nil
+}
+
+func (u *doctorScheduleUsecase) CreateException(ctx context.Context, exception *entity.DoctorAvailabilityException) (*entity.DoctorAvailabilityException, error) {
+	if exception.Date.Before(appointment.StartOfDay(time.Now())) {
+		return nil, apperror.NewClientError(errors.New("cannot add exception for past date"))
+	}
+	if (exception.StartTime == nil) != (exception.EndTime == nil) {
+		return nil, apperror.NewClientError(errors.New("start time and end time must be filled together"))
+	}
+	if exception.IsAvailable && exception.StartTime == nil {
+		return nil, apperror.NewClientError(errors.New("available exception must have start time and end time"))
+	}
+	if exception.StartTime != nil && exception.EndTime.Sub(*exception.StartTime) < appointment.SlotDuration {
+		return nil, apperror.NewClientError(fmt.Errorf("exception must be at least %v long", appointment.SlotDuration))
+	}
+	exception.DoctorId = ctx.Value("user_id").(uint)
+	return u.exceptionRepository.Create(ctx, exception)
+}
+
+func (u *doctorScheduleUsecase) DeleteException(ctx context.Context, exceptionId uint) error {
+	exception, err := u.exceptionRepository.FindById(ctx, exceptionId)
+	if err != nil {
+		return err
+	}
+	if exception == nil || exception.DoctorId != ctx.Value("user_id").(uint) {
+		return apperror.NewResourceNotFoundError("availability exception", "id", exceptionId)
+	}
+	return u.exceptionRepository.Delete(ctx, exception)
+}
+
+func (u *doctorScheduleUsecase) FindAvailableSlots(ctx context.Context, doctorId uint, date time.Time) ([]*appointment.Slot, error) {
+	doctor, err := u.doctorRepository.FindOne(ctx, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, doctorId))
+	if err != nil {
+		return nil, err
+	}
+	if doctor == nil {
+		return nil, apperror.NewResourceNotFoundError("doctor", "id", doctorId)
+	}
+	date = appointment.StartOfDay(date)
+	availabilities, err := u.availabilityRepository.Find(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctorId).
+		Condition("weekday", valueobject.Equal, date.Weekday()))
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := u.exceptionRepository.Find(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctorId).
+		Condition("date", valueobject.Equal, date.Format("2006-01-02")))
+	if err != nil {
+		return nil, err
+	}
+	telemedicines, err := u.telemedicineRepository.Find(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctorId).
+		Condition("scheduled_at", valueobject.GreaterThanEqual, date).
+		Condition("scheduled_at", valueobject.LessThan, date.AddDate(0, 0, 1)).
+		Condition("status", valueobject.NotEqual, entity.Cancel))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var booked []*appointment.Slot
+	for _, telemedicine := range telemedicines {
+		if telemedicine.Status == entity.Waiting && telemedicine.ExpiredAt.Before(now) {
+			continue
+		}
+		booked = append(booked, &appointment.Slot{StartAt: *telemedicine.ScheduledAt, EndAt: *telemedicine.ScheduledEndAt})
+	}
+	return appointment.Slots(&appointment.Day{
+		Date:           date,
+		Availabilities: availabilities,
+		Exceptions:     exceptions,
+		Booked:         booked,
+		Now:            now,
+	}), nil
+}
@@ -0,0 +1,462 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/payment"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type orderTransitionHook func(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error
+
+type orderTransition struct {
+	from  entity.StatusOrder
+	to    entity.StatusOrder
+	roles []entity.RoleId
+	hooks []orderTransitionHook
+}
+
+func (u *orderUsecase) newOrderTransitions() []*orderTransition {
+	return []*orderTransition{
+		{
+			from:  entity.WaitingForPayment,
+			to:    entity.WaitingForPaymentConfirmation,
+			roles: []entity.RoleId{entity.RoleUser, entity.RoleSystem},
+			hooks: []orderTransitionHook{u.keepShipmentStock},
+		},
+		{
+			from:  entity.WaitingForPayment,
+			to:    entity.Canceled,
+			roles: []entity.RoleId{entity.RoleUser, entity.RoleAdmin, entity.RoleSystem},
+			hooks: []orderTransitionHook{u.releaseShipmentStock},
+		},
+		{
+			from:  entity.WaitingForPaymentConfirmation,
+			to:    entity.Processed,
+			roles: []entity.RoleId{entity.RoleAdmin},
+			hooks: []orderTransitionHook{u.requireApprovedPrescription, u.deductShipmentStock, u.confirmManualPayment},
+		},
+		{
+			from:  entity.WaitingForPaymentConfirmation,
+			to:    entity.Canceled,
+			roles: []entity.RoleId{entity.RoleAdmin},
+			hooks: []orderTransitionHook{u.releaseShipmentStock, u.refundShipment},
+		},
+		{
+			from:  entity.Processed,
+			to:    entity.Sent,
+			roles: []entity.RoleId{entity.RoleAdmin},
+		},
+		{
+			from:  entity.Processed,
+			to:    entity.Canceled,
+			roles: []entity.RoleId{entity.RoleAdmin},
+			hooks: []orderTransitionHook{u.restockShipment, u.refundShipment},
+		},
+		{
+			from:  entity.Sent,
+			to:    entity.OrderConfirmed,
+			roles: []entity.RoleId{entity.RoleUser, entity.RoleSystem},
+		},
+	}
+}
+
+func (u *orderUsecase) transitionOrder(c context.Context, order *entity.ProductOrder, to entity.StatusOrder, reason string) error {
+	shipmentQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, order.Id).
+		Condition("order_status_id", valueobject.NotEqual, entity.Canceled).
+		WithSortBy("id")
+	fetchedShipments, err := u.orderShipmentRepo.Find(c, shipmentQuery)
+	if err != nil {
+		return err
+	}
+	for _, shipment := range fetchedShipments {
+		err = u.transitionShipment(c, order, shipment, to, reason)
+		if err != nil {
+			return err
+		}
+	}
+	return u.syncOrderStatus(c, order, reason)
+}
+
+func (u *orderUsecase) transitionShipment(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment, to entity.StatusOrder, reason string) error {
+	from := entity.StatusOrder(shipment.OrderStatusId)
+	var transition *orderTransition
+	for _, t := range u.transitions {
+		if t.from == from && t.to == to {
+			transition = t
+			break
+		}
+	}
+	if transition == nil {
+		return apperror.NewClientError(apperror.NewResourceStateError(fmt.Sprintf("cant update order status from %d to %d", from, to)))
+	}
+	allowed := false
+	role := actorRole(c)
+	for _, r := range transition.roles {
+		if r == role {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return apperror.NewForbiddenActionError("cannot update order to this status")
+	}
+	for _, hook := range transition.hooks {
+		err := hook(c, order, shipment)
+		if err != nil {
+			return err
+		}
+	}
+	shipment.OrderStatusId = uint(to)
+	_, err := u.orderShipmentRepo.Update(c, shipment)
+	if err != nil {
+		return err
+	}
+	fromId := uint(from)
+	return u.recordOrderStatus(c, order.Id, &shipment.Id, &fromId, uint(to), reason)
+}
+
+func (u *orderUsecase) syncOrderStatus(c context.Context, order *entity.ProductOrder, reason string) error {
+	shipmentQuery := valueobject.NewQuery().Condition("order_id", valueobject.Equal, order.Id)
+	fetchedShipments, err := u.orderShipmentRepo.Find(c, shipmentQuery)
+	if err != nil {
+		return err
+	}
+	status := uint(entity.Canceled)
+	for _, shipment := range fetchedShipments {
+		if shipment.OrderStatusId < status {
+			status = shipment.OrderStatusId
+		}
+	}
+	from := order.OrderStatusId
+	order.OrderStatusId = status
+	_, err = u.productOrderRepo.Update(c, order)
+	if err != nil {
+		return err
+	}
+	if from == status {
+		return nil
+	}
+	if status == uint(entity.Canceled) {
+		err = u.voucherUsecase.ReleaseVoucher(c, order)
+		if err != nil {
+			return err
+		}
+		err = u.failManualPayment(c, order)
+		if err != nil {
+			return err
+		}
+	}
+	return u.recordOrderStatus(c, order.Id, nil, &from, status, reason)
+}
+
+func (u *orderUsecase) recordOrderStatus(c context.Context, orderId uint, shipmentId *uint, from *uint, to uint, reason string) error {
+	history := &entity.OrderStatusHistory{
+		OrderId:      orderId,
+		ShipmentId:   shipmentId,
+		FromStatusId: from,
+		ToStatusId:   to,
+		ActorRoleId:  actorRole(c),
+		Reason:       reason,
+	}
+	if userId, ok := c.Value("user_id").(uint); ok {
+		history.ActorId = &userId
+	}
+	_, err := u.statusHistoryRepo.Create(c, history)
+	return err
+}
+
+func actorRole(ctx context.Context) entity.RoleId {
+	if roleId, ok := ctx.Value("role_id").(entity.RoleId); ok {
+		return roleId
+	}
+	return entity.RoleSystem
+}
+
+func (u *orderUsecase) findPaidPayment(c context.Context, orderId uint) (*entity.Payment, error) {
+	paymentQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, orderId).
+		Condition("status", valueobject.Equal, entity.PaymentPaid).
+		Lock()
+	return u.paymentRepo.FindOne(c, paymentQuery)
+}
+
+func (u *orderUsecase) findPendingManualPayment(c context.Context, orderId uint) (*entity.Payment, error) {
+	paymentQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, orderId).
+		Condition("provider", valueobject.Equal, payment.ManualMethod).
+		Condition("status", valueobject.Equal, entity.PaymentPending).
+		Lock()
+	return u.paymentRepo.FindOne(c, paymentQuery)
+}
+
+func (u *orderUsecase) recordManualPayment(c context.Context, order *entity.ProductOrder) error {
+	paidPayment, err := u.findPaidPayment(c, order.Id)
+	if err != nil {
+		return err
+	}
+	pendingPayment, err := u.findPendingManualPayment(c, order.Id)
+	if err != nil {
+		return err
+	}
+	if paidPayment != nil || pendingPayment != nil {
+		return nil
+	}
+	manualPayment, err := chargeManually(c, &entity.Payment{
+		ProfileId:   order.ProfileId,
+		Reference:   fmt.Sprintf("order-%d-%s", order.Id, generateRandomString(8)),
+		PaymentType: entity.PaymentTypeOrder,
+		OrderId:     &order.Id,
+		Amount:      order.TotalPayment,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = u.paymentRepo.Create(c, manualPayment)
+	return err
+}
+
+func (u *orderUsecase) confirmManualPayment(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	pendingPayment, err := u.findPendingManualPayment(c, order.Id)
+	if err != nil {
+		return err
+	}
+	if pendingPayment == nil {
+		return nil
+	}
+	paidAt := time.Now()
+	pendingPayment.Status = entity.PaymentPaid
+	pendingPayment.PaidAt = &paidAt
+	_, err = u.paymentRepo.Update(c, pendingPayment)
+	return err
+}
+
+func (u *orderUsecase) failManualPayment(c context.Context, order *entity.ProductOrder) error {
+	pendingPayment, err := u.findPendingManualPayment(c, order.Id)
+	if err != nil {
+		return err
+	}
+	if pendingPayment == nil {
+		return nil
+	}
+	pendingPayment.Status = entity.PaymentFailed
+	_, err = u.paymentRepo.Update(c, pendingPayment)
+	return err
+}
+
+func (u *orderUsecase) keepShipmentStock(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	return u.reservationRepo.KeepByShipment(c, shipment.Id)
+}
+
+func (u *orderUsecase) releaseShipmentStock(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	return u.reservationRepo.UpdateStatusByShipment(c, shipment.Id, entity.ReservationHeld, entity.ReservationReleased)
+}
+
+func (u *orderUsecase) requireApprovedPrescription(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	if shipment.PrescriptionStatus == entity.PrescriptionNotRequired || shipment.PrescriptionStatus == entity.PrescriptionApproved {
+		return nil
+	}
+	return apperror.NewResourceStateError(fmt.Sprintf("prescription for shipment %d has not been approved", shipment.Id))
+}
+
+func (u *orderUsecase) refundShipment(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	paidPayment, err := u.findPaidPayment(c, order.Id)
+	if err != nil {
+		return err
+	}
+	if paidPayment == nil {
+		return nil
+	}
+	refunded, err := u.refundRepo.SumAmountByShipment(c, shipment.Id)
+	if err != nil {
+		return err
+	}
+	amount := shipment.SubTotal.Sub(shipment.Discount).Add(shipment.ShippingPrice).Sub(shipment.ShippingDiscount).Sub(refunded)
+	if !amount.IsPositive() {
+		return nil
+	}
+	_, err = u.refundRepo.Create(c, &entity.Refund{
+		ProfileId:   order.ProfileId,
+		PaymentId:   &paidPayment.Id,
+		PaymentType: entity.PaymentTypeOrder,
+		OrderId:     &order.Id,
+		ShipmentId:  &shipment.Id,
+		Amount:      amount,
+		Reason:      "shipment canceled by pharmacy",
+		Status:      entity.RefundRequested,
+	})
+	return err
+}
+
+func (u *orderUsecase) deductShipmentStock(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	fetchedOrderItem, err := u.orderItemRepo.ListOfOrderItem(c, shipment.Id)
+	if err != nil {
+		return err
+	}
+	if len(fetchedOrderItem) == 0 {
+		return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
+	}
+	pharmacyProductM := make(map[uint]*entity.PharmacyProduct)
+	nearestPharmacyLoc := fetchedOrderItem[0].PharmacyProduct.Pharmacy.Location
+	var listOfProductId []uint
+	var stockMutations []*entity.StockMutation
+	var stockRecords []*entity.StockRecord
+	for _, item := range fetchedOrderItem {
+		lockedPP, err := u.pharmacyProductRepo.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, item.PharmacyProductId).Lock())
+		if err != nil {
+			return err
+		}
+		if lockedPP == nil {
+			return apperror.NewResourceStateError(fmt.Sprintf("pharmacy product %d no longer exists", item.PharmacyProductId))
+		}
+		item.PharmacyProduct.Stock = lockedPP.Stock
+		if item.PharmacyProduct.Stock >= item.Quantity {
+			item.PharmacyProduct.Stock -= item.Quantity
+			_, err := u.pharmacyProductRepo.Update(c, &item.PharmacyProduct)
+			if err != nil {
+				return err
+			}
+		} else {
+			item.PharmacyProduct.Stock = item.Quantity - item.PharmacyProduct.Stock
+			pharmacyProductM[item.PharmacyProduct.ProductId] = &item.PharmacyProduct
+			listOfProductId = append(listOfProductId, item.PharmacyProduct.ProductId)
+		}
+	}
+	if len(listOfProductId) != 0 {
+		fetchedPP, err := u.pharmacyProductRepo.FindNearbyProductOrder(c, listOfProductId, nearestPharmacyLoc)
+		if err != nil {
+			return err
+		}
+		if len(fetchedPP) == 0 {
+			return apperror.NewResourceStateError("No nearest pharmacy found, order cancelled")
+		}
+		var listOfNearbyPPId []uint
+		for _, pp := range fetchedPP {
+			listOfNearbyPPId = append(listOfNearbyPPId, pp.Id)
+		}
+		heldM, err := u.reservationRepo.FindHeldQuantity(c, listOfNearbyPPId)
+		if err != nil {
+			return err
+		}
+		for key, value := range pharmacyProductM {
+			for _, pp := range fetchedPP {
+				if (pp.ProductId == key) && (pp.Stock-heldM[pp.Id] >= value.Stock) && (value.PharmacyId != pp.PharmacyId) {
+					sm, r1, r2 := createStockMutation(pp.Id, value.Id, value.Stock)
+					sm.OrderId = order.Id
+					stockMutations = append(stockMutations, sm)
+					stockRecords = append(stockRecords, r1, r2)
+					pp.Stock -= value.Stock
+					value.Stock = 0
+					_, err = u.pharmacyProductRepo.Update(c, pp)
+					if err != nil {
+						return err
+					}
+					_, err = u.pharmacyProductRepo.Update(c, value)
+					if err != nil {
+						return err
+					}
+					value = nil
+					break
+				}
+			}
+			if value != nil {
+				return apperror.NewResourceStateError("Cancel order")
+			}
+		}
+		err = u.stockMutationRepo.BulkCreate(c, stockMutations)
+		if err != nil {
+			return err
+		}
+		err = u.stockRecordRepo.BulkCreate(c, stockRecords)
+		if err != nil {
+			return err
+		}
+	}
+	return u.reservationRepo.UpdateStatusByShipment(c, shipment.Id, entity.ReservationHeld, entity.ReservationDeducted)
+}
+
+func (u *orderUsecase) restockShipment(c context.Context, order *entity.ProductOrder, shipment *entity.OrderShipment) error {
+	fetchedOrderItem, err := u.orderItemRepo.ListOfOrderItem(c, shipment.Id)
+	if err != nil {
+		return err
+	}
+	var listOfPharmacyProductId []uint
+	for _, item := range fetchedOrderItem {
+		listOfPharmacyProductId = append(listOfPharmacyProductId, item.PharmacyProductId)
+	}
+	stockMutationQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, order.Id).
+		Condition("to_pharmacy_product_id", valueobject.In, listOfPharmacyProductId)
+	fetchedSM, err := u.stockMutationRepo.Find(c, stockMutationQuery)
+	if err != nil {
+		return err
+	}
+	mutatedM := make(map[uint]bool)
+	for _, stockMutation := range fetchedSM {
+		mutatedM[stockMutation.ToPharmacyProductId] = true
+	}
+	for _, item := range fetchedOrderItem {
+		if mutatedM[item.PharmacyProductId] {
+			continue
+		}
+		lockedPP, err := u.pharmacyProductRepo.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, item.PharmacyProductId).Lock())
+		if err != nil {
+			return err
+		}
+		if lockedPP == nil {
+			return apperror.NewResourceStateError(fmt.Sprintf("pharmacy product %d no longer exists", item.PharmacyProductId))
+		}
+		lockedPP.Stock += item.Quantity
+		_, err = u.pharmacyProductRepo.Update(c, lockedPP)
+		if err != nil {
+			return err
+		}
+	}
+	if len(fetchedSM) != 0 {
+		var stockMutations []*entity.StockMutation
+		var stockRecords []*entity.StockRecord
+		for _, stockMutation := range fetchedSM {
+			sm, r1, r2 := createStockMutation(stockMutation.ToPharmacyProductId, stockMutation.FromPharmacyProductId, stockMutation.Quantity)
+			stockMutations = append(stockMutations, sm)
+			stockRecords = append(stockRecords, r1, r2)
+			ppId := [2]uint{stockMutation.FromPharmacyProductId, stockMutation.ToPharmacyProductId}
+			ppQuery := valueobject.NewQuery().Condition("id", valueobject.In, ppId).Lock()
+			fetchedPP, err := u.pharmacyProductRepo.Find(c, ppQuery)
+			if err != nil {
+				return err
+			}
+			itemQuery := valueobject.NewQuery().
+				Condition("shipment_id", valueobject.Equal, shipment.Id).
+				Condition("pharmacy_product_id", valueobject.Equal, stockMutation.ToPharmacyProductId)
+			fetchedItem, err := u.orderItemRepo.FindOne(c, itemQuery)
+			if err != nil {
+				return err
+			}
+			for _, pp := range fetchedPP {
+				if pp.Id == stockMutation.FromPharmacyProductId {
+					pp.Stock += stockMutation.Quantity
+				} else if pp.Id == stockMutation.ToPharmacyProductId {
+					pp.Stock = pp.Stock + fetchedItem.Quantity - stockMutation.Quantity
+				}
+				_, err = u.pharmacyProductRepo.Update(c, pp)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = u.stockMutationRepo.BulkCreate(c, stockMutations)
+		if err != nil {
+			return err
+		}
+		err = u.stockRecordRepo.BulkCreate(c, stockRecords)
+		if err != nil {
+			return err
+		}
+	}
+	return u.reservationRepo.UpdateStatusByShipment(c, shipment.Id, entity.ReservationDeducted, entity.ReservationReleased)
+}
@@ -2,27 +2,40 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"mime/multipart"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/imagehelper"
+	"github.com/night1010/everhealth/logger"
+	"github.com/night1010/everhealth/promotion"
+	"github.com/night1010/everhealth/rbac"
 	"github.com/night1010/everhealth/repository"
 	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/util"
 	"github.com/night1010/everhealth/valueobject"
 	"github.com/shopspring/decimal"
 )
 
 type OrderUsecase interface {
 	CreateOrder(context.Context, *entity.ProductOrder) (uint, error)
-	GetAvailableProduct(context.Context, uint) (decimal.Decimal, []*entity.PharmacyProduct, []*entity.OrderItem, []*entity.CartItem, error)
+	GetAvailableProduct(context.Context, uint) (decimal.Decimal, []*entity.PharmacyProduct, []*entity.OrderShipment, []*entity.CartItem, error)
 	ListAllOrders(context.Context, *valueobject.Query) (*valueobject.PagedResult, error)
 	UploadPaymentProof(context.Context, uint) error
+	ConfirmPayment(context.Context, uint) error
 	OrderDetail(context.Context, uint) (*entity.ProductOrder, []*entity.OrderItem, *entity.Address, error)
 	UserUpdateOrderStatus(context.Context, *entity.ProductOrder) error
 	AdminUpdateOrderStatus(context.Context, *entity.ProductOrder) error
 	MonthlyReport(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	GetInvoice(context.Context, uint) (*entity.Invoice, error)
+	UploadPrescription(context.Context) (*entity.OrderPrescription, error)
+	ReviewPrescription(context.Context, *entity.ProductOrder) error
+	CancelExpiredOrders(context.Context) error
+	ConfirmSentOrders(context.Context) error
 }
 
 type orderUsecase struct {
@@ -31,12 +44,22 @@ type orderUsecase struct {
 	cartRepo            repository.CartRepository
 	orderItemRepo       repository.OrderItemRepository
 	productOrderRepo    repository.ProductOrderRepository
+	orderShipmentRepo   repository.OrderShipmentRepository
 	cartItemRepo        repository.CartItemRepository
 	addressRepo         repository.AddressRepository
 	pharmacyRepo        repository.PharmacyRepository
 	pharmacyProductRepo repository.PharmacyProductRepository
 	stockMutationRepo   repository.StockMutationRepository
 	stockRecordRepo     repository.StockRecordRepository
+	reservationRepo     repository.StockReservationRepository
+	refundRepo          repository.RefundRepository
+	statusHistoryRepo   repository.OrderStatusHistoryRepository
+	invoiceRepo         repository.InvoiceRepository
+	prescriptionRepo    repository.OrderPrescriptionRepository
+	telemedicineRepo    repository.TelemedicineRepository
+	paymentRepo         repository.PaymentRepository
+	voucherUsecase      VoucherUsecase
+	transitions         []*orderTransition
 }
 
 func NewOrderUsecase(
@@ -45,34 +68,84 @@ func NewOrderUsecase(
 	cartRepo repository.CartRepository,
 	orderItemRepo repository.OrderItemRepository,
 	productOrderRepo repository.ProductOrderRepository,
+	orderShipmentRepo repository.OrderShipmentRepository,
 	cartItemRepo repository.CartItemRepository,
 	addressRepo repository.AddressRepository,
 	pharmacyRepo repository.PharmacyRepository,
 	pharmacyProductRepo repository.PharmacyProductRepository,
 	stockMutationRepo repository.StockMutationRepository,
 	stockRecordRepo repository.StockRecordRepository,
+	reservationRepo repository.StockReservationRepository,
+	refundRepo repository.RefundRepository,
+	statusHistoryRepo repository.OrderStatusHistoryRepository,
+	invoiceRepo repository.InvoiceRepository,
+	prescriptionRepo repository.OrderPrescriptionRepository,
+	telemedicineRepo repository.TelemedicineRepository,
+	paymentRepo repository.PaymentRepository,
+	voucherUsecase VoucherUsecase,
 ) OrderUsecase {
-	return &orderUsecase{
+	usecase := &orderUsecase{
 		manager:             manager,
 		imageHelper:         imageHelper,
 		cartRepo:            cartRepo,
 		orderItemRepo:       orderItemRepo,
 		productOrderRepo:    productOrderRepo,
+		orderShipmentRepo:   orderShipmentRepo,
 		cartItemRepo:        cartItemRepo,
 		addressRepo:         addressRepo,
 		pharmacyRepo:        pharmacyRepo,
 		pharmacyProductRepo: pharmacyProductRepo,
 		stockMutationRepo:   stockMutationRepo,
 		stockRecordRepo:     stockRecordRepo,
-	}
+		reservationRepo:     reservationRepo,
+		refundRepo:          refundRepo,
+		statusHistoryRepo:   statusHistoryRepo,
+		invoiceRepo:         invoiceRepo,
+		prescriptionRepo:    prescriptionRepo,
+		telemedicineRepo:    telemedicineRepo,
+		paymentRepo:         paymentRepo,
+		voucherUsecase:      voucherUsecase,
+	}
+	usecase.transitions = usecase.newOrderTransitions()
+	return usecase
 }
 
 func (u *orderUsecase) CreateOrder(ctx context.Context, userOrder *entity.ProductOrder) (uint, error) {
 	userId := ctx.Value("user_id").(uint)
-	total, pharmacyProducts, orderItems, fetchedCartItem, err := u.GetAvailableProduct(ctx, userOrder.AddressId)
+	total, pharmacyProducts, shipments, fetchedCartItem, err := u.GetAvailableProduct(ctx, userOrder.AddressId)
 	if err != nil {
 		return 0, err
 	}
+	shippingM := make(map[uint]entity.OrderShipment)
+	for _, shipping := range userOrder.Shipments {
+		shippingM[shipping.PharmacyId] = shipping
+	}
+	prescriptionM := make(map[uint]bool)
+	for _, pp := range pharmacyProducts {
+		if pp.Product.Drug != nil && pp.Product.Drug.DrugClassification.RequiresPrescription {
+			prescriptionM[pp.Id] = true
+		}
+	}
+	var needPrescription bool
+	var shippingPrice decimal.Decimal
+	var shippingNames, shippingEtas []string
+	for _, shipment := range shipments {
+		shipping, ok := shippingM[shipment.PharmacyId]
+		if !ok {
+			return 0, apperror.NewClientError(apperror.NewResourceStateError(fmt.Sprintf("no shipping method selected for pharmacy %d", shipment.PharmacyId)))
+		}
+		shipment.OrderStatusId = uint(entity.WaitingForPayment)
+		if requiresPrescription(shipment, prescriptionM) {
+			shipment.PrescriptionStatus = entity.PrescriptionPending
+			needPrescription = true
+		}
+		shipment.ShippingName = shipping.ShippingName
+		shipment.ShippingPrice = shipping.ShippingPrice
+		shipment.ShippingEta = shipping.ShippingEta
+		shippingPrice = shippingPrice.Add(shipping.ShippingPrice)
+		shippingNames = append(shippingNames, shipping.ShippingName)
+		shippingEtas = append(shippingEtas, shipping.ShippingEta)
+	}
 	var orderId uint
 	err = u.manager.Run(ctx, func(c context.Context) error {
 		var order entity.ProductOrder
@@ -80,24 +153,73 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userOrder *entity.Produc
 		order.OrderStatusId = uint(entity.WaitingForPayment)
 		order.ProfileId = userId
 		order.ExpiredAt = time.Now().Add(time.Hour * 24).Truncate(time.Hour).Add(-time.Minute)
-		order.ShippingName = userOrder.ShippingName
-		order.ShippingPrice = userOrder.ShippingPrice
-		order.ShippingEta = userOrder.ShippingEta
-		order.TotalPayment = total.Add(userOrder.ShippingPrice)
+		order.ShippingName = strings.Join(shippingNames, ", ")
+		order.ShippingPrice = shippingPrice
+		order.ShippingEta = strings.Join(shippingEtas, ", ")
+		order.TotalPayment = total.Add(shippingPrice)
+		var voucher *entity.Voucher
+		if userOrder.VoucherCode != "" {
+			voucher, err = u.applyVoucher(c, userOrder.VoucherCode, &order, shipments, pharmacyProducts)
+			if err != nil {
+				return err
+			}
+		}
 		order.PaymentMethod = userOrder.PaymentMethod
 		order.AddressId = userOrder.AddressId
 		order.ItemOrderQty = len(pharmacyProducts)
+		var prescription *entity.OrderPrescription
+		if needPrescription {
+			prescription, err = u.findOrderPrescription(c, userOrder)
+			if err != nil {
+				return err
+			}
+			order.PrescriptionId = &prescription.Id
+		}
 		createdOrder, err := u.productOrderRepo.Create(c, &order)
 		if err != nil {
 			return err
 		}
-		for _, item := range orderItems {
-			item.OrderId = createdOrder.Id
+		if prescription != nil {
+			prescription.OrderId = &createdOrder.Id
+			_, err = u.prescriptionRepo.Update(c, prescription)
+			if err != nil {
+				return err
+			}
+		}
+		var orderItems []*entity.OrderItem
+		for _, shipment := range shipments {
+			items := shipment.OrderItems
+			shipment.OrderItems = nil
+			shipment.Pharmacy = nil
+			shipment.OrderId = createdOrder.Id
+			createdShipment, err := u.orderShipmentRepo.Create(c, shipment)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				item.OrderId = createdOrder.Id
+				item.ShipmentId = createdShipment.Id
+			}
+			orderItems = append(orderItems, items...)
 		}
 		err = u.orderItemRepo.BulkCreate(c, orderItems)
 		if err != nil {
 			return err
 		}
+		err = u.reserveStock(c, createdOrder, orderItems)
+		if err != nil {
+			return err
+		}
+		if voucher != nil {
+			err = u.voucherUsecase.RedeemVoucher(c, voucher, createdOrder)
+			if err != nil {
+				return err
+			}
+		}
+		err = u.recordOrderStatus(c, createdOrder.Id, nil, nil, createdOrder.OrderStatusId, "order created")
+		if err != nil {
+			return err
+		}
 		err = u.cartItemRepo.BulkDelete(c, fetchedCartItem)
 		if err != nil {
 			return err
@@ -108,7 +230,198 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userOrder *entity.Produc
 	return orderId, err
 }
 
-func (u *orderUsecase) GetAvailableProduct(ctx context.Context, addressId uint) (decimal.Decimal, []*entity.PharmacyProduct, []*entity.OrderItem, []*entity.CartItem, error) {
+func (u *orderUsecase) findOrderPrescription(c context.Context, userOrder *entity.ProductOrder) (*entity.OrderPrescription, error) {
+	userId := c.Value("user_id").(uint)
+	if userOrder.PrescriptionId != nil {
+		prescriptionQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, *userOrder.PrescriptionId).Lock()
+		fetchedPrescription, err := u.prescriptionRepo.FindOne(c, prescriptionQuery)
+		if err != nil {
+			return nil, err
+		}
+		if fetchedPrescription == nil || fetchedPrescription.ProfileId != userId {
+			return nil, apperror.NewResourceNotFoundError("prescription", "id", *userOrder.PrescriptionId)
+		}
+		if fetchedPrescription.OrderId != nil {
+			return nil, apperror.NewResourceStateError("prescription has already been used for another order")
+		}
+		return fetchedPrescription, nil
+	}
+	if userOrder.PrescriptionTelemedicineId != nil {
+		telemedicineQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, *userOrder.PrescriptionTelemedicineId).Lock()
+		fetchedTelemedicine, err := u.telemedicineRepo.FindOne(c, telemedicineQuery)
+		if err != nil {
+			return nil, err
+		}
+		if fetchedTelemedicine == nil || fetchedTelemedicine.ProfileId != userId {
+			return nil, apperror.NewResourceNotFoundError("telemedicine", "id", *userOrder.PrescriptionTelemedicineId)
+		}
+		if fetchedTelemedicine.Status != entity.End || fetchedTelemedicine.PrescriptionPdf == "" {
+			return nil, apperror.NewResourceStateError("telemedicine has no prescription from a completed consultation")
+		}
+		prescriptionQuery := valueobject.NewQuery().Condition("telemedicine_id", valueobject.Equal, fetchedTelemedicine.Id).Lock()
+		fetchedPrescription, err := u.prescriptionRepo.FindOne(c, prescriptionQuery)
+		if err != nil {
+			return nil, err
+		}
+		if fetchedPrescription != nil && fetchedPrescription.OrderId != nil {
+			return nil, apperror.NewResourceStateError("telemedicine prescription has already been used for another order")
+		}
+		if fetchedPrescription != nil {
+			return fetchedPrescription, nil
+		}
+		return u.prescriptionRepo.Create(c, &entity.OrderPrescription{
+			ProfileId:      userId,
+			TelemedicineId: &fetchedTelemedicine.Id,
+			File:           fetchedTelemedicine.PrescriptionPdf,
+			FileKey:        fetchedTelemedicine.PrescriptionPdfKey,
+		})
+	}
+	return nil, apperror.NewResourceStateError("a prescription is required to order prescription-only drugs")
+}
+
+func requiresPrescription(shipment *entity.OrderShipment, prescriptionM map[uint]bool) bool {
+	for _, item := range shipment.OrderItems {
+		if prescriptionM[item.PharmacyProductId] {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *orderUsecase) UploadPrescription(ctx context.Context) (*entity.OrderPrescription, error) {
+	userId := ctx.Value("user_id").(uint)
+	image := ctx.Value("image")
+	if image == nil {
+		return nil, apperror.NewResourceStateError("no image inputted")
+	}
+	fileKey := entity.OrderPrescriptionPrefix + fmt.Sprintf("%d-", userId) + generateRandomString(10)
+	fileUrl, err := u.imageHelper.Upload(ctx, image.(multipart.File), entity.OrderPrescriptionFolder, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	prescription, err := u.prescriptionRepo.Create(ctx, &entity.OrderPrescription{
+		ProfileId: userId,
+		File:      fileUrl,
+		FileKey:   fileKey,
+	})
+	if err != nil {
+		err2 := u.imageHelper.Destroy(ctx, entity.OrderPrescriptionFolder, fileKey)
+		if err2 != nil {
+			return nil, err2
+		}
+		return nil, err
+	}
+	return prescription, nil
+}
+
+func (u *orderUsecase) ReviewPrescription(ctx context.Context, order *entity.ProductOrder) error {
+	userId := ctx.Value("user_id").(uint)
+	review := order.Shipments[0]
+	return u.manager.Run(ctx, func(c context.Context) error {
+		fetchedShipments, err := u.orderShipmentRepo.FindAdminShipments(c, order.Id, review.Id, userId, entity.PermissionOrderReviewPrescription)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		var reviewed int
+		for _, shipment := range fetchedShipments {
+			if shipment.PrescriptionStatus == entity.PrescriptionNotRequired {
+				continue
+			}
+			if shipment.OrderStatusId != uint(entity.WaitingForPayment) && shipment.OrderStatusId != uint(entity.WaitingForPaymentConfirmation) {
+				return apperror.NewResourceStateError("prescription can only be reviewed before the order is processed")
+			}
+			shipment.PrescriptionStatus = review.PrescriptionStatus
+			shipment.PrescriptionNote = review.PrescriptionNote
+			shipment.PrescriptionReviewedBy = &userId
+			shipment.PrescriptionReviewedAt = &now
+			_, err = u.orderShipmentRepo.Update(c, shipment)
+			if err != nil {
+				return err
+			}
+			reviewed++
+		}
+		if reviewed == 0 {
+			return apperror.NewResourceNotFoundError("prescription", "order id", order.Id)
+		}
+		return nil
+	})
+}
+
+func (u *orderUsecase) applyVoucher(c context.Context, code string, order *entity.ProductOrder, shipments []*entity.OrderShipment, pharmacyProducts []*entity.PharmacyProduct) (*entity.Voucher, error) {
+	categoryM := make(map[uint]uint)
+	for _, pp := range pharmacyProducts {
+		categoryM[pp.Id] = pp.Product.ProductCategoryId
+	}
+	cart := &promotion.Cart{ShippingCost: order.ShippingPrice}
+	for _, shipment := range shipments {
+		for _, item := range shipment.OrderItems {
+			cart.Lines = append(cart.Lines, &promotion.Line{
+				ProductCategoryId: categoryM[item.PharmacyProductId],
+				SubTotal:          item.SubTotal,
+			})
+		}
+	}
+	voucher, result, err := u.voucherUsecase.ApplyVoucher(c, code, cart)
+	if err != nil {
+		return nil, err
+	}
+	var eligibleTotals, shippingPrices []decimal.Decimal
+	for _, shipment := range shipments {
+		var eligible decimal.Decimal
+		for _, item := range shipment.OrderItems {
+			if voucher.ProductCategoryId == nil || *voucher.ProductCategoryId == categoryM[item.PharmacyProductId] {
+				eligible = eligible.Add(item.SubTotal)
+			}
+		}
+		eligibleTotals = append(eligibleTotals, eligible)
+		shippingPrices = append(shippingPrices, shipment.ShippingPrice)
+	}
+	discounts := allocateDiscount(result.Discount, eligibleTotals)
+	shippingDiscounts := allocateDiscount(result.ShippingDiscount, shippingPrices)
+	for i, shipment := range shipments {
+		shipment.Discount = discounts[i]
+		shipment.ShippingDiscount = shippingDiscounts[i]
+	}
+	order.VoucherId = &voucher.Id
+	order.VoucherCode = voucher.Code
+	order.Discount = result.Discount
+	order.ShippingDiscount = result.ShippingDiscount
+	order.TotalPayment = order.TotalPayment.Sub(result.Total())
+	return voucher, nil
+}
+
+func allocateDiscount(discount decimal.Decimal, weights []decimal.Decimal) []decimal.Decimal {
+	shares := make([]decimal.Decimal, len(weights))
+	var total decimal.Decimal
+	for _, weight := range weights {
+		total = total.Add(weight)
+	}
+	if !total.IsPositive() {
+		return shares
+	}
+	remaining := discount
+	last := -1
+	for i, weight := range weights {
+		if weight.IsPositive() {
+			last = i
+		}
+	}
+	for i, weight := range weights {
+		if !weight.IsPositive() {
+			continue
+		}
+		if i == last {
+			shares[i] = remaining
+			break
+		}
+		shares[i] = discount.Mul(weight).Div(total).Round(2)
+		remaining = remaining.Sub(shares[i])
+	}
+	return shares
+}
+
+func (u *orderUsecase) GetAvailableProduct(ctx context.Context, addressId uint) (decimal.Decimal, []*entity.PharmacyProduct, []*entity.OrderShipment, []*entity.CartItem, error) {
 	userId := ctx.Value("user_id").(uint)
 	cartItemQuery := valueobject.NewQuery().
 		Condition("cart_id", valueobject.Equal, userId).
@@ -120,20 +433,17 @@ func (u *orderUsecase) GetAvailableProduct(ctx context.Context, addressId uint)
 	if len(fetchedCartItem) == 0 {
 		return decimal.Zero, nil, nil, nil, apperror.NewClientError(apperror.NewResourceStateError("no item in cart"))
 	}
-	pharmacyProductM := make(map[uint]*entity.PharmacyProduct)
+	quantityM := make(map[uint]int)
 	var listOfProductId []uint
 	for _, item := range fetchedCartItem {
 		listOfProductId = append(listOfProductId, item.ProductId)
-		pharmacyProductM[item.ProductId] = &entity.PharmacyProduct{
-			Id:    0,
-			Stock: item.Quantity,
-		}
+		quantityM[item.ProductId] = item.Quantity
 	}
 	fetchedAddress, err := u.addressRepo.FindById(ctx, addressId)
 	if err != nil {
 		return decimal.Zero, nil, nil, nil, err
 	}
-	if fetchedAddress.ProfileId != userId {
+	if fetchedAddress == nil || fetchedAddress.ProfileId != userId {
 		return decimal.Zero, nil, nil, nil, apperror.NewClientError(apperror.NewResourceNotFoundError("address", "id", addressId))
 	}
 	fetchedPP, err := u.pharmacyProductRepo.FindNearbyProductOrder(ctx, listOfProductId, fetchedAddress.Location)
@@ -143,54 +453,73 @@ func (u *orderUsecase) GetAvailableProduct(ctx context.Context, addressId uint)
 	if len(fetchedPP) == 0 {
 		return decimal.Zero, nil, nil, nil, apperror.NewResourceStateError("there are no pharmacies nearby that carry the product you are looking for.")
 	}
-	count := len(listOfProductId)
-	var selectedPharmacy uint
-	for key, value := range pharmacyProductM {
-		if count == 0 {
-			break
+	var listOfNearbyPPId []uint
+	for _, pp := range fetchedPP {
+		listOfNearbyPPId = append(listOfNearbyPPId, pp.Id)
+	}
+	heldM, err := u.reservationRepo.FindHeldQuantity(ctx, listOfNearbyPPId)
+	if err != nil {
+		return decimal.Zero, nil, nil, nil, err
+	}
+	pharmacyProductM := make(map[uint]map[uint]*entity.PharmacyProduct)
+	var listOfPharmacyId []uint
+	for _, pp := range fetchedPP {
+		if !pp.IsActive || pp.Stock-heldM[pp.Id] < quantityM[pp.ProductId] {
+			continue
+		}
+		if _, ok := pharmacyProductM[pp.PharmacyId]; !ok {
+			pharmacyProductM[pp.PharmacyId] = make(map[uint]*entity.PharmacyProduct)
+			listOfPharmacyId = append(listOfPharmacyId, pp.PharmacyId)
 		}
-		for _, pp := range fetchedPP {
-			if selectedPharmacy != pp.PharmacyId {
-				count = len(listOfProductId)
-				selectedPharmacy = pp.PharmacyId
+		pharmacyProductM[pp.PharmacyId][pp.ProductId] = pp
+	}
+	var totalPrice decimal.Decimal
+	var shipments []*entity.OrderShipment
+	var listOfProductPharmacyId []uint
+	for len(quantityM) != 0 {
+		var selectedPharmacy uint
+		var selectedCount int
+		for _, pharmacyId := range listOfPharmacyId {
+			count := 0
+			for productId := range quantityM {
+				if _, ok := pharmacyProductM[pharmacyId][productId]; ok {
+					count++
+				}
 			}
-			qty := value.Stock
-			if (pp.ProductId == key) && pp.IsActive {
-				value.Id = pp.Id
-				value.ProductId = pp.ProductId
-				value.PharmacyId = pp.PharmacyId
-				value.Stock = qty
-				value.Price = pp.Price
-				count--
-				break
+			if count > selectedCount {
+				selectedPharmacy = pharmacyId
+				selectedCount = count
 			}
 		}
-		if value.Id == uint(0) {
+		if selectedCount == 0 {
 			return decimal.Zero, nil, nil, nil, apperror.NewResourceStateError("No nearest pharmacy available with your product")
 		}
-	}
-	if count != 0 {
-		return decimal.Zero, nil, nil, nil, apperror.NewResourceStateError("No nearest pharmacy available with your product")
-	}
-	var totalPrice decimal.Decimal
-	var orderItems []*entity.OrderItem
-	var listOfProductPharmacyId []uint
-	for _, value := range pharmacyProductM {
-		orderItem := &entity.OrderItem{
-			PharmacyProductId: value.Id,
-			Quantity:          value.Stock,
-			SubTotal:          value.Price.Mul(decimal.NewFromInt(int64(value.Stock))),
+		shipment := &entity.OrderShipment{PharmacyId: selectedPharmacy}
+		for productId, qty := range quantityM {
+			pp, ok := pharmacyProductM[selectedPharmacy][productId]
+			if !ok {
+				continue
+			}
+			orderItem := &entity.OrderItem{
+				PharmacyProductId: pp.Id,
+				Quantity:          qty,
+				SubTotal:          pp.Price.Mul(decimal.NewFromInt(int64(qty))),
+			}
+			shipment.Pharmacy = pp.Pharmacy
+			shipment.OrderItems = append(shipment.OrderItems, orderItem)
+			shipment.SubTotal = shipment.SubTotal.Add(orderItem.SubTotal)
+			listOfProductPharmacyId = append(listOfProductPharmacyId, pp.Id)
+			delete(quantityM, productId)
 		}
-		orderItems = append(orderItems, orderItem)
-		totalPrice = totalPrice.Add(orderItem.SubTotal)
-		listOfProductPharmacyId = append(listOfProductPharmacyId, value.Id)
+		totalPrice = totalPrice.Add(shipment.SubTotal)
+		shipments = append(shipments, shipment)
 	}
-	productPharmacyQuery := valueobject.NewQuery().Condition("id", valueobject.In, listOfProductPharmacyId).WithPreload("Product")
+	productPharmacyQuery := valueobject.NewQuery().Condition("id", valueobject.In, listOfProductPharmacyId).WithPreload("Product").WithPreload("Product.Drug.DrugClassification")
 	fetchedPPResult, err := u.pharmacyProductRepo.Find(ctx, productPharmacyQuery)
 	if err != nil {
 		return decimal.Zero, nil, nil, nil, err
 	}
-	return totalPrice, fetchedPPResult, orderItems, fetchedCartItem, nil
+	return totalPrice, fetchedPPResult, shipments, fetchedCartItem, nil
 }
 
 func (u *orderUsecase) ListAllOrders(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
@@ -218,6 +547,7 @@ func (u *orderUsecase) UploadPaymentProof(ctx context.Context, orderId uint) err
 	if fetchedOrder.OrderStatusId > uint(entity.WaitingForPaymentConfirmation) {
 		return apperror.NewClientError(apperror.NewResourceStateError("payment already confirmed"))
 	}
+	isReupload := fetchedOrder.OrderStatusId == uint(entity.WaitingForPaymentConfirmation)
 	image := ctx.Value("image")
 	if image == nil {
 		return apperror.NewClientError(apperror.NewResourceStateError("no image inputted"))
@@ -232,12 +562,34 @@ func (u *orderUsecase) UploadPaymentProof(ctx context.Context, orderId uint) err
 		return err
 	}
 	fetchedOrder.PaymentProof = proofUrl
-	fetchedOrder.OrderStatusId = uint(entity.WaitingForPaymentConfirmation)
-	_, err = u.productOrderRepo.Update(ctx, fetchedOrder)
-	if err != nil {
-		return err
-	}
-	return nil
+	return u.manager.Run(ctx, func(c context.Context) error {
+		err := u.recordManualPayment(c, fetchedOrder)
+		if err != nil {
+			return err
+		}
+		if isReupload {
+			_, err := u.productOrderRepo.Update(c, fetchedOrder)
+			return err
+		}
+		return u.transitionOrder(c, fetchedOrder, entity.WaitingForPaymentConfirmation, "payment proof uploaded")
+	})
+}
+
+func (u *orderUsecase) ConfirmPayment(ctx context.Context, orderId uint) error {
+	return u.manager.Run(ctx, func(c context.Context) error {
+		orderQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, orderId).Lock()
+		fetchedOrder, err := u.productOrderRepo.FindOne(c, orderQuery)
+		if err != nil {
+			return err
+		}
+		if fetchedOrder == nil {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", orderId))
+		}
+		if fetchedOrder.OrderStatusId != uint(entity.WaitingForPayment) {
+			return apperror.NewClientError(apperror.NewResourceStateError("order is not waiting for payment"))
+		}
+		return u.transitionOrder(c, fetchedOrder, entity.WaitingForPaymentConfirmation, "payment received")
+	})
 }
 
 func (u *orderUsecase) OrderDetail(ctx context.Context, orderId uint) (*entity.ProductOrder, []*entity.OrderItem, *entity.Address, error) {
@@ -250,7 +602,18 @@ func (u *orderUsecase) OrderDetail(ctx context.Context, orderId uint) (*entity.P
 	if fetchedOrder.Id == uint(0) {
 		return nil, nil, nil, apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", orderId))
 	}
-	orderItemQuery := valueobject.NewQuery().Condition("order_id", valueobject.Equal, orderId).WithJoin("PharmacyProduct").WithJoin("PharmacyProduct.Product")
+	var listOfShipmentId []uint
+	for _, shipment := range fetchedOrder.Shipments {
+		listOfShipmentId = append(listOfShipmentId, shipment.Id)
+	}
+	if len(listOfShipmentId) == 0 {
+		return nil, nil, nil, apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", orderId))
+	}
+	orderItemQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, orderId).
+		Condition("shipment_id", valueobject.In, listOfShipmentId).
+		WithJoin("PharmacyProduct").
+		WithJoin("PharmacyProduct.Product")
 	fetchedItemOrder, err := u.orderItemRepo.Find(ctx, orderItemQuery)
 	if err != nil {
 		return nil, nil, nil, err
@@ -263,46 +626,53 @@ func (u *orderUsecase) OrderDetail(ctx context.Context, orderId uint) (*entity.P
 	if fetchedAddress == nil {
 		return nil, nil, nil, apperror.NewClientError(apperror.NewResourceNotFoundError("address", "id", fetchedOrder.AddressId))
 	}
+	historyQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, orderId).
+		WithPreload("FromStatus").
+		WithPreload("ToStatus").
+		WithSortBy("created_at")
+	fetchedHistories, err := u.statusHistoryRepo.Find(ctx, historyQuery)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	shipmentM := make(map[uint]bool)
+	for _, shipmentId := range listOfShipmentId {
+		shipmentM[shipmentId] = true
+	}
+	for _, history := range fetchedHistories {
+		if history.ShipmentId == nil || shipmentM[*history.ShipmentId] {
+			fetchedOrder.Histories = append(fetchedOrder.Histories, history)
+		}
+	}
 	return fetchedOrder, fetchedItemOrder, fetchedAddress, nil
 }
 
 func (u *orderUsecase) UserUpdateOrderStatus(ctx context.Context, order *entity.ProductOrder) error {
 	userId := ctx.Value("user_id").(uint)
-	orderQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, order.Id)
-	fetchedOrder, err := u.productOrderRepo.FindOne(ctx, orderQuery)
-	if err != nil {
-		return err
-	}
-	if fetchedOrder == nil {
-		return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
-	}
-	if fetchedOrder.ProfileId != userId {
-		return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
-	}
-	if order.OrderStatusId == uint(entity.Canceled) {
-		if fetchedOrder.OrderStatusId >= uint(entity.WaitingForPaymentConfirmation) {
-			return apperror.NewClientError(apperror.NewResourceStateError("cant cancel order"))
+	return u.manager.Run(ctx, func(c context.Context) error {
+		orderQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, order.Id).Lock()
+		fetchedOrder, err := u.productOrderRepo.FindOne(c, orderQuery)
+		if err != nil {
+			return err
 		}
-		fetchedOrder.OrderStatusId = uint(entity.Canceled)
-	}
-	if order.OrderStatusId == uint(entity.OrderConfirmed) {
-		if fetchedOrder.OrderStatusId != uint(entity.Sent) {
-			return apperror.NewClientError(apperror.NewResourceStateError("cant confirm order"))
+		if fetchedOrder == nil {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
 		}
-		fetchedOrder.OrderStatusId = uint(entity.OrderConfirmed)
-	}
-	_, err = u.productOrderRepo.Update(ctx, fetchedOrder)
-	if err != nil {
-		return err
-	}
-	return nil
+		if fetchedOrder.ProfileId != userId {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
+		}
+		return u.transitionOrder(c, fetchedOrder, entity.StatusOrder(order.OrderStatusId), order.StatusReason)
+	})
 }
 
 func (u *orderUsecase) AdminUpdateOrderStatus(ctx context.Context, order *entity.ProductOrder) error {
-	roleId := ctx.Value("role_id").(entity.RoleId)
 	userId := ctx.Value("user_id").(uint)
-	if roleId != entity.RoleAdmin {
-		return apperror.NewForbiddenActionError("you're not admin")
+	if !rbac.Can(ctx, entity.PermissionOrderUpdateStatus) {
+		return apperror.NewForbiddenActionError("you're not allowed to update order status")
+	}
+	var shipmentId uint
+	if len(order.Shipments) != 0 {
+		shipmentId = order.Shipments[0].Id
 	}
 	err := u.manager.Run(ctx, func(c context.Context) error {
 		orderQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, order.Id).Lock()
@@ -313,155 +683,202 @@ func (u *orderUsecase) AdminUpdateOrderStatus(ctx context.Context, order *entity
 		if fetchedOrder == nil {
 			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
 		}
-		switch order.OrderStatusId {
-		case uint(entity.Processed):
-			if fetchedOrder.OrderStatusId != uint(entity.WaitingForPaymentConfirmation) {
-				return apperror.NewClientError(apperror.NewResourceStateError("cant update order status to processed"))
-			}
-			fetchedOrderItem, err := u.orderItemRepo.ListOfOrderItem(c, order.Id, userId)
+		fetchedShipments, err := u.orderShipmentRepo.FindAdminShipments(c, order.Id, shipmentId, userId, entity.PermissionOrderUpdateStatus)
+		if err != nil {
+			return err
+		}
+		if len(fetchedShipments) == 0 {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
+		}
+		for _, shipment := range fetchedShipments {
+			err = u.transitionShipment(c, fetchedOrder, shipment, entity.StatusOrder(order.OrderStatusId), order.StatusReason)
 			if err != nil {
 				return err
 			}
-			if len(fetchedOrderItem) == 0 {
-				return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", order.Id))
-			}
-			pharmacyProductM := make(map[uint]*entity.PharmacyProduct)
-			nearestPharmacyLoc := fetchedOrderItem[0].PharmacyProduct.Pharmacy.Location
-			var listOfProductId []uint
-			var stockMutations []*entity.StockMutation
-			var stockRecords []*entity.StockRecord
-			for _, item := range fetchedOrderItem {
-				if item.PharmacyProduct.Stock >= item.Quantity {
-					item.PharmacyProduct.Stock -= item.Quantity
-					_, err := u.pharmacyProductRepo.Update(c, &item.PharmacyProduct)
-					if err != nil {
-						return err
-					}
-				} else {
-					item.PharmacyProduct.Stock = item.Quantity - item.PharmacyProduct.Stock
-					pharmacyProductM[item.PharmacyProduct.ProductId] = &item.PharmacyProduct
-					listOfProductId = append(listOfProductId, item.PharmacyProduct.ProductId)
-				}
-			}
-			if len(listOfProductId) != 0 {
-				fetchedPP, err := u.pharmacyProductRepo.FindNearbyProductOrder(c, listOfProductId, nearestPharmacyLoc)
-				if err != nil {
-					return err
-				}
-				if len(fetchedPP) == 0 {
-					fetchedOrder.OrderStatusId = uint(entity.Canceled)
-					return apperror.NewResourceStateError("No nearest pharmacy found, order cancelled")
-				}
-				for key, value := range pharmacyProductM {
-					for _, pp := range fetchedPP {
-						if (pp.ProductId == key) && (pp.Stock >= value.Stock) && (value.PharmacyId != pp.PharmacyId) {
-							sm, r1, r2 := createStockMutation(pp.Id, value.Id, value.Stock)
-							sm.OrderId = fetchedOrder.Id
-							stockMutations = append(stockMutations, sm)
-							stockRecords = append(stockRecords, r1, r2)
-							pp.Stock -= value.Stock
-							value.Stock = 0
-							_, err = u.pharmacyProductRepo.Update(c, pp)
-							if err != nil {
-								return err
-							}
-							_, err = u.pharmacyProductRepo.Update(c, value)
-							if err != nil {
-								return err
-							}
-							value = nil
-							break
-						}
-					}
-					if value != nil {
-						fetchedOrder.OrderStatusId = uint(entity.Canceled)
-						return apperror.NewResourceStateError("Cancel order")
-					}
-				}
-				err = u.stockMutationRepo.BulkCreate(c, stockMutations)
-				if err != nil {
-					return err
-				}
-				err = u.stockRecordRepo.BulkCreate(c, stockRecords)
-				if err != nil {
-					return err
-				}
-			}
-			fetchedOrder.OrderStatusId = uint(entity.Processed)
-		case uint(entity.Sent):
-			if fetchedOrder.OrderStatusId != uint(entity.Processed) {
-				return apperror.NewClientError(apperror.NewResourceStateError("cant update order status to sent"))
+		}
+		return u.syncOrderStatus(c, fetchedOrder, order.StatusReason)
+	})
+	return err
+}
+
+func (u *orderUsecase) GetInvoice(ctx context.Context, orderId uint) (*entity.Invoice, error) {
+	_, _, _, err := u.OrderDetail(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	fetchedOrder, fetchedItems, fetchedAddress, err := u.invoiceOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	var invoice *entity.Invoice
+	err = u.manager.Run(ctx, func(c context.Context) error {
+		invoiceQuery := valueobject.NewQuery().Condition("order_id", valueobject.Equal, orderId).Lock()
+		invoice, err = u.invoiceRepo.FindOne(c, invoiceQuery)
+		if err != nil {
+			return err
+		}
+		if invoice != nil {
+			return nil
+		}
+		invoice, err = u.invoiceRepo.Create(c, &entity.Invoice{
+			OrderId: orderId,
+			Key:     fmt.Sprintf("%s%d", entity.InvoicePrefix, orderId),
+		})
+		if err != nil {
+			return err
+		}
+		invoice.Number = fmt.Sprintf("INV/%d/%06d", fetchedOrder.OrderedAt.Year(), invoice.Id)
+		_, err = u.invoiceRepo.Update(c, invoice)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Url != "" && invoice.OrderUpdatedAt.Equal(fetchedOrder.UpdatedAt) {
+		return invoice, nil
+	}
+	invoicePdf, err := util.CreateInvoice(invoice.Number, fetchedOrder, fetchedItems, fetchedAddress)
+	if err != nil {
+		return nil, err
+	}
+	invoiceUrl, err := u.imageHelper.Upload(ctx, invoicePdf, entity.InvoiceFolder, invoice.Key)
+	if err != nil {
+		return nil, err
+	}
+	firstUpload := invoice.Url == ""
+	invoice.Url = invoiceUrl
+	invoice.OrderUpdatedAt = fetchedOrder.UpdatedAt
+	_, err = u.invoiceRepo.Update(ctx, invoice)
+	if err != nil {
+		if firstUpload {
+			if destroyErr := u.imageHelper.Destroy(ctx, entity.InvoiceFolder, invoice.Key); destroyErr != nil {
+				logger.Log.Errorf("failed to remove invoice %s: %v", invoice.Key, destroyErr)
 			}
-			fetchedOrder.OrderStatusId = uint(entity.Sent)
-		case uint(entity.Canceled):
-			if fetchedOrder.OrderStatusId >= uint(entity.Sent) {
-				return apperror.NewClientError(apperror.NewResourceStateError("cant cancel order"))
+		}
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (u *orderUsecase) invoiceOrder(ctx context.Context, orderId uint) (*entity.ProductOrder, []*entity.OrderItem, *entity.Address, error) {
+	orderQuery := valueobject.NewQuery().
+		Condition("id", valueobject.Equal, orderId).
+		WithPreload("OrderStatus").
+		WithPreload("Shipments.Pharmacy")
+	fetchedOrder, err := u.productOrderRepo.FindOne(ctx, orderQuery)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if fetchedOrder == nil {
+		return nil, nil, nil, apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", orderId))
+	}
+	orderItemQuery := valueobject.NewQuery().
+		Condition("order_id", valueobject.Equal, orderId).
+		WithJoin("PharmacyProduct").
+		WithJoin("PharmacyProduct.Product")
+	fetchedItems, err := u.orderItemRepo.Find(ctx, orderItemQuery)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	addressQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, fetchedOrder.AddressId).WithPreload("Province").WithPreload("City")
+	fetchedAddress, err := u.addressRepo.FindOne(ctx, addressQuery)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if fetchedAddress == nil {
+		return nil, nil, nil, apperror.NewClientError(apperror.NewResourceNotFoundError("address", "id", fetchedOrder.AddressId))
+	}
+	return fetchedOrder, fetchedItems, fetchedAddress, nil
+}
+
+func (u *orderUsecase) CancelExpiredOrders(ctx context.Context) error {
+	orderQuery := valueobject.NewQuery().
+		Condition("order_status_id", valueobject.Equal, entity.WaitingForPayment).
+		Condition("expired_at", valueobject.LessThan, time.Now())
+	return u.transitionOrders(ctx, orderQuery, entity.Canceled, "payment deadline passed")
+}
+
+func (u *orderUsecase) ConfirmSentOrders(ctx context.Context) error {
+	orderQuery := valueobject.NewQuery().
+		Condition("order_status_id", valueobject.Equal, entity.Sent).
+		Condition("ordered_at", valueobject.LessThan, time.Now().Add(time.Hour*-7*24))
+	return u.transitionOrders(ctx, orderQuery, entity.OrderConfirmed, "order confirmed automatically")
+}
+
+func (u *orderUsecase) transitionOrders(ctx context.Context, query *valueobject.Query, to entity.StatusOrder, reason string) error {
+	fetchedOrders, err := u.productOrderRepo.Find(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, order := range fetchedOrders {
+		err = u.manager.Run(ctx, func(c context.Context) error {
+			orderQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, order.Id).Lock()
+			lockedOrder, err := u.productOrderRepo.FindOne(c, orderQuery)
+			if err != nil {
+				return err
 			}
-			if fetchedOrder.OrderStatusId == uint(entity.Processed) {
-				stockMutationQuery := valueobject.NewQuery().Condition("order_id", valueobject.Equal, fetchedOrder.Id)
-				fetchedSM, err := u.stockMutationRepo.Find(c, stockMutationQuery)
-				if err != nil {
-					return err
-				}
-				if len(fetchedSM) != 0 {
-					var stockMutations []*entity.StockMutation
-					var stockRecords []*entity.StockRecord
-					for _, stockMutation := range fetchedSM {
-						sm, r1, r2 := createStockMutation(stockMutation.ToPharmacyProductId, stockMutation.FromPharmacyProductId, stockMutation.Quantity)
-						stockMutations = append(stockMutations, sm)
-						stockRecords = append(stockRecords, r1, r2)
-						ppId := [2]uint{stockMutation.FromPharmacyProductId, stockMutation.ToPharmacyProductId}
-						ppQuery := valueobject.NewQuery().Condition("id", valueobject.In, ppId).Lock()
-						fetchedPP, err := u.pharmacyProductRepo.Find(c, ppQuery)
-						if err != nil {
-							return err
-						}
-						itemQuery := valueobject.NewQuery().
-							Condition("order_id", valueobject.Equal, fetchedOrder.Id).
-							Condition("pharmacy_product_id", valueobject.Equal, stockMutation.ToPharmacyProductId)
-						fetchedItem, err := u.orderItemRepo.FindOne(c, itemQuery)
-						if err != nil {
-							return err
-						}
-						for _, pp := range fetchedPP {
-							if pp.Id == stockMutation.FromPharmacyProductId {
-								pp.Stock += stockMutation.Quantity
-							} else if pp.Id == stockMutation.ToPharmacyProductId {
-								pp.Stock = pp.Stock + fetchedItem.Quantity - stockMutation.Quantity
-							}
-							_, err = u.pharmacyProductRepo.Update(c, pp)
-							if err != nil {
-								return err
-							}
-						}
-					}
-					err = u.stockMutationRepo.BulkCreate(c, stockMutations)
-					if err != nil {
-						return err
-					}
-					err = u.stockRecordRepo.BulkCreate(c, stockRecords)
-					if err != nil {
-						return err
-					}
-				}
+			if lockedOrder == nil || lockedOrder.OrderStatusId != order.OrderStatusId {
+				return nil
 			}
-			fetchedOrder.OrderStatusId = uint(entity.Canceled)
+			return u.transitionOrder(c, lockedOrder, to, reason)
+		})
+		if err != nil {
+			logger.Log.Errorf("failed to move order %d to status %d: %v", order.Id, to, err)
 		}
-		_, err = u.productOrderRepo.Update(c, fetchedOrder)
+	}
+	return nil
+}
+
+func (u *orderUsecase) reserveStock(c context.Context, order *entity.ProductOrder, orderItems []*entity.OrderItem) error {
+	var listOfPharmacyProductId []uint
+	for _, item := range orderItems {
+		listOfPharmacyProductId = append(listOfPharmacyProductId, item.PharmacyProductId)
+	}
+	sort.Slice(listOfPharmacyProductId, func(i, j int) bool {
+		return listOfPharmacyProductId[i] < listOfPharmacyProductId[j]
+	})
+	stockM := make(map[uint]*entity.PharmacyProduct)
+	for _, ppId := range listOfPharmacyProductId {
+		lockedPP, err := u.pharmacyProductRepo.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, ppId).WithPreload("Product").Lock())
 		if err != nil {
 			return err
 		}
-		return nil
-	})
-	return err
+		if lockedPP == nil {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("pharmacy product", "id", ppId))
+		}
+		stockM[ppId] = lockedPP
+	}
+	heldM, err := u.reservationRepo.FindHeldQuantity(c, listOfPharmacyProductId)
+	if err != nil {
+		return err
+	}
+	var reservations []*entity.StockReservation
+	for _, item := range orderItems {
+		pp := stockM[item.PharmacyProductId]
+		if pp.Stock-heldM[pp.Id] < item.Quantity {
+			return apperror.NewClientError(apperror.NewResourceStateError(fmt.Sprintf("insufficient stock for %s", pp.Product.Name)))
+		}
+		heldM[pp.Id] += item.Quantity
+		expiredAt := order.ExpiredAt
+		reservations = append(reservations, &entity.StockReservation{
+			PharmacyProductId: item.PharmacyProductId,
+			OrderId:           order.Id,
+			ShipmentId:        item.ShipmentId,
+			OrderItemId:       item.Id,
+			Quantity:          item.Quantity,
+			Status:            entity.ReservationHeld,
+			ExpiredAt:         &expiredAt,
+		})
+	}
+	return u.reservationRepo.BulkCreate(c, reservations)
 }
 
 func (u *orderUsecase) MonthlyReport(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
-	if ctx.Value("role_id").(entity.RoleId) == entity.RoleAdmin {
-		return u.orderItemRepo.MonthlyReportAdminPharmacy(ctx, query)
+	if rbac.Can(ctx, entity.PermissionOrderReportAll) {
+		return u.orderItemRepo.MonthlyReport(ctx, query)
 	}
 
-	return u.orderItemRepo.MonthlyReport(ctx, query)
+	return u.orderItemRepo.MonthlyReportAdminPharmacy(ctx, query)
 }
 
 func createStockMutation(from, to uint, qty int) (*entity.StockMutation, *entity.StockRecord, *entity.StockRecord) {
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/logger"
+	"github.com/night1010/everhealth/payment"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type PaymentUsecase interface {
+	CreatePayment(ctx context.Context, p *entity.Payment) (*entity.Payment, error)
+	HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) error
+}
+
+type paymentUsecase struct {
+	manager                transactor.Manager
+	providers              map[string]payment.PaymentProvider
+	paymentRepository      repository.PaymentRepository
+	refundRepository       repository.RefundRepository
+	productOrderRepository repository.ProductOrderRepository
+	telemedicineRepository repository.TelemedicineRepository
+	orderUsecase           OrderUsecase
+	telemedicineUsecase    TelemedicineUsecase
+}
+
+func NewPaymentUsecase(
+	manager transactor.Manager,
+	providers []payment.PaymentProvider,
+	paymentRepository repository.PaymentRepository,
+	refundRepository repository.RefundRepository,
+	productOrderRepository repository.ProductOrderRepository,
+	telemedicineRepository repository.TelemedicineRepository,
+	orderUsecase OrderUsecase,
+	telemedicineUsecase TelemedicineUsecase,
+) PaymentUsecase {
+	providerM := make(map[string]payment.PaymentProvider)
+	for _, provider := range providers {
+		providerM[provider.Name()] = provider
+	}
+	return &paymentUsecase{
+		manager:                manager,
+		providers:              providerM,
+		paymentRepository:      paymentRepository,
+		refundRepository:       refundRepository,
+		productOrderRepository: productOrderRepository,
+		telemedicineRepository: telemedicineRepository,
+		orderUsecase:           orderUsecase,
+		telemedicineUsecase:    telemedicineUsecase,
+	}
+}
+
+func (u *paymentUsecase) CreatePayment(ctx context.Context, p *entity.Payment) (*entity.Payment, error) {
+	userId := ctx.Value("user_id").(uint)
+	p.ProfileId = userId
+	if p.Provider == payment.ManualMethod {
+		return nil, apperror.NewClientError(errors.New("manual payment is made by uploading a payment proof"))
+	}
+	provider, ok := u.providers[p.Provider]
+	if !ok {
+		return nil, apperror.NewClientError(fmt.Errorf("payment provider %v not available", p.Provider))
+	}
+	paymentQuery := valueobject.NewQuery().
+		Condition("provider", valueobject.Equal, p.Provider).
+		Condition("status", valueobject.Equal, entity.PaymentPending)
+	switch p.PaymentType {
+	case entity.PaymentTypeOrder:
+		fetchedOrder, err := u.productOrderRepository.FindById(ctx, *p.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		if fetchedOrder == nil || fetchedOrder.ProfileId != userId {
+			return nil, apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", *p.OrderId))
+		}
+		if fetchedOrder.OrderStatusId != uint(entity.WaitingForPayment) {
+			return nil, apperror.NewClientError(apperror.NewResourceStateError("order is not waiting for payment"))
+		}
+		if fetchedOrder.ExpiredAt.Before(time.Now()) {
+			return nil, apperror.NewClientError(apperror.NewResourceStateError("order already expired"))
+		}
+		p.Amount = fetchedOrder.TotalPayment
+		p.Reference = fmt.Sprintf("order-%d-%s", fetchedOrder.Id, generateRandomString(8))
+		paymentQuery.Condition("order_id", valueobject.Equal, fetchedOrder.Id)
+	case entity.PaymentTypeTelemedicine:
+		fetchedTelemedicine, err := u.telemedicineRepository.FindById(ctx, *p.TelemedicineId)
+		if err != nil {
+			return nil, err
+		}
+		if fetchedTelemedicine == nil || fetchedTelemedicine.ProfileId != userId {
+			return nil, apperror.NewClientError(apperror.NewResourceNotFoundError("telemedicine", "id", *p.TelemedicineId))
+		}
+		if fetchedTelemedicine.Status != entity.Waiting {
+			return nil, apperror.NewClientError(fmt.Errorf("cannot pay because telemedicine already %v", fetchedTelemedicine.Status))
+		}
+		if fetchedTelemedicine.ExpiredAt.Before(time.Now()) {
+			return nil, apperror.NewClientError(errors.New("cannot pay because telemedecine already expired"))
+		}
+		p.Amount = fetchedTelemedicine.TotalPayment
+		p.Reference = fmt.Sprintf("telemedicine-%d-%s", fetchedTelemedicine.Id, generateRandomString(8))
+		paymentQuery.Condition("telemedicine_id", valueobject.Equal, fetchedTelemedicine.Id)
+	default:
+		return nil, apperror.NewClientError(fmt.Errorf("unknown payment type %v", p.PaymentType))
+	}
+	pendingPayment, err := u.paymentRepository.FindOne(ctx, paymentQuery)
+	if err != nil {
+		return nil, err
+	}
+	if pendingPayment != nil {
+		return pendingPayment, nil
+	}
+	charge, err := provider.CreateCharge(ctx, p.Reference, p.Amount)
+	if err != nil {
+		return nil, err
+	}
+	p.ExternalId = charge.ExternalId
+	p.PaymentUrl = charge.PaymentUrl
+	p.Status = entity.PaymentStatus(charge.Status)
+	return u.paymentRepository.Create(ctx, p)
+}
+
+func (u *paymentUsecase) HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) error {
+	paymentProvider, ok := u.providers[provider]
+	if !ok {
+		return apperror.NewClientError(fmt.Errorf("payment provider %v not available", provider)).NotFound()
+	}
+	notification, err := paymentProvider.VerifyWebhook(ctx, header, body)
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return apperror.NewClientError(err).Unauthorized()
+	}
+	if err != nil {
+		return apperror.NewClientError(err)
+	}
+	return u.manager.Run(ctx, func(c context.Context) error {
+		paymentQuery := valueobject.NewQuery().
+			Condition("reference", valueobject.Equal, notification.Reference).
+			Condition("provider", valueobject.Equal, provider).
+			Lock()
+		fetchedPayment, err := u.paymentRepository.FindOne(c, paymentQuery)
+		if err != nil {
+			return err
+		}
+		if fetchedPayment == nil {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("payment", "reference", notification.Reference)).NotFound()
+		}
+		if fetchedPayment.Status != entity.PaymentPending {
+			return nil
+		}
+		switch notification.Status {
+		case payment.StatusPaid:
+			if !notification.Amount.Equal(fetchedPayment.Amount) {
+				return apperror.NewClientError(errors.New("paid amount does not match payment amount"))
+			}
+			paidAt := time.Now()
+			fetchedPayment.Status = entity.PaymentPaid
+			fetchedPayment.PaidAt = &paidAt
+		case payment.StatusFailed:
+			fetchedPayment.Status = entity.PaymentFailed
+		default:
+			return nil
+		}
+		_, err = u.paymentRepository.Update(c, fetchedPayment)
+		if err != nil {
+			return err
+		}
+		if fetchedPayment.Status != entity.PaymentPaid {
+			return nil
+		}
+		if fetchedPayment.PaymentType == entity.PaymentTypeOrder {
+			err = u.orderUsecase.ConfirmPayment(c, *fetchedPayment.OrderId)
+		} else {
+			err = u.telemedicineUsecase.ConfirmPayment(c, *fetchedPayment.TelemedicineId)
+		}
+		var clientErr *apperror.ClientError
+		if !errors.As(err, &clientErr) {
+			return err
+		}
+		logger.Log.Info(fmt.Sprintf("payment %v received but cannot be applied: %v", fetchedPayment.Reference, err))
+		_, err = u.refundRepository.Create(c, &entity.Refund{
+			ProfileId:      fetchedPayment.ProfileId,
+			PaymentType:    fetchedPayment.PaymentType,
+			OrderId:        fetchedPayment.OrderId,
+			TelemedicineId: fetchedPayment.TelemedicineId,
+			PaymentId:      &fetchedPayment.Id,
+			Amount:         fetchedPayment.Amount,
+			Reason:         clientErr.Error(),
+			Status:         entity.RefundRequested,
+		})
+		return err
+	})
+}
+
+func chargeManually(ctx context.Context, p *entity.Payment) (*entity.Payment, error) {
+	provider, err := payment.NewPaymentProvider(payment.ManualMethod)
+	if err != nil {
+		return nil, err
+	}
+	charge, err := provider.CreateCharge(ctx, p.Reference, p.Amount)
+	if err != nil {
+		return nil, err
+	}
+	p.Provider = provider.Name()
+	p.ExternalId = charge.ExternalId
+	p.Status = entity.PaymentStatus(charge.Status)
+	return p, nil
+}
@@ -21,10 +21,12 @@ type pharmacyProductUsecase struct {
 	pharmacyProductRepository repository.PharmacyProductRepository
 	productRepository         repository.ProductRepository
 	pharmacyRepository        repository.PharmacyRepository
+	reservationRepository     repository.StockReservationRepository
+	staffRepository           repository.PharmacyStaffRepository
 }
 
-func NewPharmacyProductUsecase(rp repository.PharmacyProductRepository, pr repository.PharmacyRepository, p repository.ProductRepository) PharmacyProductUsecase {
-	return &pharmacyProductUsecase{pharmacyProductRepository: rp, productRepository: p, pharmacyRepository: pr}
+func NewPharmacyProductUsecase(rp repository.PharmacyProductRepository, pr repository.PharmacyRepository, p repository.ProductRepository, sr repository.StockReservationRepository, psr repository.PharmacyStaffRepository) PharmacyProductUsecase {
+	return &pharmacyProductUsecase{pharmacyProductRepository: rp, productRepository: p, pharmacyRepository: pr, reservationRepository: sr, staffRepository: psr}
 }
 
 func (u *pharmacyProductUsecase) FindAllPharmacyProduct(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
@@ -36,10 +38,22 @@ func (u *pharmacyProductUsecase) FindAllPharmacyProduct(ctx context.Context, que
 	if checkPharmacy == nil {
 		return nil, apperror.NewResourceNotFoundError("pharmacy", "id", idPharmacy)
 	}
-	if checkPharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, idPharmacy, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionPharmacyProductRead) {
 		return nil, apperror.NewForbiddenActionError("dont have access to this pharmacy")
 	}
-	return u.pharmacyProductRepository.FindAllPharmacyProducts(ctx, query)
+	pagedResult, err := u.pharmacyProductRepository.FindAllPharmacyProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = u.fillAvailableStock(ctx, pagedResult.Data.([]*entity.PharmacyProduct)...)
+	if err != nil {
+		return nil, err
+	}
+	return pagedResult, nil
 }
 
 func (u *pharmacyProductUsecase) FindOnePharmacyPeoduct(ctx context.Context, pharmacyProduct *entity.PharmacyProduct) (*entity.PharmacyProduct, error) {
@@ -55,9 +69,17 @@ func (u *pharmacyProductUsecase) FindOnePharmacyPeoduct(ctx context.Context, pha
 	if selectPharmacyProduct == nil {
 		return nil, apperror.NewResourceNotFoundError("pharmacy product", "id", pharmacyProduct.ProductId)
 	}
-	if selectPharmacyProduct.Pharmacy.AdminId != userId {
+	staff, err := u.staffRepository.FindMember(ctx, selectPharmacyProduct.PharmacyId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionPharmacyProductRead) {
 		return nil, apperror.NewResourceNotFoundError("pharmacy product", "id", pharmacyProduct.ProductId)
 	}
+	err = u.fillAvailableStock(ctx, selectPharmacyProduct)
+	if err != nil {
+		return nil, err
+	}
 	return selectPharmacyProduct, nil
 }
 
@@ -89,7 +111,11 @@ func (u *pharmacyProductUsecase) CreatePharmacyProduct(ctx context.Context, phar
 		return nil, apperror.NewClientError(fmt.Errorf("cannot add duplicate product on this pharmacy id %v", pharmacyProduct.PharmacyId))
 	}
 
-	if pharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, pharmacy.Id, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionPharmacyProductCreate) {
 		return nil, apperror.NewForbiddenActionError("cannot have access to add product to this pharmacy")
 	}
 
@@ -120,7 +146,11 @@ func (u *pharmacyProductUsecase) UpdatePharmacyProduct(ctx context.Context, phar
 		return nil, apperror.NewResourceNotFoundError("pharmacy product", "id", pharmacyProduct.Id)
 	}
 
-	if pharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, pharmacy.Id, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionPharmacyProductUpdate) {
 		return nil, apperror.NewForbiddenActionError("cannot have access to add profuct to this pharmacy")
 	}
 	pharmacyProduct.ProductId = checkPharProduct.ProductId
@@ -132,3 +162,21 @@ func (u *pharmacyProductUsecase) UpdatePharmacyProduct(ctx context.Context, phar
 
 	return newPharmacyProduct, nil
 }
+
+func (u *pharmacyProductUsecase) fillAvailableStock(ctx context.Context, pharmacyProducts ...*entity.PharmacyProduct) error {
+	if len(pharmacyProducts) == 0 {
+		return nil
+	}
+	var listOfPharmacyProductId []uint
+	for _, pharmacyProduct := range pharmacyProducts {
+		listOfPharmacyProductId = append(listOfPharmacyProductId, pharmacyProduct.Id)
+	}
+	heldM, err := u.reservationRepository.FindHeldQuantity(ctx, listOfPharmacyProductId)
+	if err != nil {
+		return err
+	}
+	for _, pharmacyProduct := range pharmacyProducts {
+		pharmacyProduct.AvailableStock = pharmacyProduct.Stock - heldM[pharmacyProduct.Id]
+	}
+	return nil
+}
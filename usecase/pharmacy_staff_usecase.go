@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type PharmacyStaffUsecase interface {
+	ListStaff(ctx context.Context, pharmacyId uint) ([]*entity.PharmacyStaff, error)
+	InviteStaff(ctx context.Context, staff *entity.PharmacyStaff) (*entity.PharmacyStaff, error)
+	RemoveStaff(ctx context.Context, pharmacyId, userId uint) error
+}
+
+type pharmacyStaffUsecase struct {
+	manager            transactor.Manager
+	staffRepository    repository.PharmacyStaffRepository
+	pharmacyRepository repository.PharmacyRepository
+	userRepository     repository.UserRepository
+}
+
+func NewPharmacyStaffUsecase(m transactor.Manager, sr repository.PharmacyStaffRepository, pr repository.PharmacyRepository, ur repository.UserRepository) PharmacyStaffUsecase {
+	return &pharmacyStaffUsecase{manager: m, staffRepository: sr, pharmacyRepository: pr, userRepository: ur}
+}
+
+func (u *pharmacyStaffUsecase) ListStaff(ctx context.Context, pharmacyId uint) ([]*entity.PharmacyStaff, error) {
+	err := u.checkAccess(ctx, pharmacyId, entity.PermissionPharmacyRead)
+	if err != nil {
+		return nil, err
+	}
+	query := valueobject.NewQuery().
+		Condition("pharmacy_id", valueobject.Equal, pharmacyId).
+		WithPreload("User.AdminContact").
+		WithSortBy("id")
+	return u.staffRepository.Find(ctx, query)
+}
+
+func (u *pharmacyStaffUsecase) InviteStaff(ctx context.Context, staff *entity.PharmacyStaff) (*entity.PharmacyStaff, error) {
+	userId := ctx.Value("user_id").(uint)
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		err := u.checkAccess(c, staff.PharmacyId, entity.PermissionPharmacyStaffManage)
+		if err != nil {
+			return err
+		}
+		invitee, err := u.userRepository.FindOne(c, valueobject.NewQuery().
+			Condition("email", valueobject.Equal, staff.User.Email).
+			WithPreload("Role").
+			WithPreload("AdminContact"))
+		if err != nil {
+			return err
+		}
+		if invitee == nil {
+			return apperror.NewResourceNotFoundError("user", "email", staff.User.Email)
+		}
+		if invitee.Role == nil || invitee.Role.GetBaseRoleId() != entity.RoleAdmin {
+			return apperror.NewClientError(errors.New("only pharmacy admin accounts can join a pharmacy staff"))
+		}
+		checkStaff, err := u.staffRepository.FindMember(c, staff.PharmacyId, invitee.Id)
+		if err != nil {
+			return err
+		}
+		if checkStaff != nil {
+			return apperror.NewResourceAlreadyExistError("pharmacy staff", "email", invitee.Email)
+		}
+		staff.UserId = invitee.Id
+		staff.User = nil
+		staff.InvitedBy = &userId
+		_, err = u.staffRepository.Create(c, staff)
+		if err != nil {
+			return err
+		}
+		staff.User = invitee
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+func (u *pharmacyStaffUsecase) RemoveStaff(ctx context.Context, pharmacyId, userId uint) error {
+	return u.manager.Run(ctx, func(c context.Context) error {
+		err := u.checkAccess(c, pharmacyId, entity.PermissionPharmacyStaffManage)
+		if err != nil {
+			return err
+		}
+		staffs, err := u.staffRepository.Find(c, valueobject.NewQuery().Condition("pharmacy_id", valueobject.Equal, pharmacyId))
+		if err != nil {
+			return err
+		}
+		var removed *entity.PharmacyStaff
+		var owners int
+		for _, staff := range staffs {
+			if staff.UserId == userId {
+				removed = staff
+			}
+			if staff.Role == entity.PharmacyOwner {
+				owners++
+			}
+		}
+		if removed == nil {
+			return apperror.NewResourceNotFoundError("pharmacy staff", "user id", userId)
+		}
+		if removed.Role == entity.PharmacyOwner && owners == 1 {
+			return apperror.NewResourceStateError("pharmacy must keep at least one owner")
+		}
+		return u.staffRepository.Delete(c, removed)
+	})
+}
+
+func (u *pharmacyStaffUsecase) checkAccess(ctx context.Context, pharmacyId uint, permission string) error {
+	pharmacy, err := u.pharmacyRepository.FindOne(ctx, valueobject.NewQuery().Condition("id", valueobject.Equal, pharmacyId).Lock())
+	if err != nil {
+		return err
+	}
+	if pharmacy == nil {
+		return apperror.NewResourceNotFoundError("pharmacy", "id", pharmacyId)
+	}
+	staff, err := u.staffRepository.FindMember(ctx, pharmacy.Id, ctx.Value("user_id").(uint))
+	if err != nil {
+		return err
+	}
+	if !staff.Can(permission) {
+		return apperror.NewForbiddenActionError("dont have access to this pharmacy")
+	}
+	return nil
+}
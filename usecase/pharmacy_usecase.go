@@ -7,6 +7,7 @@ import (
 	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
 	"github.com/night1010/everhealth/valueobject"
 )
 
@@ -23,10 +24,12 @@ type pharmacyUsecase struct {
 	pharmacyRepository repository.PharmacyRepository
 	provinceRepository repository.ProvinceRepository
 	cityRepository     repository.CityRepository
+	staffRepository    repository.PharmacyStaffRepository
+	manager            transactor.Manager
 }
 
-func NewPharmacyUsecase(rp repository.PharmacyRepository, pr repository.ProvinceRepository, cr repository.CityRepository) PharmacyUsecase {
-	return &pharmacyUsecase{pharmacyRepository: rp, provinceRepository: pr, cityRepository: cr}
+func NewPharmacyUsecase(rp repository.PharmacyRepository, pr repository.ProvinceRepository, cr repository.CityRepository, sr repository.PharmacyStaffRepository, m transactor.Manager) PharmacyUsecase {
+	return &pharmacyUsecase{pharmacyRepository: rp, provinceRepository: pr, cityRepository: cr, staffRepository: sr, manager: m}
 }
 
 func (u *pharmacyUsecase) FindAllPharmacy(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
@@ -68,11 +71,18 @@ func (u *pharmacyUsecase) CreatePharmacy(ctx context.Context, pharmacy *entity.P
 		return nil, apperror.NewClientError(fmt.Errorf("city with id %v doesn't belong to province id %v", city.Id, pharmacy.ProvinceId))
 	}
 	pharmacy.AdminId = ctx.Value("user_id").(uint)
-	newPharmacy, err := u.pharmacyRepository.Create(ctx, pharmacy)
+	err = u.manager.Run(ctx, func(c context.Context) error {
+		_, err := u.pharmacyRepository.Create(c, pharmacy)
+		if err != nil {
+			return err
+		}
+		_, err = u.staffRepository.Create(c, &entity.PharmacyStaff{PharmacyId: pharmacy.Id, UserId: pharmacy.AdminId, Role: entity.PharmacyOwner})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return newPharmacy, nil
+	return pharmacy, nil
 }
 
 func (u *pharmacyUsecase) UpdatePharmacy(ctx context.Context, pharmacy *entity.Pharmacy) (*entity.Pharmacy, error) {
@@ -106,7 +116,11 @@ func (u *pharmacyUsecase) UpdatePharmacy(ctx context.Context, pharmacy *entity.P
 		return nil, apperror.NewClientError(fmt.Errorf("city with id %v doesn't belong to province id %v", city.Id, pharmacy.ProvinceId))
 	}
 
-	if checkPharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, checkPharmacy.Id, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionPharmacyWrite) {
 		return nil, apperror.NewForbiddenActionError("cannot have access to update this pharmacy")
 	}
 
@@ -126,8 +140,18 @@ func (u *pharmacyUsecase) DeletePharmacy(ctx context.Context, pharmacy entity.Ph
 	if checkPharmacy == nil {
 		return apperror.NewResourceNotFoundError("product category", "id", pharmacy.Id)
 	}
-	if checkPharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, checkPharmacy.Id, ctx.Value("user_id").(uint))
+	if err != nil {
+		return err
+	}
+	if !staff.Can(entity.PermissionPharmacyWrite) {
 		return apperror.NewForbiddenActionError("cannot have access to delete this pharmacy")
 	}
-	return u.pharmacyRepository.Delete(ctx, &pharmacy)
+	return u.manager.Run(ctx, func(c context.Context) error {
+		err := u.staffRepository.DeleteByPharmacyId(c, checkPharmacy.Id)
+		if err != nil {
+			return err
+		}
+		return u.pharmacyRepository.Delete(c, &pharmacy)
+	})
 }
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/imagehelper"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type ProductReviewUsecase interface {
+	CreateReview(ctx context.Context, review *entity.ProductReview) (*entity.ProductReview, error)
+	ListReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	ModerateReview(ctx context.Context, review *entity.ProductReview) (*entity.ProductReview, error)
+}
+
+type productReviewUsecase struct {
+	manager                transactor.Manager
+	imageHelper            imagehelper.ImageHelper
+	reviewRepository       repository.ProductReviewRepository
+	photoRepository        repository.ProductReviewPhotoRepository
+	productRepository      repository.ProductRepository
+	productOrderRepository repository.ProductOrderRepository
+	orderItemRepository    repository.OrderItemRepository
+}
+
+func NewProductReviewUsecase(
+	manager transactor.Manager,
+	imageHelper imagehelper.ImageHelper,
+	reviewRepository repository.ProductReviewRepository,
+	photoRepository repository.ProductReviewPhotoRepository,
+	productRepository repository.ProductRepository,
+	productOrderRepository repository.ProductOrderRepository,
+	orderItemRepository repository.OrderItemRepository,
+) ProductReviewUsecase {
+	return &productReviewUsecase{
+		manager:                manager,
+		imageHelper:            imageHelper,
+		reviewRepository:       reviewRepository,
+		photoRepository:        photoRepository,
+		productRepository:      productRepository,
+		productOrderRepository: productOrderRepository,
+		orderItemRepository:    orderItemRepository,
+	}
+}
+
+func (u *productReviewUsecase) CreateReview(ctx context.Context, review *entity.ProductReview) (*entity.ProductReview, error) {
+	profileId := ctx.Value("user_id").(uint)
+	var imageKeys []string
+	var imageUrls []string
+	images, _ := ctx.Value("images").([]multipart.File)
+	for _, image := range images {
+		imageKey := entity.ProductReviewPrefix + generateRandomString(10)
+		imgUrl, err := u.imageHelper.Upload(ctx, image, entity.ProductReviewFolder, imageKey)
+		if err != nil {
+			err2 := u.destroyPhotos(ctx, imageKeys)
+			if err2 != nil {
+				return nil, err2
+			}
+			return nil, err
+		}
+		imageKeys = append(imageKeys, imageKey)
+		imageUrls = append(imageUrls, imgUrl)
+	}
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		order, err := u.productOrderRepository.FindById(c, review.ProductOrderId)
+		if err != nil {
+			return err
+		}
+		if order == nil || order.ProfileId != profileId {
+			return apperror.NewResourceNotFoundError("order", "id", review.ProductOrderId)
+		}
+		if order.OrderStatusId != uint(entity.OrderConfirmed) {
+			return apperror.NewResourceStateError("can only review product of confirmed order")
+		}
+		itemQuery := valueobject.NewQuery().
+			Condition("order_id", valueobject.Equal, order.Id).
+			Condition("\"PharmacyProduct\".product_id", valueobject.Equal, review.ProductId).
+			WithJoin("PharmacyProduct")
+		item, err := u.orderItemRepository.FindOne(c, itemQuery)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return apperror.NewClientError(errors.New("product is not part of this order"))
+		}
+		product, err := u.productRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, review.ProductId).Lock())
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return apperror.NewResourceNotFoundError("product", "id", review.ProductId)
+		}
+		checkReview, err := u.reviewRepository.FindOne(c, valueobject.NewQuery().
+			Condition("product_order_id", valueobject.Equal, order.Id).
+			Condition("product_id", valueobject.Equal, review.ProductId))
+		if err != nil {
+			return err
+		}
+		if checkReview != nil {
+			return apperror.NewResourceAlreadyExistError("review", "product id", review.ProductId)
+		}
+		review.ProfileId = profileId
+		_, err = u.reviewRepository.Create(c, review)
+		if err != nil {
+			return err
+		}
+		for i, imageKey := range imageKeys {
+			photo, err := u.photoRepository.Create(c, &entity.ProductReviewPhoto{ProductReviewId: review.Id, Image: imageUrls[i], ImageKey: imageKey})
+			if err != nil {
+				return err
+			}
+			review.Photos = append(review.Photos, photo)
+		}
+		return u.updateProductRating(c, product)
+	})
+	if err != nil {
+		err2 := u.destroyPhotos(ctx, imageKeys)
+		if err2 != nil {
+			return nil, err2
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+func (u *productReviewUsecase) ListReviews(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return u.reviewRepository.FindAllReviews(ctx, query)
+}
+
+func (u *productReviewUsecase) ModerateReview(ctx context.Context, review *entity.ProductReview) (*entity.ProductReview, error) {
+	var fetchedReview *entity.ProductReview
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		var err error
+		fetchedReview, err = u.reviewRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, review.Id).Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedReview == nil {
+			return apperror.NewResourceNotFoundError("review", "id", review.Id)
+		}
+		if fetchedReview.IsHidden == review.IsHidden {
+			return nil
+		}
+		product, err := u.productRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, fetchedReview.ProductId).Lock())
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return apperror.NewResourceNotFoundError("product", "id", fetchedReview.ProductId)
+		}
+		fetchedReview.IsHidden = review.IsHidden
+		_, err = u.reviewRepository.Update(c, fetchedReview)
+		if err != nil {
+			return err
+		}
+		return u.updateProductRating(c, product)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fetchedReview, nil
+}
+
+func (u *productReviewUsecase) updateProductRating(ctx context.Context, product *entity.Product) error {
+	average, count, err := u.reviewRepository.FindRatingSummary(ctx, product.Id)
+	if err != nil {
+		return err
+	}
+	product.RatingAverage = average
+	product.ReviewCount = count
+	_, err = u.productRepository.Update(ctx, product)
+	return err
+}
+
+func (u *productReviewUsecase) destroyPhotos(ctx context.Context, imageKeys []string) error {
+	for _, imageKey := range imageKeys {
+		err := u.imageHelper.Destroy(ctx, entity.ProductReviewFolder, imageKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
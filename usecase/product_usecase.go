@@ -241,6 +241,8 @@ func (u *productUsecase) UpdateProduct(ctx context.Context, product *entity.Prod
 	}
 	product.Image = fetchedProduct.Image
 	product.ImageKey = fetchedProduct.ImageKey
+	product.RatingAverage = fetchedProduct.RatingAverage
+	product.ReviewCount = fetchedProduct.ReviewCount
 	err = u.manager.Run(ctx, func(c context.Context) error {
 		image := ctx.Value("image")
 		if image != nil {
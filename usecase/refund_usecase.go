@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/payment"
+	"github.com/night1010/everhealth/rbac"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+)
+
+type RefundUsecase interface {
+	ListRefunds(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	RequestItemRefund(ctx context.Context, refund *entity.Refund) (*entity.Refund, error)
+	ApproveRefund(ctx context.Context, refundId uint) error
+	MarkRefundPaid(ctx context.Context, refundId uint) error
+}
+
+type refundUsecase struct {
+	manager           transactor.Manager
+	providers         map[string]payment.PaymentProvider
+	refundRepo        repository.RefundRepository
+	paymentRepo       repository.PaymentRepository
+	productOrderRepo  repository.ProductOrderRepository
+	orderShipmentRepo repository.OrderShipmentRepository
+	orderItemRepo     repository.OrderItemRepository
+	staffRepo         repository.PharmacyStaffRepository
+}
+
+func NewRefundUsecase(
+	manager transactor.Manager,
+	providers []payment.PaymentProvider,
+	refundRepo repository.RefundRepository,
+	paymentRepo repository.PaymentRepository,
+	productOrderRepo repository.ProductOrderRepository,
+	orderShipmentRepo repository.OrderShipmentRepository,
+	orderItemRepo repository.OrderItemRepository,
+	staffRepo repository.PharmacyStaffRepository,
+) RefundUsecase {
+	providerM := make(map[string]payment.PaymentProvider)
+	for _, provider := range providers {
+		providerM[provider.Name()] = provider
+	}
+	return &refundUsecase{
+		manager:           manager,
+		providers:         providerM,
+		refundRepo:        refundRepo,
+		paymentRepo:       paymentRepo,
+		productOrderRepo:  productOrderRepo,
+		orderShipmentRepo: orderShipmentRepo,
+		orderItemRepo:     orderItemRepo,
+		staffRepo:         staffRepo,
+	}
+}
+
+func (u *refundUsecase) ListRefunds(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return u.refundRepo.FindAllRefunds(ctx, query)
+}
+
+func (u *refundUsecase) RequestItemRefund(ctx context.Context, refund *entity.Refund) (*entity.Refund, error) {
+	userId := ctx.Value("user_id").(uint)
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		orderQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, *refund.OrderId).Lock()
+		fetchedOrder, err := u.productOrderRepo.FindOne(c, orderQuery)
+		if err != nil {
+			return err
+		}
+		if fetchedOrder == nil {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", *refund.OrderId))
+		}
+		var listOfOrderItemId []uint
+		for _, item := range refund.RefundItems {
+			listOfOrderItemId = append(listOfOrderItemId, item.OrderItemId)
+		}
+		itemQuery := valueobject.NewQuery().
+			Condition("id", valueobject.In, listOfOrderItemId).
+			Condition("order_id", valueobject.Equal, fetchedOrder.Id)
+		fetchedItems, err := u.orderItemRepo.Find(c, itemQuery)
+		if err != nil {
+			return err
+		}
+		itemM := make(map[uint]*entity.OrderItem)
+		for _, item := range fetchedItems {
+			itemM[item.Id] = item
+		}
+		var shipmentId uint
+		for _, item := range refund.RefundItems {
+			orderItem, ok := itemM[item.OrderItemId]
+			if !ok {
+				return apperror.NewClientError(apperror.NewResourceNotFoundError("order item", "id", item.OrderItemId))
+			}
+			if shipmentId != 0 && shipmentId != orderItem.ShipmentId {
+				return apperror.NewClientError(apperror.NewResourceStateError("refund items must belong to the same shipment"))
+			}
+			shipmentId = orderItem.ShipmentId
+		}
+		fetchedShipments, err := u.orderShipmentRepo.FindAdminShipments(c, fetchedOrder.Id, shipmentId, userId, entity.PermissionRefundRequest)
+		if err != nil {
+			return err
+		}
+		if len(fetchedShipments) == 0 {
+			return apperror.NewClientError(apperror.NewResourceNotFoundError("order", "id", fetchedOrder.Id))
+		}
+		shipment := fetchedShipments[0]
+		if shipment.OrderStatusId != uint(entity.WaitingForPaymentConfirmation) && shipment.OrderStatusId != uint(entity.Processed) {
+			return apperror.NewClientError(apperror.NewResourceStateError("can only refund items of a paid shipment that has not been sent"))
+		}
+		fetchedPayment, err := u.findRefundedPayment(c, refund)
+		if err != nil {
+			return err
+		}
+		if fetchedPayment == nil {
+			return apperror.NewClientError(apperror.NewResourceStateError("shipment payment has not been verified"))
+		}
+		refundedM, err := u.refundRepo.FindRefundedQuantity(c, listOfOrderItemId)
+		if err != nil {
+			return err
+		}
+		amount := decimal.Zero
+		paidRatio := decimal.NewFromInt(1)
+		if shipment.SubTotal.IsPositive() {
+			paidRatio = shipment.SubTotal.Sub(shipment.Discount).Div(shipment.SubTotal)
+		}
+		for _, item := range refund.RefundItems {
+			orderItem := itemM[item.OrderItemId]
+			if refundedM[orderItem.Id]+item.Quantity > orderItem.Quantity {
+				return apperror.NewClientError(apperror.NewResourceStateError(fmt.Sprintf("refund quantity exceeds ordered quantity for item %d", orderItem.Id)))
+			}
+			refundedM[orderItem.Id] += item.Quantity
+			item.Amount = orderItem.SubTotal.Div(decimal.NewFromInt(int64(orderItem.Quantity))).Mul(decimal.NewFromInt(int64(item.Quantity))).Mul(paidRatio).Round(2)
+			amount = amount.Add(item.Amount)
+		}
+		refund.ProfileId = fetchedOrder.ProfileId
+		refund.PaymentId = &fetchedPayment.Id
+		refund.PaymentType = entity.PaymentTypeOrder
+		refund.ShipmentId = &shipment.Id
+		refund.Amount = amount
+		refund.Status = entity.RefundRequested
+		_, err = u.refundRepo.Create(c, refund)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+func (u *refundUsecase) ApproveRefund(ctx context.Context, refundId uint) error {
+	userId := ctx.Value("user_id").(uint)
+	return u.manager.Run(ctx, func(c context.Context) error {
+		fetchedRefund, err := u.findManagedRefund(c, refundId)
+		if err != nil {
+			return err
+		}
+		if fetchedRefund.Status != entity.RefundRequested {
+			return apperror.NewClientError(apperror.NewResourceStateError(fmt.Sprintf("refund already %v", fetchedRefund.Status)))
+		}
+		approvedAt := time.Now()
+		fetchedRefund.Status = entity.RefundApproved
+		fetchedRefund.ApprovedBy = &userId
+		fetchedRefund.ApprovedAt = &approvedAt
+		_, err = u.refundRepo.Update(c, fetchedRefund)
+		return err
+	})
+}
+
+func (u *refundUsecase) MarkRefundPaid(ctx context.Context, refundId uint) error {
+	return u.manager.Run(ctx, func(c context.Context) error {
+		fetchedRefund, err := u.findManagedRefund(c, refundId)
+		if err != nil {
+			return err
+		}
+		if fetchedRefund.Status != entity.RefundApproved {
+			return apperror.NewClientError(apperror.NewResourceStateError("refund must be approved before it is paid"))
+		}
+		fetchedPayment, err := u.findRefundedPayment(c, fetchedRefund)
+		if err != nil {
+			return err
+		}
+		paidAt := time.Now()
+		fetchedRefund.Status = entity.RefundPaid
+		fetchedRefund.PaidAt = &paidAt
+		if fetchedPayment != nil {
+			fetchedRefund.PaymentId = &fetchedPayment.Id
+		}
+		_, err = u.refundRepo.Update(c, fetchedRefund)
+		if err != nil {
+			return err
+		}
+		if fetchedPayment == nil {
+			return nil
+		}
+		refunded, err := u.refundRepo.SumPaidAmountByPayment(c, fetchedPayment.Id)
+		if err != nil {
+			return err
+		}
+		if refunded.GreaterThan(fetchedPayment.Amount) {
+			return apperror.NewClientError(apperror.NewResourceStateError("refund amount exceeds paid amount"))
+		}
+		if refunded.Equal(fetchedPayment.Amount) {
+			fetchedPayment.Status = entity.PaymentRefunded
+			_, err = u.paymentRepo.Update(c, fetchedPayment)
+			if err != nil {
+				return err
+			}
+		}
+		provider, ok := u.providers[fetchedPayment.Provider]
+		if !ok {
+			return apperror.NewClientError(fmt.Errorf("payment provider %v not available", fetchedPayment.Provider))
+		}
+		return provider.Refund(c, fetchedPayment.ExternalId, fetchedRefund.Amount)
+	})
+}
+
+func (u *refundUsecase) findManagedRefund(c context.Context, refundId uint) (*entity.Refund, error) {
+	refundQuery := valueobject.NewQuery().
+		Condition("id", valueobject.Equal, refundId).
+		WithPreload("Shipment").
+		Lock()
+	fetchedRefund, err := u.refundRepo.FindOne(c, refundQuery)
+	if err != nil {
+		return nil, err
+	}
+	if fetchedRefund == nil {
+		return nil, apperror.NewClientError(apperror.NewResourceNotFoundError("refund", "id", refundId))
+	}
+	if rbac.Can(c, entity.PermissionRefundProcessAll) {
+		return fetchedRefund, nil
+	}
+	if fetchedRefund.Shipment == nil {
+		return nil, apperror.NewClientError(apperror.NewResourceNotFoundError("refund", "id", refundId))
+	}
+	staff, err := u.staffRepo.FindMember(c, fetchedRefund.Shipment.PharmacyId, c.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionRefundProcess) {
+		return nil, apperror.NewClientError(apperror.NewResourceNotFoundError("refund", "id", refundId))
+	}
+	return fetchedRefund, nil
+}
+
+func (u *refundUsecase) findRefundedPayment(c context.Context, refund *entity.Refund) (*entity.Payment, error) {
+	paymentQuery := valueobject.NewQuery().Lock()
+	if refund.PaymentId != nil {
+		paymentQuery.Condition("id", valueobject.Equal, *refund.PaymentId)
+	} else if refund.OrderId != nil {
+		paymentQuery.
+			Condition("order_id", valueobject.Equal, *refund.OrderId).
+			Condition("status", valueobject.Equal, entity.PaymentPaid)
+	} else {
+		paymentQuery.
+			Condition("telemedicine_id", valueobject.Equal, *refund.TelemedicineId).
+			Condition("status", valueobject.Equal, entity.PaymentPaid)
+	}
+	return u.paymentRepo.FindOne(c, paymentQuery)
+}
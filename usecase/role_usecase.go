@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/rbac"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+type RoleUsecase interface {
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+	ListPermissions(ctx context.Context) ([]*entity.Permission, error)
+	CreateRole(ctx context.Context, role *entity.Role) (*entity.Role, error)
+	UpdateRole(ctx context.Context, role *entity.Role) (*entity.Role, error)
+	DeleteRole(ctx context.Context, roleId entity.RoleId) error
+	AssignRole(ctx context.Context, userId uint, roleId entity.RoleId) error
+}
+
+type roleUsecase struct {
+	manager              transactor.Manager
+	roleRepository       repository.RoleRepository
+	permissionRepository repository.PermissionRepository
+	userRepository       repository.UserRepository
+	roleResolver         *rbac.Resolver
+}
+
+func NewRoleUsecase(m transactor.Manager, rr repository.RoleRepository, pr repository.PermissionRepository, ur repository.UserRepository, rs *rbac.Resolver) RoleUsecase {
+	return &roleUsecase{manager: m, roleRepository: rr, permissionRepository: pr, userRepository: ur, roleResolver: rs}
+}
+
+func (u *roleUsecase) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	return u.roleRepository.Find(ctx, valueobject.NewQuery().WithSortBy("id").WithPreload("Permissions"))
+}
+
+func (u *roleUsecase) ListPermissions(ctx context.Context) ([]*entity.Permission, error) {
+	return u.permissionRepository.Find(ctx, valueobject.NewQuery().WithSortBy("name"))
+}
+
+func (u *roleUsecase) CreateRole(ctx context.Context, role *entity.Role) (*entity.Role, error) {
+	if !role.BaseRoleId.IsStaff() {
+		return nil, apperror.NewClientError(fmt.Errorf("base role id %v is not a staff role", role.BaseRoleId))
+	}
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		permissions, err := u.findPermissions(c, role.Permissions)
+		if err != nil {
+			return err
+		}
+		err = u.checkRoleName(c, role)
+		if err != nil {
+			return err
+		}
+		role.IsSystem = false
+		role.Permissions = nil
+		_, err = u.roleRepository.Create(c, role)
+		if err != nil {
+			return err
+		}
+		role.Permissions = permissions
+		return u.roleRepository.ReplacePermissions(c, role, permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (u *roleUsecase) UpdateRole(ctx context.Context, role *entity.Role) (*entity.Role, error) {
+	var fetchedRole *entity.Role
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		var err error
+		fetchedRole, err = u.roleRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, role.Id).Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedRole == nil {
+			return apperror.NewResourceNotFoundError("role", "id", role.Id)
+		}
+		if fetchedRole.IsSystem {
+			return apperror.NewResourceStateError("system role cannot be modified")
+		}
+		permissions, err := u.findPermissions(c, role.Permissions)
+		if err != nil {
+			return err
+		}
+		err = u.checkRoleName(c, role)
+		if err != nil {
+			return err
+		}
+		fetchedRole.Name = role.Name
+		_, err = u.roleRepository.Update(c, fetchedRole)
+		if err != nil {
+			return err
+		}
+		fetchedRole.Permissions = permissions
+		return u.roleRepository.ReplacePermissions(c, fetchedRole, permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.roleResolver.Invalidate(fetchedRole.Id)
+	return fetchedRole, nil
+}
+
+func (u *roleUsecase) DeleteRole(ctx context.Context, roleId entity.RoleId) error {
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		fetchedRole, err := u.roleRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, roleId).Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedRole == nil {
+			return apperror.NewResourceNotFoundError("role", "id", roleId)
+		}
+		if fetchedRole.IsSystem {
+			return apperror.NewResourceStateError("system role cannot be deleted")
+		}
+		members, err := u.roleRepository.CountMembers(c, roleId)
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			return apperror.NewResourceStateError(fmt.Sprintf("role is still assigned to %d users", members))
+		}
+		return u.roleRepository.Delete(c, fetchedRole)
+	})
+	if err != nil {
+		return err
+	}
+	u.roleResolver.Invalidate(roleId)
+	return nil
+}
+
+func (u *roleUsecase) AssignRole(ctx context.Context, userId uint, roleId entity.RoleId) error {
+	if userId == ctx.Value("user_id").(uint) {
+		return apperror.NewForbiddenActionError("cannot change your own role")
+	}
+	return u.manager.Run(ctx, func(c context.Context) error {
+		role, err := u.roleRepository.FindById(c, uint(roleId))
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return apperror.NewResourceNotFoundError("role", "id", roleId)
+		}
+		if !role.GetBaseRoleId().IsStaff() {
+			return apperror.NewClientError(fmt.Errorf("role id %v is not a staff role", roleId))
+		}
+		fetchedUser, err := u.userRepository.FindOne(c, valueobject.NewQuery().
+			Condition("\"users\".id", valueobject.Equal, userId).
+			WithJoin("Role").
+			Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedUser == nil {
+			return apperror.NewResourceNotFoundError("user", "id", userId)
+		}
+		if fetchedUser.Role == nil || !fetchedUser.Role.GetBaseRoleId().IsStaff() {
+			return apperror.NewResourceStateError("only staff accounts can be assigned a custom role")
+		}
+		if fetchedUser.Role.GetBaseRoleId() != role.GetBaseRoleId() {
+			return apperror.NewResourceStateError("cannot move user to a role with a different base role")
+		}
+		fetchedUser.Role = nil
+		fetchedUser.RoleId = roleId
+		fetchedUser.TokenVersion++
+		_, err = u.userRepository.Update(c, fetchedUser)
+		return err
+	})
+}
+
+func (u *roleUsecase) findPermissions(ctx context.Context, requested []*entity.Permission) ([]*entity.Permission, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, permission := range requested {
+		if !seen[permission.Name] {
+			seen[permission.Name] = true
+			names = append(names, permission.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	permissions, err := u.permissionRepository.Find(ctx, valueobject.NewQuery().Condition("name", valueobject.In, names))
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(names) {
+		found := make(map[string]bool)
+		for _, permission := range permissions {
+			found[permission.Name] = true
+		}
+		var unknown []string
+		for _, name := range names {
+			if !found[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		return nil, apperror.NewClientError(fmt.Errorf("unknown permissions: %s", strings.Join(unknown, ", ")))
+	}
+	return permissions, nil
+}
+
+func (u *roleUsecase) checkRoleName(ctx context.Context, role *entity.Role) error {
+	checkRole, err := u.roleRepository.FindOne(ctx, valueobject.NewQuery().Condition("name", valueobject.Equal, role.Name))
+	if err != nil {
+		return err
+	}
+	if checkRole != nil && checkRole.Id != role.Id {
+		return apperror.NewResourceAlreadyExistError("role", "name", role.Name)
+	}
+	return nil
+}
@@ -6,20 +6,22 @@ import (
 
 	"github.com/night1010/everhealth/apperror"
 	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/promotion"
 	"github.com/night1010/everhealth/repository"
 	"github.com/night1010/everhealth/valueobject"
 	"github.com/shopspring/decimal"
 )
 
 type ShippingMethodUsecase interface {
-	GetShippingMethod(ctx context.Context, addressId uint) ([]*entity.CalculatedShippingMethod, error)
+	GetShippingMethod(ctx context.Context, addressId uint, voucherCode string) ([]*entity.PharmacyShippingMethod, error)
 }
 
 type shippingMethodUsecase struct {
-	addressRepo  repository.AddressRepository
-	shippingRepo repository.ShippingMethodRepository
-	pharmacyRepo repository.PharmacyRepository
-	orderUsecase OrderUsecase
+	addressRepo    repository.AddressRepository
+	shippingRepo   repository.ShippingMethodRepository
+	pharmacyRepo   repository.PharmacyRepository
+	orderUsecase   OrderUsecase
+	voucherUsecase VoucherUsecase
 }
 
 func NewShippingMethodUsecase(
@@ -27,23 +29,25 @@ func NewShippingMethodUsecase(
 	shippingRepo repository.ShippingMethodRepository,
 	pharmacyRepo repository.PharmacyRepository,
 	orderUsecase OrderUsecase,
+	voucherUsecase VoucherUsecase,
 ) ShippingMethodUsecase {
 	return &shippingMethodUsecase{
-		addressRepo:  addressRepo,
-		shippingRepo: shippingRepo,
-		pharmacyRepo: pharmacyRepo,
-		orderUsecase: orderUsecase,
+		addressRepo:    addressRepo,
+		shippingRepo:   shippingRepo,
+		pharmacyRepo:   pharmacyRepo,
+		orderUsecase:   orderUsecase,
+		voucherUsecase: voucherUsecase,
 	}
 }
 
-func (u *shippingMethodUsecase) GetShippingMethod(ctx context.Context, addressId uint) ([]*entity.CalculatedShippingMethod, error) {
+func (u *shippingMethodUsecase) GetShippingMethod(ctx context.Context, addressId uint, voucherCode string) ([]*entity.PharmacyShippingMethod, error) {
 	userId := ctx.Value("user_id").(uint)
 
-	_, products, _, _, err := u.orderUsecase.GetAvailableProduct(ctx, addressId)
+	_, pharmacyProducts, shipments, _, err := u.orderUsecase.GetAvailableProduct(ctx, addressId)
 	if err != nil {
 		return nil, err
 	}
-	if len(products) == 0 {
+	if len(shipments) == 0 {
 		return nil, apperror.NewClientError(fmt.Errorf("no pharmacy available"))
 	}
 
@@ -62,41 +66,105 @@ func (u *shippingMethodUsecase) GetShippingMethod(ctx context.Context, addressId
 		return nil, apperror.NewForbiddenActionError("not the address of current logged in user")
 	}
 
-	fetchedPharmacy, err := u.pharmacyRepo.FindNearestPharmacyFromAddress(ctx, addressId)
+	var listOfPharmacyId []uint
+	for _, shipment := range shipments {
+		listOfPharmacyId = append(listOfPharmacyId, shipment.PharmacyId)
+	}
+	pharmacyQuery := valueobject.NewQuery().
+		WithJoin("City").
+		Condition("\"pharmacies\".id", valueobject.In, listOfPharmacyId)
+	fetchedPharmacies, err := u.pharmacyRepo.Find(ctx, pharmacyQuery)
 	if err != nil {
 		return nil, err
 	}
-	if fetchedPharmacy == nil || len(fetchedPharmacy) < 1 {
-		return nil, apperror.NewClientError(fmt.Errorf("there's no pharmacy available near this address"))
+	pharmacyM := make(map[uint]*entity.Pharmacy)
+	for _, pharmacy := range fetchedPharmacies {
+		pharmacyM[pharmacy.Id] = pharmacy
 	}
 
-	distanceInKM, err := u.shippingRepo.FindDistanceBetween(ctx, fetchedPharmacy[0].Location, fetchedAddress.Location)
+	fetchedShippingMethod, err := u.shippingRepo.Find(ctx, valueobject.NewQuery())
 	if err != nil {
 		return nil, err
 	}
+	var voucher *entity.Voucher
+	cart := &promotion.Cart{}
+	if voucherCode != "" {
+		categoryM := make(map[uint]uint)
+		for _, pp := range pharmacyProducts {
+			categoryM[pp.Id] = pp.Product.ProductCategoryId
+		}
+		for _, shipment := range shipments {
+			for _, item := range shipment.OrderItems {
+				cart.Lines = append(cart.Lines, &promotion.Line{
+					ProductCategoryId: categoryM[item.PharmacyProductId],
+					SubTotal:          item.SubTotal,
+				})
+			}
+		}
+		voucher, _, err = u.voucherUsecase.ApplyVoucher(ctx, voucherCode, cart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	distanceThresholdInKM := decimal.NewFromInt(25)
-	calculatedShippingMethods := make([]*entity.CalculatedShippingMethod, 0)
+	pharmacyShippingMethods := make([]*entity.PharmacyShippingMethod, 0)
 
-	if distanceInKM.LessThan(distanceThresholdInKM) {
-		fetchedShippingMethod, err := u.shippingRepo.Find(ctx, valueobject.NewQuery())
+	for _, shipment := range shipments {
+		pharmacy, ok := pharmacyM[shipment.PharmacyId]
+		if !ok {
+			return nil, apperror.NewClientError(fmt.Errorf("there's no pharmacy available near this address"))
+		}
+		distanceInKM, err := u.shippingRepo.FindDistanceBetween(ctx, pharmacy.Location, fetchedAddress.Location)
 		if err != nil {
 			return nil, err
 		}
-		distanceInKM = distanceInKM.DivRound(decimal.NewFromInt(1), 0)
-		for _, fsm := range fetchedShippingMethod {
-			calculatedShippingMethods = append(calculatedShippingMethods, &entity.CalculatedShippingMethod{
-				Name:              fsm.Name,
-				EstimatedDuration: fsm.Duration,
-				Cost:              fsm.PricePerKM.Mul(distanceInKM).String(),
-			})
+		calculatedShippingMethods := make([]*entity.CalculatedShippingMethod, 0)
+
+		if distanceInKM.LessThan(distanceThresholdInKM) {
+			distanceInKM = distanceInKM.DivRound(decimal.NewFromInt(1), 0)
+			for _, fsm := range fetchedShippingMethod {
+				calculatedShippingMethods = append(calculatedShippingMethods, &entity.CalculatedShippingMethod{
+					Name:              fsm.Name,
+					EstimatedDuration: fsm.Duration,
+					Cost:              fsm.PricePerKM.Mul(distanceInKM).String(),
+				})
+			}
 		}
-	}
 
-	calculatedThirdPartyShippingMethods, _ := u.shippingRepo.GetThirdPartyShipping(ctx, fetchedPharmacy[0].City.Code, fetchedAddress.City.Code, "10")
+		calculatedThirdPartyShippingMethods, _ := u.shippingRepo.GetThirdPartyShipping(ctx, pharmacy.City.Code, fetchedAddress.City.Code, "10")
 
-	if len(calculatedThirdPartyShippingMethods) > 0 {
-		calculatedShippingMethods = append(calculatedShippingMethods, calculatedThirdPartyShippingMethods...)
+		if len(calculatedThirdPartyShippingMethods) > 0 {
+			calculatedShippingMethods = append(calculatedShippingMethods, calculatedThirdPartyShippingMethods...)
+		}
+		if voucher != nil {
+			err = u.applyShippingVoucher(voucher, cart, calculatedShippingMethods)
+			if err != nil {
+				return nil, err
+			}
+		}
+		pharmacyShippingMethods = append(pharmacyShippingMethods, &entity.PharmacyShippingMethod{
+			Pharmacy: pharmacy,
+			Methods:  calculatedShippingMethods,
+		})
 	}
 
-	return calculatedShippingMethods, nil
+	return pharmacyShippingMethods, nil
+}
+
+func (u *shippingMethodUsecase) applyShippingVoucher(voucher *entity.Voucher, cart *promotion.Cart, methods []*entity.CalculatedShippingMethod) error {
+	for _, method := range methods {
+		cost, err := decimal.NewFromString(method.Cost)
+		if err != nil {
+			return err
+		}
+		cart.ShippingCost = cost
+		result, err := promotion.Apply(voucher, cart)
+		if err != nil {
+			return apperror.NewClientError(err)
+		}
+		method.Discount = result.ShippingDiscount.String()
+		method.FinalCost = cost.Sub(result.ShippingDiscount).String()
+	}
+	return nil
 }
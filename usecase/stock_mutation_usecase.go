@@ -25,16 +25,31 @@ type stockMutationUsecase struct {
 	stockMutationRepository   repository.StockMutationRepository
 	stockRecordRepository     repository.StockRecordRepository
 	pharmacyProductRepository repository.PharmacyProductRepository
+	staffRepository           repository.PharmacyStaffRepository
 	manager                   transactor.Manager
 }
 
-func NewStockMutationUsecase(rp repository.StockMutationRepository, cr repository.PharmacyProductRepository, sr repository.StockRecordRepository, m transactor.Manager) StockMutationUsecase {
-	return &stockMutationUsecase{stockMutationRepository: rp, pharmacyProductRepository: cr, manager: m, stockRecordRepository: sr}
+func NewStockMutationUsecase(rp repository.StockMutationRepository, cr repository.PharmacyProductRepository, sr repository.StockRecordRepository, psr repository.PharmacyStaffRepository, m transactor.Manager) StockMutationUsecase {
+	return &stockMutationUsecase{stockMutationRepository: rp, pharmacyProductRepository: cr, manager: m, stockRecordRepository: sr, staffRepository: psr}
 }
 
 func (u *stockMutationUsecase) FindAllStockMutation(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
-
-	return u.stockMutationRepository.FindAllStockMutation(ctx, query)
+	pagedResult, err := u.stockMutationRepository.FindAllStockMutation(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	staffs, err := u.staffRepository.Find(ctx, valueobject.NewQuery().Condition("user_id", valueobject.Equal, ctx.Value("user_id").(uint)))
+	if err != nil {
+		return nil, err
+	}
+	staffM := make(map[uint]*entity.PharmacyStaff)
+	for _, staff := range staffs {
+		staffM[staff.PharmacyId] = staff
+	}
+	for _, stockMutation := range pagedResult.Data.([]*entity.StockMutation) {
+		stockMutation.IsRequest = staffM[stockMutation.FromPharmacyProduct.PharmacyId].Can(entity.PermissionStockMutationWrite)
+	}
+	return pagedResult, nil
 }
 
 func (u *stockMutationUsecase) GetStockMutationDetail(ctx context.Context, stockMutation *entity.StockMutation) (*entity.StockMutation, error) {
@@ -46,11 +61,20 @@ func (u *stockMutationUsecase) GetStockMutationDetail(ctx context.Context, stock
 	if selectStockMutation == nil {
 		return nil, apperror.NewResourceNotFoundError("stock mutation", "id", stockMutation.Id)
 	}
-	adminId := ctx.Value("user_id").(uint)
-	if selectStockMutation.ToPharmacyProduct.Pharmacy.AdminId == adminId || selectStockMutation.FromPharmacyProduct.Pharmacy.AdminId == adminId {
-		return selectStockMutation, nil
+	userId := ctx.Value("user_id").(uint)
+	toStaff, err := u.staffRepository.FindMember(ctx, selectStockMutation.ToPharmacyProduct.PharmacyId, userId)
+	if err != nil {
+		return nil, err
+	}
+	fromStaff, err := u.staffRepository.FindMember(ctx, selectStockMutation.FromPharmacyProduct.PharmacyId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !toStaff.Can(entity.PermissionStockMutationRead) && !fromStaff.Can(entity.PermissionStockMutationRead) {
+		return nil, apperror.NewForbiddenActionError("cannot access this stock mutation")
 	}
-	return nil, apperror.NewForbiddenActionError("cannot access this stock mutation")
+	selectStockMutation.IsRequest = fromStaff.Can(entity.PermissionStockMutationWrite)
+	return selectStockMutation, nil
 }
 
 func (u *stockMutationUsecase) CreateStockMutation(ctx context.Context, stockMutation *dto.StockMutationReq) (*entity.StockMutation, error) {
@@ -59,14 +83,18 @@ func (u *stockMutationUsecase) CreateStockMutation(ctx context.Context, stockMut
 	newStockMutation.Status = entity.Pending
 	newStockMutation.ToPharmacyProductId = stockMutation.ToPharmacyProductId
 	newStockMutation.Quantity = stockMutation.Quantity
-	toProduct, err := u.pharmacyProductRepository.FindOne(ctx, valueobject.NewQuery().Condition("\"pharmacy_products\".id", valueobject.Equal, newStockMutation.ToPharmacyProductId).WithJoin("Pharmacy"))
+	toProduct, err := u.pharmacyProductRepository.FindOne(ctx, valueobject.NewQuery().Condition("\"pharmacy_products\".id", valueobject.Equal, newStockMutation.ToPharmacyProductId))
 	if err != nil {
 		return nil, err
 	}
 	if toProduct == nil {
 		return nil, apperror.NewClientError(fmt.Errorf("pharmacy product with id %v not found", newStockMutation.ToPharmacyProductId))
 	}
-	if toProduct.Pharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, toProduct.PharmacyId, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionStockMutationWrite) {
 		return nil, apperror.NewForbiddenActionError(fmt.Sprintf("u dont have access to stock mutation this pharmacy product %v", toProduct.Id))
 	}
 	pharmacyProduct, err := u.pharmacyProductRepository.FindOne(ctx, valueobject.NewQuery().Condition("pharmacy_id", valueobject.Equal, stockMutation.FromPharmacy).Condition("product_id", valueobject.Equal, toProduct.ProductId))
@@ -96,6 +124,23 @@ func (u *stockMutationUsecase) UpdateStockMutation(ctx context.Context, stockMut
 	if err != nil {
 		return nil, err
 	}
+	if updateStockMutation == nil {
+		return nil, apperror.NewResourceNotFoundError("stock mutation", "id", stockMutation.Id)
+	}
+	fromProduct, err := u.pharmacyProductRepository.FindById(ctx, updateStockMutation.FromPharmacyProductId)
+	if err != nil {
+		return nil, err
+	}
+	if fromProduct == nil {
+		return nil, apperror.NewResourceNotFoundError("pharmacy product", "id", updateStockMutation.FromPharmacyProductId)
+	}
+	staff, err := u.staffRepository.FindMember(ctx, fromProduct.PharmacyId, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionStockMutationWrite) {
+		return nil, apperror.NewForbiddenActionError("cannot change status")
+	}
 	if updateStockMutation.Status != entity.Pending {
 		return nil, apperror.NewClientError(errors.New("status already change"))
 	}
@@ -114,12 +159,15 @@ func (u *stockMutationUsecase) UpdateStockMutation(ctx context.Context, stockMut
 		if err != nil {
 			return err
 		}
-		fromProduct, err := u.pharmacyProductRepository.FindOne(c, valueobject.NewQuery().Condition("\"pharmacy_products\".id", valueobject.Equal, updateStockMutation.FromPharmacyProductId).WithJoin("Pharmacy"))
+		if toProduct == nil {
+			return apperror.NewResourceNotFoundError("pharmacy product", "id", updateStockMutation.ToPharmacyProductId)
+		}
+		fromProduct, err := u.pharmacyProductRepository.FindOne(c, valueobject.NewQuery().Condition("\"pharmacy_products\".id", valueobject.Equal, updateStockMutation.FromPharmacyProductId).Lock())
 		if err != nil {
 			return err
 		}
-		if fromProduct.Pharmacy.AdminId != ctx.Value("user_id").(uint) {
-			return apperror.NewForbiddenActionError("cannot change status")
+		if fromProduct == nil {
+			return apperror.NewResourceNotFoundError("pharmacy product", "id", updateStockMutation.FromPharmacyProductId)
 		}
 		if fromProduct.Stock < int(updateStockMutation.Quantity) {
 			updateStockMutation.Status = entity.Decline
@@ -22,11 +22,13 @@ type StockRecordUsecase interface {
 type stockRecordUsecase struct {
 	stockRecordRepository     repository.StockRecordRepository
 	pharmacyProductRepository repository.PharmacyProductRepository
+	reservationRepository     repository.StockReservationRepository
+	staffRepository           repository.PharmacyStaffRepository
 	manager                   transactor.Manager
 }
 
-func NewStockRecordUsecase(rp repository.StockRecordRepository, cr repository.PharmacyProductRepository, m transactor.Manager) StockRecordUsecase {
-	return &stockRecordUsecase{stockRecordRepository: rp, pharmacyProductRepository: cr, manager: m}
+func NewStockRecordUsecase(rp repository.StockRecordRepository, cr repository.PharmacyProductRepository, sr repository.StockReservationRepository, psr repository.PharmacyStaffRepository, m transactor.Manager) StockRecordUsecase {
+	return &stockRecordUsecase{stockRecordRepository: rp, pharmacyProductRepository: cr, reservationRepository: sr, staffRepository: psr, manager: m}
 }
 
 func (u *stockRecordUsecase) FindAllStockRecord(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
@@ -36,14 +38,18 @@ func (u *stockRecordUsecase) FindAllStockRecord(ctx context.Context, query *valu
 func (u *stockRecordUsecase) CreateStockRecord(ctx context.Context, stockRecord *entity.StockRecord) (*entity.StockRecord, error) {
 	var newStockRecord *entity.StockRecord
 	stockRecord.ChangeAt = time.Now()
-	pharmacyProduct, err := u.pharmacyProductRepository.FindOne(ctx, valueobject.NewQuery().Condition("\"pharmacy_products\".id", valueobject.Equal, stockRecord.PharmacyProductId).WithJoin("Pharmacy"))
+	pharmacyProduct, err := u.pharmacyProductRepository.FindOne(ctx, valueobject.NewQuery().Condition("\"pharmacy_products\".id", valueobject.Equal, stockRecord.PharmacyProductId))
 	if err != nil {
 		return nil, err
 	}
 	if pharmacyProduct == nil {
 		return nil, apperror.NewClientError(fmt.Errorf("product with id %v not found", stockRecord.PharmacyProductId))
 	}
-	if pharmacyProduct.Pharmacy.AdminId != ctx.Value("user_id").(uint) {
+	staff, err := u.staffRepository.FindMember(ctx, pharmacyProduct.PharmacyId, ctx.Value("user_id").(uint))
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(entity.PermissionStockRecordWrite) {
 		return nil, apperror.NewForbiddenActionError("cannot have access to change stock")
 	}
 	err = u.manager.Run(ctx, func(c context.Context) error {
@@ -65,6 +71,13 @@ func (u *stockRecordUsecase) CreateStockRecord(ctx context.Context, stockRecord
 		if number < 0 {
 			return apperror.NewClientError(errors.New("product's stock cannot below zero"))
 		}
+		heldM, err := u.reservationRepository.FindHeldQuantity(c, []uint{pharmacyProduct.Id})
+		if err != nil {
+			return err
+		}
+		if number < heldM[pharmacyProduct.Id] {
+			return apperror.NewClientError(errors.New("product's stock cannot below reserved quantity"))
+		}
 		pharmacyProduct.Stock = number
 		_, err = u.pharmacyProductRepository.Update(c, pharmacyProduct)
 		if err != nil {
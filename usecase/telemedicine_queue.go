@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/valueobject"
+)
+
+const queueConsultationEstimate = 15 * time.Minute
+
+type QueueNotifier interface {
+	NotifyQueue(userId uint, queue *entity.TelemedicineQueue)
+}
+
+func (u *telemedicineUsecase) JoinQueue(ctx context.Context, doctorId uint) (*entity.TelemedicineQueue, error) {
+	profileId := ctx.Value("user_id").(uint)
+	queue := &entity.TelemedicineQueue{DoctorId: doctorId, ProfileId: profileId, Status: entity.QueueWaiting}
+	var promoted *entity.TelemedicineQueue
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		doctor, err := u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, doctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if doctor == nil {
+			return apperror.NewClientError(fmt.Errorf("doctor id %v not found", doctorId))
+		}
+		if doctor.Status == entity.Offline {
+			return apperror.NewClientError(fmt.Errorf("doctor id %v %v", doctorId, entity.DoctorStatusMap[doctor.Status]))
+		}
+		checkQueue, err := u.queueRepository.FindOne(c, valueobject.NewQuery().
+			Condition("profile_id", valueobject.Equal, profileId).
+			Condition("status", valueobject.Equal, entity.QueueWaiting))
+		if err != nil {
+			return err
+		}
+		if checkQueue != nil {
+			return apperror.NewClientError(errors.New("cannot join another queue, because you are already waiting in a queue"))
+		}
+		checkTelemedicine, err := u.telemedicineRepository.FindTelemedicineWhereStatusNotCancelAndEnd(c, &entity.Telemedicine{ProfileId: profileId})
+		if err != nil {
+			return err
+		}
+		if checkTelemedicine != nil {
+			return apperror.NewClientError(errors.New("cannot join queue, because you have ongoing telemedicine"))
+		}
+		queue.JoinedAt = time.Now()
+		_, err = u.queueRepository.Create(c, queue)
+		if err != nil {
+			return err
+		}
+		promoted, err = u.promoteNextPatient(c, doctor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.notifyQueue(ctx, doctorId, promoted)
+	if promoted != nil && promoted.Id == queue.Id {
+		queue = promoted
+	}
+	err = u.fillQueuePosition(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+func (u *telemedicineUsecase) FindQueue(ctx context.Context) (*entity.TelemedicineQueue, error) {
+	profileId := ctx.Value("user_id").(uint)
+	query := valueobject.NewQuery().
+		Condition("profile_id", valueobject.Equal, profileId).
+		Condition("status", valueobject.In, []entity.TelemedicineQueueStatus{entity.QueueWaiting, entity.QueuePromoted}).
+		WithSortBy("id").
+		WithOrder(valueobject.OrderDesc).
+		WithLimit(1)
+	queues, err := u.queueRepository.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(queues) == 0 {
+		return nil, apperror.NewResourceNotFoundError("queue", "profile id", profileId)
+	}
+	err = u.fillQueuePosition(ctx, queues[0])
+	if err != nil {
+		return nil, err
+	}
+	return queues[0], nil
+}
+
+func (u *telemedicineUsecase) LeaveQueue(ctx context.Context) error {
+	profileId := ctx.Value("user_id").(uint)
+	var queue *entity.TelemedicineQueue
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		var err error
+		queue, err = u.queueRepository.FindOne(c, valueobject.NewQuery().
+			Condition("profile_id", valueobject.Equal, profileId).
+			Condition("status", valueobject.Equal, entity.QueueWaiting).
+			Lock())
+		if err != nil {
+			return err
+		}
+		if queue == nil {
+			return apperror.NewResourceNotFoundError("queue", "profile id", profileId)
+		}
+		queue.Status = entity.QueueLeft
+		_, err = u.queueRepository.Update(c, queue)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	u.notifyQueue(ctx, queue.DoctorId)
+	return nil
+}
+
+func (u *telemedicineUsecase) promoteNextPatient(ctx context.Context, doctor *entity.DoctorProfile) (*entity.TelemedicineQueue, error) {
+	if doctor.Status != entity.Online {
+		return nil, nil
+	}
+	now := time.Now()
+	pending, err := u.telemedicineRepository.FindOne(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctor.ProfileId).
+		Condition("status", valueobject.Equal, entity.Waiting).
+		Condition("scheduled_at", valueobject.Is, nil).
+		Condition("expired_at", valueobject.GreaterThan, now))
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, nil
+	}
+	next, err := u.queueRepository.FindOne(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctor.ProfileId).
+		Condition("status", valueobject.Equal, entity.QueueWaiting).
+		Lock())
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return nil, nil
+	}
+	telemedicine, err := u.telemedicineRepository.Create(ctx, &entity.Telemedicine{
+		ProfileId:    next.ProfileId,
+		DoctorId:     doctor.ProfileId,
+		OrderedAt:    now,
+		ExpiredAt:    now.Add(telemedicinePaymentWindow),
+		TotalPayment: doctor.Fee,
+		Status:       entity.Waiting,
+	})
+	if err != nil {
+		return nil, err
+	}
+	next.Status = entity.QueuePromoted
+	next.TelemedicineId = &telemedicine.Id
+	next.PromotedAt = &now
+	_, err = u.queueRepository.Update(ctx, next)
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+func (u *telemedicineUsecase) fillQueuePosition(ctx context.Context, queue *entity.TelemedicineQueue) error {
+	if queue.Status != entity.QueueWaiting {
+		return nil
+	}
+	ahead, err := u.queueRepository.CountWaitingBefore(ctx, queue)
+	if err != nil {
+		return err
+	}
+	queue.Position = int(ahead) + 1
+	queue.EstimatedWait = time.Duration(queue.Position) * queueConsultationEstimate
+	return nil
+}
+
+func (u *telemedicineUsecase) notifyQueue(ctx context.Context, doctorId uint, promoted ...*entity.TelemedicineQueue) {
+	if u.queueNotifier == nil {
+		return
+	}
+	for _, queue := range promoted {
+		if queue != nil {
+			u.queueNotifier.NotifyQueue(queue.ProfileId, queue)
+		}
+	}
+	queues, err := u.queueRepository.Find(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, doctorId).
+		Condition("status", valueobject.Equal, entity.QueueWaiting).
+		WithSortBy("id"))
+	if err != nil {
+		return
+	}
+	for i, queue := range queues {
+		queue.Position = i + 1
+		queue.EstimatedWait = time.Duration(queue.Position) * queueConsultationEstimate
+		u.queueNotifier.NotifyQueue(queue.ProfileId, queue)
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/util"
+)
+
+const (
+	transcriptImageTimeout = 10 * time.Second
+	transcriptImageWorkers = 4
+	maxTranscriptImageSize = 5 << 20
+)
+
+func (u *telemedicineUsecase) GetTranscript(ctx context.Context, telemedicineId uint) (io.Reader, error) {
+	telemedicine, err := u.FindTelemedicine(ctx, telemedicineId)
+	if err != nil {
+		return nil, err
+	}
+	if telemedicine.Status != entity.End {
+		return nil, apperror.NewResourceStateError("transcript is only available after consultation ended")
+	}
+	chats := make([]*entity.Chat, 0, len(telemedicine.Chats))
+	for i := len(telemedicine.Chats) - 1; i >= 0; i-- {
+		chats = append(chats, telemedicine.Chats[i])
+	}
+	return util.CreateTranscript(telemedicine, chats, fetchTranscriptImages(ctx, chats))
+}
+
+func fetchTranscriptImages(ctx context.Context, chats []*entity.Chat) map[uint][]byte {
+	ctx, cancel := context.WithTimeout(ctx, transcriptImageTimeout)
+	defer cancel()
+
+	jobs := make(chan *entity.Chat)
+	images := make(map[uint][]byte)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < transcriptImageWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chat := range jobs {
+				image, err := fetchTranscriptImage(ctx, chat.Message)
+				if err != nil {
+					continue
+				}
+				mu.Lock()
+				images[chat.Id] = image
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, chat := range chats {
+		if chat.MessageType == entity.MessageTypeImage {
+			jobs <- chat
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return images
+}
+
+func fetchTranscriptImage(ctx context.Context, url string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(response.Body, maxTranscriptImageSize))
+}
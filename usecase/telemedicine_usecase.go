@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"time"
 
 	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/appointment"
 	"github.com/night1010/everhealth/dto"
 	"github.com/night1010/everhealth/entity"
 	"github.com/night1010/everhealth/imagehelper"
+	"github.com/night1010/everhealth/logger"
 	"github.com/night1010/everhealth/repository"
 	"github.com/night1010/everhealth/transactor"
 	"github.com/night1010/everhealth/util"
@@ -19,23 +22,41 @@ import (
 
 type TelemedicineUsecase interface {
 	CreateTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine) (*entity.Telemedicine, error)
+	BookTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine) (*entity.Telemedicine, error)
 	PaymentProofTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine) (*entity.Telemedicine, error)
+	ConfirmPayment(ctx context.Context, telemedicineId uint) error
 	SickLeaveTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine, sickLeave *dto.SickLeave) (*entity.Telemedicine, error)
 	FindAllTelemedicine(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
 	FindTelemedicine(ctx context.Context, telemedicineId uint) (*entity.Telemedicine, error)
+	GetTranscript(ctx context.Context, telemedicineId uint) (io.Reader, error)
 	PrescriptionTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine, prescription *dto.Prescription) (*entity.Telemedicine, error)
 	EndTelemedicine(ctx context.Context, telemedicineId uint) error
+	CancelExpiredTelemedicines(ctx context.Context) error
+	StartScheduledTelemedicines(ctx context.Context) error
+	JoinQueue(ctx context.Context, doctorId uint) (*entity.TelemedicineQueue, error)
+	FindQueue(ctx context.Context) (*entity.TelemedicineQueue, error)
+	LeaveQueue(ctx context.Context) error
 }
 
+const telemedicinePaymentWindow = 10 * time.Minute
+
 type telemedicineUsecase struct {
 	imageHelper            imagehelper.ImageHelper
 	manager                transactor.Manager
 	doctorRepository       repository.DoctorProfileRepository
 	telemedicineRepository repository.TelemedicineRepository
+	refundRepository       repository.RefundRepository
+	paymentRepository      repository.PaymentRepository
+	productRepository      repository.ProductRepository
+	prescriptionRepository repository.PrescriptionItemRepository
+	queueRepository        repository.TelemedicineQueueRepository
+	chatRepository         repository.ChatRepository
+	scheduleUsecase        DoctorScheduleUsecase
+	queueNotifier          QueueNotifier
 }
 
-func NewTelemedicineUsecase(rp repository.TelemedicineRepository, dr repository.DoctorProfileRepository, m transactor.Manager, img imagehelper.ImageHelper) TelemedicineUsecase {
-	return &telemedicineUsecase{telemedicineRepository: rp, doctorRepository: dr, imageHelper: img, manager: m}
+func NewTelemedicineUsecase(rp repository.TelemedicineRepository, dr repository.DoctorProfileRepository, rr repository.RefundRepository, pyr repository.PaymentRepository, pr repository.ProductRepository, pir repository.PrescriptionItemRepository, qr repository.TelemedicineQueueRepository, cr repository.ChatRepository, su DoctorScheduleUsecase, m transactor.Manager, img imagehelper.ImageHelper, qn QueueNotifier) TelemedicineUsecase {
+	return &telemedicineUsecase{telemedicineRepository: rp, doctorRepository: dr, refundRepository: rr, paymentRepository: pyr, productRepository: pr, prescriptionRepository: pir, queueRepository: qr, chatRepository: cr, scheduleUsecase: su, imageHelper: img, manager: m, queueNotifier: qn}
 }
 
 func (u *telemedicineUsecase) FindAllTelemedicine(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
@@ -43,7 +64,9 @@ func (u *telemedicineUsecase) FindAllTelemedicine(ctx context.Context, query *va
 	if err != nil {
 		return nil, err
 	}
-	for _, telemedecine := range page.Data.([]*entity.Telemedicine) {
+	telemedicines := page.Data.([]*entity.Telemedicine)
+	var telemedicineIds []uint
+	for _, telemedecine := range telemedicines {
 		if telemedecine.ExpiredAt.Before(time.Now()) && telemedecine.Status == entity.Waiting {
 			telemedecine.Status = entity.Cancel
 			_, err := u.telemedicineRepository.Update(ctx, telemedecine)
@@ -51,6 +74,14 @@ func (u *telemedicineUsecase) FindAllTelemedicine(ctx context.Context, query *va
 				return nil, err
 			}
 		}
+		telemedicineIds = append(telemedicineIds, telemedecine.Id)
+	}
+	unread, err := u.chatRepository.CountUnread(ctx, ctx.Value("user_id").(uint), telemedicineIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, telemedecine := range telemedicines {
+		telemedecine.UnreadCount = unread[telemedecine.Id]
 	}
 	return page, nil
 }
@@ -66,7 +97,8 @@ func (u *telemedicineUsecase) FindTelemedicine(ctx context.Context, telemedicine
 	}
 	telemedicineQuery = telemedicineQuery.
 		WithPreload("Doctor.Profile").
-		WithPreload("Profile")
+		WithPreload("Profile").
+		WithPreload("PrescriptionItems.Product")
 	fetchedTelemedicine, err := u.telemedicineRepository.FindByTelemedicineId(ctx, telemedicineQuery)
 	if err != nil {
 		return nil, err
@@ -97,14 +129,112 @@ func (u *telemedicineUsecase) CreateTelemedicine(ctx context.Context, telemedici
 	if checkTelemedicine != nil {
 		return nil, apperror.NewClientError(errors.New("cannot create another telemedicine, because you have ongoing telemedicine"))
 	}
+	checkQueue, err := u.queueRepository.FindOne(ctx, valueobject.NewQuery().
+		Condition("profile_id", valueobject.Equal, profileId).
+		Condition("status", valueobject.Equal, entity.QueueWaiting))
+	if err != nil {
+		return nil, err
+	}
+	if checkQueue != nil {
+		return nil, apperror.NewClientError(errors.New("cannot create telemedicine, because you are waiting in a queue"))
+	}
+	doctorQueue, err := u.queueRepository.FindOne(ctx, valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, telemedicine.DoctorId).
+		Condition("status", valueobject.Equal, entity.QueueWaiting))
+	if err != nil {
+		return nil, err
+	}
+	if doctorQueue != nil {
+		return nil, apperror.NewClientError(fmt.Errorf("doctor id %v has patients waiting, please join the queue", telemedicine.DoctorId))
+	}
+	now := time.Now()
+	bookingQuery := valueobject.NewQuery().
+		Condition("doctor_id", valueobject.Equal, telemedicine.DoctorId).
+		Condition("scheduled_at", valueobject.LessThan, now.Add(telemedicinePaymentWindow+queueConsultationEstimate)).
+		Condition("scheduled_end_at", valueobject.GreaterThan, now).
+		Condition("status", valueobject.In, []entity.TelemedicineStatus{entity.Waiting, entity.Booked})
+	upcomingBooking, err := u.telemedicineRepository.FindOne(ctx, bookingQuery)
+	if err != nil {
+		return nil, err
+	}
+	if upcomingBooking != nil {
+		return nil, apperror.NewClientError(fmt.Errorf("doctor id %v has an upcoming appointment, please book a slot", telemedicine.DoctorId))
+	}
 	telemedicine.ProfileId = profileId
-	telemedicine.OrderedAt = time.Now()
-	telemedicine.ExpiredAt = telemedicine.OrderedAt.Add(10 * time.Minute)
+	telemedicine.OrderedAt = now
+	telemedicine.ExpiredAt = telemedicine.OrderedAt.Add(telemedicinePaymentWindow)
 	telemedicine.TotalPayment = doctor.Fee
 	telemedicine.Status = entity.Waiting
 	return u.telemedicineRepository.Create(ctx, telemedicine)
 }
 
+func (u *telemedicineUsecase) BookTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine) (*entity.Telemedicine, error) {
+	profileId := ctx.Value("user_id").(uint)
+	now := time.Now()
+	if !telemedicine.ScheduledAt.After(now) {
+		return nil, apperror.NewClientError(errors.New("cannot book slot in the past"))
+	}
+	var promoted []*entity.TelemedicineQueue
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		doctor, err := u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, telemedicine.DoctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if doctor == nil {
+			return apperror.NewClientError(fmt.Errorf("doctor id %v not found", telemedicine.DoctorId))
+		}
+		for _, query := range []*valueobject.Query{
+			valueobject.NewQuery().Condition("doctor_id", valueobject.Equal, telemedicine.DoctorId),
+			valueobject.NewQuery().Condition("profile_id", valueobject.Equal, profileId),
+		} {
+			queues, err := u.cancelExpiredTelemedicines(c, query)
+			if err != nil {
+				return err
+			}
+			promoted = append(promoted, queues...)
+		}
+		slots, err := u.scheduleUsecase.FindAvailableSlots(c, telemedicine.DoctorId, telemedicine.ScheduledAt.In(time.Local))
+		if err != nil {
+			return err
+		}
+		slot := appointment.Find(slots, *telemedicine.ScheduledAt)
+		if slot == nil {
+			return apperror.NewClientError(fmt.Errorf("slot %v is not available", telemedicine.ScheduledAt.Format(time.RFC3339)))
+		}
+		patientQuery := valueobject.NewQuery().
+			Condition("profile_id", valueobject.Equal, profileId).
+			Condition("scheduled_at", valueobject.LessThan, slot.EndAt).
+			Condition("scheduled_end_at", valueobject.GreaterThan, slot.StartAt).
+			Condition("status", valueobject.In, []entity.TelemedicineStatus{entity.Waiting, entity.Booked})
+		checkTelemedicine, err := u.telemedicineRepository.FindOne(c, patientQuery)
+		if err != nil {
+			return err
+		}
+		if checkTelemedicine != nil {
+			return apperror.NewClientError(errors.New("cannot book this slot, because you have another booking at the same time"))
+		}
+		telemedicine.ProfileId = profileId
+		telemedicine.OrderedAt = now
+		telemedicine.ExpiredAt = now.Add(appointment.PaymentWindow)
+		if slot.StartAt.Before(telemedicine.ExpiredAt) {
+			telemedicine.ExpiredAt = slot.StartAt
+		}
+		telemedicine.ScheduledAt = &slot.StartAt
+		telemedicine.ScheduledEndAt = &slot.EndAt
+		telemedicine.TotalPayment = doctor.Fee
+		telemedicine.Status = entity.Waiting
+		_, err = u.telemedicineRepository.Create(c, telemedicine)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, queue := range promoted {
+		u.notifyQueue(ctx, queue.DoctorId, queue)
+	}
+	return telemedicine, nil
+}
+
 func (u *telemedicineUsecase) PaymentProofTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine) (*entity.Telemedicine, error) {
 	var imageKey string
 	var newTelemedicine *entity.Telemedicine
@@ -131,28 +261,22 @@ func (u *telemedicineUsecase) PaymentProofTelemedicine(ctx context.Context, tele
 		}
 		newTelemedicine.Proof = imgUrl
 		newTelemedicine.ProofKey = imageKey
-		newTelemedicine.Status = entity.Ongoing
-		if newTelemedicine.ExpiredAt.Before(time.Now()) {
-			newTelemedicine.Status = entity.Cancel
-		}
-		_, err = u.telemedicineRepository.Update(c, newTelemedicine)
-		if err != nil {
-			err2 := u.imageHelper.Destroy(ctx, entity.TelemedicineProofFolder, imageKey)
-			if err2 != nil {
-				return err2
-			}
-			return err
+		err = u.startTelemedicine(c, newTelemedicine)
+		var manualPayment *entity.Payment
+		if err == nil {
+			manualPayment, err = u.recordManualPayment(c, newTelemedicine)
 		}
-		fetchedDoctor, err := u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, newTelemedicine.DoctorId))
-		if err != nil {
-			err2 := u.imageHelper.Destroy(ctx, entity.TelemedicineProofFolder, imageKey)
-			if err2 != nil {
-				return err2
-			}
-			return err
+		if err == nil && newTelemedicine.Status == entity.Cancel {
+			_, err = u.refundRepository.Create(c, &entity.Refund{
+				ProfileId:      newTelemedicine.ProfileId,
+				PaymentId:      &manualPayment.Id,
+				PaymentType:    entity.PaymentTypeTelemedicine,
+				TelemedicineId: &newTelemedicine.Id,
+				Amount:         newTelemedicine.TotalPayment,
+				Reason:         "telemedicine expired before payment was confirmed",
+				Status:         entity.RefundRequested,
+			})
 		}
-		fetchedDoctor.Status = entity.Busy
-		_, err = u.doctorRepository.Update(c, fetchedDoctor)
 		if err != nil {
 			err2 := u.imageHelper.Destroy(ctx, entity.TelemedicineProofFolder, imageKey)
 			if err2 != nil {
@@ -171,6 +295,93 @@ func (u *telemedicineUsecase) PaymentProofTelemedicine(ctx context.Context, tele
 	return telemedicine, nil
 }
 
+func (u *telemedicineUsecase) recordManualPayment(c context.Context, telemedicine *entity.Telemedicine) (*entity.Payment, error) {
+	manualPayment, err := chargeManually(c, &entity.Payment{
+		ProfileId:      telemedicine.ProfileId,
+		Reference:      fmt.Sprintf("telemedicine-%d-%s", telemedicine.Id, generateRandomString(8)),
+		PaymentType:    entity.PaymentTypeTelemedicine,
+		TelemedicineId: &telemedicine.Id,
+		Amount:         telemedicine.TotalPayment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	paidAt := time.Now()
+	manualPayment.Status = entity.PaymentPaid
+	manualPayment.PaidAt = &paidAt
+	return u.paymentRepository.Create(c, manualPayment)
+}
+
+func (u *telemedicineUsecase) ConfirmPayment(ctx context.Context, telemedicineId uint) error {
+	var fetchedTelemedicine *entity.Telemedicine
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		var err error
+		fetchedTelemedicine, err = u.telemedicineRepository.FindOne(c, valueobject.NewQuery().Condition("id", valueobject.Equal, telemedicineId).Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedTelemedicine == nil {
+			return apperror.NewResourceNotFoundError("telemedicine", "id", telemedicineId)
+		}
+		if fetchedTelemedicine.Status != entity.Waiting {
+			return apperror.NewClientError(fmt.Errorf("cannot pay because telemedicine already %v", fetchedTelemedicine.Status))
+		}
+		return u.startTelemedicine(c, fetchedTelemedicine)
+	})
+	if err != nil {
+		return err
+	}
+	if fetchedTelemedicine.Status == entity.Cancel {
+		return apperror.NewClientError(errors.New("cannot pay because telemedecine already expired"))
+	}
+	return nil
+}
+
+func (u *telemedicineUsecase) startTelemedicine(c context.Context, telemedicine *entity.Telemedicine) error {
+	now := time.Now()
+	if telemedicine.Status == entity.Waiting && telemedicine.ExpiredAt.Before(now) {
+		telemedicine.Status = entity.Cancel
+	} else if telemedicine.ScheduledAt != nil && telemedicine.ScheduledAt.After(now) {
+		telemedicine.Status = entity.Booked
+	} else {
+		telemedicine.Status = entity.Ongoing
+	}
+	var fetchedDoctor *entity.DoctorProfile
+	if telemedicine.Status == entity.Ongoing {
+		var err error
+		fetchedDoctor, err = u.doctorRepository.FindOne(c, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, telemedicine.DoctorId).Lock())
+		if err != nil {
+			return err
+		}
+		if fetchedDoctor == nil {
+			return apperror.NewResourceNotFoundError("doctor", "id", telemedicine.DoctorId)
+		}
+	}
+	if telemedicine.Status == entity.Ongoing && telemedicine.ScheduledAt != nil {
+		ongoingQuery := valueobject.NewQuery().
+			Condition("doctor_id", valueobject.Equal, telemedicine.DoctorId).
+			Condition("status", valueobject.Equal, entity.Ongoing).
+			Condition("id", valueobject.NotEqual, telemedicine.Id)
+		ongoingTelemedicine, err := u.telemedicineRepository.FindOne(c, ongoingQuery)
+		if err != nil {
+			return err
+		}
+		if ongoingTelemedicine != nil {
+			telemedicine.Status = entity.Booked
+		}
+	}
+	_, err := u.telemedicineRepository.Update(c, telemedicine)
+	if err != nil {
+		return err
+	}
+	if telemedicine.Status != entity.Ongoing {
+		return nil
+	}
+	fetchedDoctor.Status = entity.Busy
+	_, err = u.doctorRepository.Update(c, fetchedDoctor)
+	return err
+}
+
 func (u *telemedicineUsecase) SickLeaveTelemedicine(ctx context.Context, telemedicine *entity.Telemedicine, sickLeave *dto.SickLeave) (*entity.Telemedicine, error) {
 	var pdfKey string
 	var newTelemedicine *entity.Telemedicine
@@ -240,7 +451,31 @@ func (u *telemedicineUsecase) PrescriptionTelemedicine(ctx context.Context, tele
 		if newTelemedicine.PrescriptionPdf != "" {
 			return apperror.NewClientError(errors.New("prescription already created"))
 		}
-		prescriptionPdf, err := util.CreatePrescriptionLeave(prescription.Prescription, newTelemedicine.Profile, newTelemedicine.Doctor)
+		items := prescription.ToModel(newTelemedicine.Id)
+		var listOfProductId []uint
+		for _, item := range items {
+			listOfProductId = append(listOfProductId, item.ProductId)
+		}
+		fetchedProducts, err := u.productRepository.Find(c, valueobject.NewQuery().Condition("id", valueobject.In, listOfProductId))
+		if err != nil {
+			return err
+		}
+		productM := make(map[uint]*entity.Product)
+		for _, product := range fetchedProducts {
+			productM[product.Id] = product
+		}
+		for _, item := range items {
+			product, ok := productM[item.ProductId]
+			if !ok {
+				return apperror.NewResourceNotFoundError("product", "id", item.ProductId)
+			}
+			_, err = u.prescriptionRepository.Create(c, item)
+			if err != nil {
+				return err
+			}
+			item.Product = product
+		}
+		prescriptionPdf, err := util.CreatePrescriptionLeave(items, newTelemedicine.Profile, newTelemedicine.Doctor)
 		if err != nil {
 			return err
 		}
@@ -287,6 +522,7 @@ func (u *telemedicineUsecase) EndTelemedicine(ctx context.Context, telemedicineI
 		return apperror.NewForbiddenActionError("insufficient access")
 	}
 
+	var promoted *entity.TelemedicineQueue
 	err = u.manager.Run(ctx, func(c context.Context) error {
 		fetchedTelemedicine.Status = entity.End
 		_, err = u.telemedicineRepository.Update(c, fetchedTelemedicine)
@@ -308,8 +544,97 @@ func (u *telemedicineUsecase) EndTelemedicine(ctx context.Context, telemedicineI
 			return err
 		}
 
-		return nil
+		promoted, err = u.promoteNextPatient(c, fetchedDoctor)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	u.notifyQueue(ctx, fetchedTelemedicine.DoctorId, promoted)
+	return nil
+}
+
+func (u *telemedicineUsecase) CancelExpiredTelemedicines(ctx context.Context) error {
+	var promoted []*entity.TelemedicineQueue
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		var err error
+		promoted, err = u.cancelExpiredTelemedicines(c, valueobject.NewQuery())
+		return err
 	})
+	if err != nil {
+		return err
+	}
+	for _, queue := range promoted {
+		u.notifyQueue(ctx, queue.DoctorId, queue)
+	}
+	return nil
+}
 
-	return err
+func (u *telemedicineUsecase) cancelExpiredTelemedicines(ctx context.Context, query *valueobject.Query) ([]*entity.TelemedicineQueue, error) {
+	query.
+		Condition("status", valueobject.Equal, entity.Waiting).
+		Condition("expired_at", valueobject.LessThan, time.Now())
+	telemedicines, err := u.telemedicineRepository.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var listOfDoctorId []uint
+	doctorM := make(map[uint]bool)
+	for _, telemedicine := range telemedicines {
+		telemedicine.Status = entity.Cancel
+		_, err = u.telemedicineRepository.Update(ctx, telemedicine)
+		if err != nil {
+			return nil, err
+		}
+		if telemedicine.ScheduledAt == nil && !doctorM[telemedicine.DoctorId] {
+			doctorM[telemedicine.DoctorId] = true
+			listOfDoctorId = append(listOfDoctorId, telemedicine.DoctorId)
+		}
+	}
+	var promoted []*entity.TelemedicineQueue
+	for _, doctorId := range listOfDoctorId {
+		doctor, err := u.doctorRepository.FindOne(ctx, valueobject.NewQuery().Condition("profile_id", valueobject.Equal, doctorId).Lock())
+		if err != nil {
+			return nil, err
+		}
+		queue, err := u.promoteNextPatient(ctx, doctor)
+		if err != nil {
+			return nil, err
+		}
+		if queue != nil {
+			promoted = append(promoted, queue)
+		}
+	}
+	return promoted, nil
+}
+
+func (u *telemedicineUsecase) StartScheduledTelemedicines(ctx context.Context) error {
+	query := valueobject.NewQuery().
+		Condition("status", valueobject.Equal, entity.Booked).
+		Condition("scheduled_at", valueobject.LessThanEqual, time.Now())
+	telemedicines, err := u.telemedicineRepository.Find(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, telemedicine := range telemedicines {
+		err = u.manager.Run(ctx, func(c context.Context) error {
+			lockQuery := valueobject.NewQuery().
+				Condition("id", valueobject.Equal, telemedicine.Id).
+				Condition("status", valueobject.Equal, entity.Booked).
+				Lock()
+			fetchedTelemedicine, err := u.telemedicineRepository.FindOne(c, lockQuery)
+			if err != nil {
+				return err
+			}
+			if fetchedTelemedicine == nil {
+				return nil
+			}
+			return u.startTelemedicine(c, fetchedTelemedicine)
+		})
+		if err != nil {
+			logger.Log.Errorf("failed to start scheduled telemedicine %d: %v", telemedicine.Id, err)
+		}
+	}
+	return nil
 }
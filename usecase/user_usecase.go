@@ -96,6 +96,7 @@ func (u *userUsecase) ResetPassword(ctx context.Context, oldPassword, newPasswor
 		return err
 	}
 	fetchedUser.Password = string(hashedPassword)
+	fetchedUser.TokenVersion++
 	_, err = u.userRepo.Update(ctx, fetchedUser)
 	if err != nil {
 		return err
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/night1010/everhealth/apperror"
+	"github.com/night1010/everhealth/entity"
+	"github.com/night1010/everhealth/promotion"
+	"github.com/night1010/everhealth/repository"
+	"github.com/night1010/everhealth/transactor"
+	"github.com/night1010/everhealth/valueobject"
+	"github.com/shopspring/decimal"
+)
+
+type VoucherUsecase interface {
+	ListVouchers(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error)
+	GetVoucher(ctx context.Context, id uint) (*entity.Voucher, error)
+	CreateVoucher(ctx context.Context, voucher *entity.Voucher) (*entity.Voucher, error)
+	UpdateVoucher(ctx context.Context, voucher *entity.Voucher) (*entity.Voucher, error)
+	DeleteVoucher(ctx context.Context, id uint) error
+	ApplyVoucher(ctx context.Context, code string, cart *promotion.Cart) (*entity.Voucher, *promotion.Result, error)
+	RedeemVoucher(ctx context.Context, voucher *entity.Voucher, order *entity.ProductOrder) error
+	ReleaseVoucher(ctx context.Context, order *entity.ProductOrder) error
+}
+
+type voucherUsecase struct {
+	manager             transactor.Manager
+	voucherRepo         repository.VoucherRepository
+	voucherUsageRepo    repository.VoucherUsageRepository
+	productCategoryRepo repository.ProductCategoryRepository
+}
+
+func NewVoucherUsecase(
+	manager transactor.Manager,
+	voucherRepo repository.VoucherRepository,
+	voucherUsageRepo repository.VoucherUsageRepository,
+	productCategoryRepo repository.ProductCategoryRepository,
+) VoucherUsecase {
+	return &voucherUsecase{
+		manager:             manager,
+		voucherRepo:         voucherRepo,
+		voucherUsageRepo:    voucherUsageRepo,
+		productCategoryRepo: productCategoryRepo,
+	}
+}
+
+func (u *voucherUsecase) ListVouchers(ctx context.Context, query *valueobject.Query) (*valueobject.PagedResult, error) {
+	return u.voucherRepo.FindAllVouchers(ctx, query)
+}
+
+func (u *voucherUsecase) GetVoucher(ctx context.Context, id uint) (*entity.Voucher, error) {
+	query := valueobject.NewQuery().Condition("id", valueobject.Equal, id).WithPreload("ProductCategory")
+	fetchedVoucher, err := u.voucherRepo.FindOne(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if fetchedVoucher == nil {
+		return nil, apperror.NewResourceNotFoundError("voucher", "id", id)
+	}
+	return fetchedVoucher, nil
+}
+
+func (u *voucherUsecase) CreateVoucher(ctx context.Context, voucher *entity.Voucher) (*entity.Voucher, error) {
+	err := u.validateVoucher(ctx, voucher)
+	if err != nil {
+		return nil, err
+	}
+	voucher.UsedCount = 0
+	return u.voucherRepo.Create(ctx, voucher)
+}
+
+func (u *voucherUsecase) UpdateVoucher(ctx context.Context, voucher *entity.Voucher) (*entity.Voucher, error) {
+	var updatedVoucher *entity.Voucher
+	err := u.manager.Run(ctx, func(c context.Context) error {
+		query := valueobject.NewQuery().Condition("id", valueobject.Equal, voucher.Id).Lock()
+		fetchedVoucher, err := u.voucherRepo.FindOne(c, query)
+		if err != nil {
+			return err
+		}
+		if fetchedVoucher == nil {
+			return apperror.NewResourceNotFoundError("voucher", "id", voucher.Id)
+		}
+		err = u.validateVoucher(c, voucher)
+		if err != nil {
+			return err
+		}
+		if voucher.Quota > 0 && voucher.Quota < fetchedVoucher.UsedCount {
+			return apperror.NewResourceStateError("quota cannot be lower than the number of redeemed vouchers")
+		}
+		voucher.UsedCount = fetchedVoucher.UsedCount
+		voucher.CreatedAt = fetchedVoucher.CreatedAt
+		updatedVoucher, err = u.voucherRepo.Update(c, voucher)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updatedVoucher, nil
+}
+
+func (u *voucherUsecase) DeleteVoucher(ctx context.Context, id uint) error {
+	fetchedVoucher, err := u.voucherRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if fetchedVoucher == nil {
+		return apperror.NewResourceNotFoundError("voucher", "id", id)
+	}
+	return u.voucherRepo.Delete(ctx, fetchedVoucher)
+}
+
+func (u *voucherUsecase) ApplyVoucher(ctx context.Context, code string, cart *promotion.Cart) (*entity.Voucher, *promotion.Result, error) {
+	userId := ctx.Value("user_id").(uint)
+	query := valueobject.NewQuery().Condition("code", valueobject.Equal, strings.ToUpper(code)).Lock()
+	fetchedVoucher, err := u.voucherRepo.FindOne(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fetchedVoucher == nil {
+		return nil, nil, apperror.NewResourceNotFoundError("voucher", "code", code)
+	}
+	cart.UserUsage, err = u.voucherUsageRepo.CountByProfile(ctx, fetchedVoucher.Id, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cart.Now.IsZero() {
+		cart.Now = time.Now()
+	}
+	result, err := promotion.Apply(fetchedVoucher, cart)
+	if err != nil {
+		return nil, nil, apperror.NewClientError(err)
+	}
+	return fetchedVoucher, result, nil
+}
+
+func (u *voucherUsecase) RedeemVoucher(ctx context.Context, voucher *entity.Voucher, order *entity.ProductOrder) error {
+	_, err := u.voucherUsageRepo.Create(ctx, &entity.VoucherUsage{
+		VoucherId:        voucher.Id,
+		ProfileId:        order.ProfileId,
+		OrderId:          order.Id,
+		Discount:         order.Discount,
+		ShippingDiscount: order.ShippingDiscount,
+	})
+	if err != nil {
+		return err
+	}
+	voucher.UsedCount++
+	_, err = u.voucherRepo.Update(ctx, voucher)
+	return err
+}
+
+func (u *voucherUsecase) ReleaseVoucher(ctx context.Context, order *entity.ProductOrder) error {
+	if order.VoucherId == nil {
+		return nil
+	}
+	usageQuery := valueobject.NewQuery().Condition("order_id", valueobject.Equal, order.Id)
+	fetchedUsage, err := u.voucherUsageRepo.FindOne(ctx, usageQuery)
+	if err != nil {
+		return err
+	}
+	if fetchedUsage == nil {
+		return nil
+	}
+	err = u.voucherUsageRepo.Delete(ctx, fetchedUsage)
+	if err != nil {
+		return err
+	}
+	voucherQuery := valueobject.NewQuery().Condition("id", valueobject.Equal, fetchedUsage.VoucherId).Lock()
+	fetchedVoucher, err := u.voucherRepo.FindOne(ctx, voucherQuery)
+	if err != nil {
+		return err
+	}
+	if fetchedVoucher == nil || fetchedVoucher.UsedCount == 0 {
+		return nil
+	}
+	fetchedVoucher.UsedCount--
+	_, err = u.voucherRepo.Update(ctx, fetchedVoucher)
+	return err
+}
+
+func (u *voucherUsecase) validateVoucher(ctx context.Context, voucher *entity.Voucher) error {
+	voucher.Code = strings.ToUpper(voucher.Code)
+	if !voucher.EndAt.After(voucher.StartAt) {
+		return apperror.NewResourceStateError("end time must be after start time")
+	}
+	if voucher.Type == entity.VoucherPercentage && voucher.Value.GreaterThan(decimal.NewFromInt(100)) {
+		return apperror.NewResourceStateError("percentage voucher value cannot exceed 100")
+	}
+	if voucher.Type != entity.VoucherFreeShipping && !voucher.Value.IsPositive() {
+		return apperror.NewResourceStateError("voucher value must be greater than 0")
+	}
+	duplicateQuery := valueobject.NewQuery().
+		Condition("code", valueobject.Equal, voucher.Code).
+		Condition("id", valueobject.NotEqual, voucher.Id)
+	duplicateVoucher, err := u.voucherRepo.FindOne(ctx, duplicateQuery)
+	if err != nil {
+		return err
+	}
+	if duplicateVoucher != nil {
+		return apperror.NewResourceAlreadyExistError("voucher", "code", voucher.Code)
+	}
+	if voucher.ProductCategoryId != nil {
+		fetchedCategory, err := u.productCategoryRepo.FindById(ctx, *voucher.ProductCategoryId)
+		if err != nil {
+			return err
+		}
+		if fetchedCategory == nil {
+			return apperror.NewResourceNotFoundError("product category", "id", *voucher.ProductCategoryId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/TigorLazuardi/tanggal"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/night1010/everhealth/entity"
+)
+
+func CreateInvoice(number string, order *entity.ProductOrder, items []*entity.OrderItem, address *entity.Address) (io.Reader, error) {
+	var result bytes.Buffer
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 15)
+	pdf.Image("./img/everhealth.png", 11, 10, 80, 0, false, "", 0, "")
+	pdf.Cell(0, 30, "-----------------------------------------------------------------------------------------------------------")
+	pdf.Ln(20)
+
+	format := []tanggal.Format{
+		tanggal.Hari,
+		tanggal.NamaBulan,
+		tanggal.Tahun,
+	}
+	orderedAt, err := tanggal.Papar(order.OrderedAt, "", tanggal.WIB)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf.SetFont("Arial", "B", 20)
+	pdf.Cell(0, 10, "Invoice")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(0, 7, fmt.Sprintf("Nomor Invoice: %v", number))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, fmt.Sprintf("Tanggal Pesanan: %v", orderedAt.Format(" ", format)))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, fmt.Sprintf("Status Pesanan: %v", order.OrderStatus.Name))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, fmt.Sprintf("Metode Pembayaran: %v", order.PaymentMethod))
+	pdf.Ln(12)
+
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 7, "Alamat Pengiriman")
+	pdf.Ln(7)
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(0, 7, fmt.Sprintf("%v (%v)", address.Name, address.Phone))
+	pdf.Ln(7)
+	pdf.MultiCell(0, 7, fmt.Sprintf("%v, %v, %v %v", address.StreetName, address.City.Name, address.Province.Name, address.PostalCode), "", "", false)
+	pdf.Ln(5)
+
+	itemM := make(map[uint][]*entity.OrderItem)
+	for _, item := range items {
+		itemM[item.ShipmentId] = append(itemM[item.ShipmentId], item)
+	}
+	for _, shipment := range order.Shipments {
+		pharmacyName := ""
+		if shipment.Pharmacy != nil {
+			pharmacyName = shipment.Pharmacy.Name
+		}
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 7, fmt.Sprintf("Apotek: %v", pharmacyName))
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "B", 11)
+		pdf.CellFormat(95, 7, "Produk", "1", 0, "", false, 0, "")
+		pdf.CellFormat(25, 7, "Jumlah", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(70, 7, "Subtotal", "1", 1, "R", false, 0, "")
+		pdf.SetFont("Arial", "", 11)
+		for _, item := range itemM[shipment.Id] {
+			name := ""
+			if item.PharmacyProduct.Product != nil {
+				name = item.PharmacyProduct.Product.Name
+			}
+			pdf.CellFormat(95, 7, name, "1", 0, "", false, 0, "")
+			pdf.CellFormat(25, 7, fmt.Sprintf("%d", item.Quantity), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(70, 7, fmt.Sprintf("Rp %v", item.SubTotal.StringFixed(2)), "1", 1, "R", false, 0, "")
+		}
+		pdf.CellFormat(120, 7, fmt.Sprintf("Pengiriman: %v (%v)", shipment.ShippingName, shipment.ShippingEta), "1", 0, "", false, 0, "")
+		pdf.CellFormat(70, 7, fmt.Sprintf("Rp %v", shipment.ShippingPrice.StringFixed(2)), "1", 1, "R", false, 0, "")
+		pdf.Ln(5)
+	}
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.CellFormat(120, 7, "Total Harga Produk", "", 0, "", false, 0, "")
+	pdf.CellFormat(70, 7, fmt.Sprintf("Rp %v", order.TotalPayment.Sub(order.ShippingPrice).Add(order.Discount).Add(order.ShippingDiscount).StringFixed(2)), "", 1, "R", false, 0, "")
+	pdf.CellFormat(120, 7, "Total Ongkos Kirim", "", 0, "", false, 0, "")
+	pdf.CellFormat(70, 7, fmt.Sprintf("Rp %v", order.ShippingPrice.StringFixed(2)), "", 1, "R", false, 0, "")
+	if order.Discount.IsPositive() {
+		pdf.CellFormat(120, 7, fmt.Sprintf("Diskon Voucher (%s)", order.VoucherCode), "", 0, "", false, 0, "")
+		pdf.CellFormat(70, 7, fmt.Sprintf("- Rp %v", order.Discount.StringFixed(2)), "", 1, "R", false, 0, "")
+	}
+	if order.ShippingDiscount.IsPositive() {
+		pdf.CellFormat(120, 7, fmt.Sprintf("Diskon Ongkos Kirim (%s)", order.VoucherCode), "", 0, "", false, 0, "")
+		pdf.CellFormat(70, 7, fmt.Sprintf("- Rp %v", order.ShippingDiscount.StringFixed(2)), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(120, 9, "Total Pembayaran", "T", 0, "", false, 0, "")
+	pdf.CellFormat(70, 9, fmt.Sprintf("Rp %v", order.TotalPayment.StringFixed(2)), "T", 1, "R", false, 0, "")
+
+	err = pdf.Output(&result)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(&result)
+	return reader, nil
+}
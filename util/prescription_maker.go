@@ -12,7 +12,7 @@ import (
 	"github.com/jung-kurt/gofpdf"
 )
 
-func CreatePrescriptionLeave(Prescription string, user *entity.Profile, doctor *entity.DoctorProfile) (io.Reader, error) {
+func CreatePrescriptionLeave(items []*entity.PrescriptionItem, user *entity.Profile, doctor *entity.DoctorProfile) (io.Reader, error) {
 	var result bytes.Buffer
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
@@ -47,8 +47,31 @@ func CreatePrescriptionLeave(Prescription string, user *entity.Profile, doctor *
 	pdf.Cell(0, 10, "Resep Obat: ")
 	pdf.Ln(10)
 
-	pdf.SetFont("Arial", "", 15)
-	pdf.Cell(0, 10, Prescription)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(10, 8, "No", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(60, 8, "Obat", "1", 0, "", false, 0, "")
+	pdf.CellFormat(15, 8, "Jumlah", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(35, 8, "Dosis", "1", 0, "", false, 0, "")
+	pdf.CellFormat(35, 8, "Frekuensi", "1", 0, "", false, 0, "")
+	pdf.CellFormat(35, 8, "Durasi", "1", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 12)
+	for i, item := range items {
+		var productName string
+		if item.Product != nil {
+			productName = item.Product.Name
+		}
+		pdf.CellFormat(10, 8, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(60, 8, productName, "1", 0, "", false, 0, "")
+		pdf.CellFormat(15, 8, fmt.Sprintf("%d", item.Quantity), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(35, 8, item.Dosage, "1", 0, "", false, 0, "")
+		pdf.CellFormat(35, 8, item.Frequency, "1", 0, "", false, 0, "")
+		pdf.CellFormat(35, 8, item.Duration, "1", 1, "", false, 0, "")
+		if item.Notes != "" {
+			pdf.SetFont("Arial", "I", 11)
+			pdf.MultiCell(190, 7, fmt.Sprintf("Catatan: %v", item.Notes), "1", "", false)
+			pdf.SetFont("Arial", "", 12)
+		}
+	}
 	pdf.Ln(10)
 
 	err = pdf.Output(&result)
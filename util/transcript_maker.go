@@ -0,0 +1,130 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TigorLazuardi/tanggal"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/night1010/everhealth/entity"
+)
+
+const transcriptThumbnailWidth = 50
+
+func CreateTranscript(telemedicine *entity.Telemedicine, chats []*entity.Chat, images map[uint][]byte) (io.Reader, error) {
+	var result bytes.Buffer
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 15)
+	pdf.Image("./img/everhealth.png", 11, 10, 80, 0, false, "", 0, "")
+	pdf.Cell(0, 30, "-----------------------------------------------------------------------------------------------------------")
+	pdf.Ln(20)
+
+	format := []tanggal.Format{
+		tanggal.Hari,
+		tanggal.NamaBulan,
+		tanggal.Tahun,
+	}
+	orderedAt, err := tanggal.Papar(telemedicine.OrderedAt, "", tanggal.WIB)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf.SetFont("Arial", "B", 20)
+	pdf.Cell(0, 10, "Transkrip Konsultasi")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(0, 7, fmt.Sprintf("Nomor Konsultasi: %v", telemedicine.Id))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, fmt.Sprintf("Tanggal Konsultasi: %v", orderedAt.Format(" ", format)))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, tr(fmt.Sprintf("Dokter: Dr. %v", telemedicine.Doctor.Profile.Name)))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, tr(fmt.Sprintf("Pasien: %v", telemedicine.Profile.Name)))
+	pdf.Ln(12)
+
+	names := map[uint]string{
+		telemedicine.DoctorId:  "Dr. " + telemedicine.Doctor.Profile.Name,
+		telemedicine.ProfileId: telemedicine.Profile.Name,
+	}
+
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 7, "Riwayat Percakapan")
+	pdf.Ln(9)
+
+	for _, chat := range chats {
+		pdf.SetFont("Arial", "B", 11)
+		pdf.Cell(0, 6, tr(fmt.Sprintf("[%v] %v", chat.ChatTime.Format("02/01/2006 15:04"), names[chat.UserId])))
+		pdf.Ln(6)
+
+		pdf.SetFont("Arial", "", 11)
+		switch chat.MessageType {
+		case entity.MessageTypeImage:
+			if !transcriptThumbnail(pdf, chat, images[chat.Id]) {
+				transcriptLink(pdf, "Lampiran gambar", chat.Message)
+			}
+		case entity.MessageTypePdf:
+			transcriptLink(pdf, "Lampiran PDF", chat.Message)
+		default:
+			pdf.MultiCell(0, 6, tr(chat.Message), "", "", false)
+		}
+		pdf.Ln(3)
+	}
+
+	if telemedicine.PrescriptionPdf != "" || telemedicine.SickLeavePdf != "" {
+		pdf.Ln(5)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 7, "Dokumen")
+		pdf.Ln(9)
+		pdf.SetFont("Arial", "", 11)
+		if telemedicine.PrescriptionPdf != "" {
+			transcriptLink(pdf, "Resep Dokter", telemedicine.PrescriptionPdf)
+		}
+		if telemedicine.SickLeavePdf != "" {
+			transcriptLink(pdf, "Surat Sakit", telemedicine.SickLeavePdf)
+		}
+	}
+
+	err = pdf.Output(&result)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(&result)
+	return reader, nil
+}
+
+func transcriptThumbnail(pdf *gofpdf.Fpdf, chat *entity.Chat, image []byte) bool {
+	if len(image) == 0 {
+		return false
+	}
+	var imageType string
+	switch http.DetectContentType(image) {
+	case "image/png":
+		imageType = "PNG"
+	case "image/jpeg":
+		imageType = "JPG"
+	default:
+		return false
+	}
+	name := fmt.Sprintf("chat-%d", chat.Id)
+	options := gofpdf.ImageOptions{ImageType: imageType}
+	pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(image))
+	if !pdf.Ok() {
+		pdf.ClearError()
+		return false
+	}
+	pdf.ImageOptions(name, pdf.GetX(), pdf.GetY(), transcriptThumbnailWidth, 0, true, options, 0, chat.Message)
+	return true
+}
+
+func transcriptLink(pdf *gofpdf.Fpdf, text string, url string) {
+	pdf.SetTextColor(0, 0, 255)
+	pdf.WriteLinkString(6, text, url)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(6)
+}